@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const addUsage = `Usage: zebracal add SUMMARY [--date YYYY-MM-DD] [--from HH:MM] [--to HH:MM] [--calendar NAME]
+       zebracal add --nl "dentist tomorrow 3pm" [--calendar NAME]`
+
+// addOptions are the flags of the add command
+type addOptions struct {
+	date, from, to, calendar, location, description, nl string
+}
+
+// addFlags returns the flags of the add command, read into opts
+func addFlags(opts *addOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, addUsage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.date, "date", "", "Day of the event, `YYYY-MM-DD` (default today)")
+	fs.StringVar(&opts.from, "from", "", "Start time, `HH:MM`")
+	fs.StringVar(&opts.to, "to", "", "End time, `HH:MM` (default an hour after --from)")
+	fs.StringVar(&opts.calendar, "calendar", "", "Calendar to add to (default the first writable one)")
+	fs.StringVar(&opts.location, "location", "", "Location of the event")
+	fs.StringVar(&opts.description, "description", "", "Description of the event")
+	fs.StringVar(&opts.nl, "nl", "", "Describe the event in natural language instead")
+	return fs
+}
+
+// runAddCommand creates an event from the command line and exits, for
+// scripts and launchers. Without times the event lasts all day; without
+// --to it lasts an hour.
+func runAddCommand(args []string) {
+	var opts addOptions
+	fs := addFlags(&opts)
+
+	// The summary may come before the flags
+	var summary []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			break
+		}
+		summary = append(summary, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	config, _ := loadConfig()
+	if config == nil {
+		fmt.Fprintln(os.Stderr, "Error: no config found")
+		os.Exit(1)
+	}
+	setLocale(config.Locale)
+	if err := setupHTTPClient(config.HTTP); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if err := setFloatingTimezone(config.FloatingTimezone); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if err := setDayParts(config.DayParts); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	event, err := addedEvent(strings.Join(summary, " "), opts.nl, opts.date, opts.from, opts.to, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n%s\n", err, addUsage)
+		os.Exit(2)
+	}
+	event.Location = opts.location
+	event.Description = opts.description
+
+	_, calendars, calendarURLs, err := loadAllCalendars(systemClock{}, config.Radicale, nil, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	name, err := writableCalendar(model{calendars: calendars, config: config}.sortedCalendarNames(), calendarURLs, opts.calendar, config.localStoreName())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	event.CalendarName = name
+
+	queued, err := putEventOrQueue(calendarURLs[name], event, config.Radicale)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	when := formatDate(event.Start, "Mon Jan 2") + " " + event.Start.Format("15:04") + "-" + event.End.Format("15:04")
+	if isAllDay(*event) {
+		when = formatDate(event.Start, "Mon Jan 2") + " " + tr("all day")
+	}
+	switch {
+	case queued:
+		fmt.Printf("Queued %q on %s in %s, the server is unreachable\n", event.Summary, when, name)
+	case opts.calendar != "" && !strings.EqualFold(opts.calendar, name):
+		fmt.Printf("Added %q on %s in %s, %s can't be written to\n", event.Summary, when, name, opts.calendar)
+	default:
+		fmt.Printf("Added %q on %s in %s\n", event.Summary, when, name)
+	}
+}
+
+// addedEvent builds the event of the add command, from the natural
+// language description if there is one. Dates are relative to now.
+func addedEvent(summary, nl, date, from, to string, now time.Time) (*Event, error) {
+	if nl != "" {
+		if summary != "" || date != "" || from != "" || to != "" {
+			return nil, fmt.Errorf("--nl can't be combined with a summary, --date, --from or --to")
+		}
+		return parseNaturalLanguage(nl, now)
+	}
+	if summary == "" {
+		return nil, fmt.Errorf("missing summary")
+	}
+
+	day := dayStart(now)
+	if date != "" {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return nil, fmt.Errorf("invalid --date %q (use YYYY-MM-DD)", date)
+		}
+	}
+	if from == "" && to != "" {
+		return nil, fmt.Errorf("--to needs --from")
+	}
+	if from != "" && to == "" {
+		start, err := time.Parse("15:04", from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --from %q (use HH:MM)", from)
+		}
+		to = start.Add(time.Hour).Format("15:04")
+	}
+	start, end, _, err := formTimes(day.Format("02-01-2006"), from, to, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Event{Summary: summary, Start: start, End: end}, nil
+}
+
+// writableCalendar picks the calendar to add to: the one named (in any
+// case), or the first of names that can be written to. A named calendar
+// that can't be written falls back to the local store, if there is one.
+func writableCalendar(names []string, calendarURLs map[string]string, name, store string) (string, error) {
+	if name != "" {
+		for _, candidate := range names {
+			if !strings.EqualFold(candidate, name) {
+				continue
+			}
+			if calendarURLs[candidate] == "" && calendarURLs[store] != "" {
+				return store, nil
+			}
+			if calendarURLs[candidate] == "" {
+				return "", fmt.Errorf("calendar %q is read-only", candidate)
+			}
+			return candidate, nil
+		}
+		return "", fmt.Errorf("unknown calendar %q", name)
+	}
+	for _, candidate := range names {
+		if calendarURLs[candidate] != "" {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no calendar can be written to")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// agendaWriter writes today's agenda to a file for widgets that read a
+// static file (--write-agenda)
+type agendaWriter struct {
+	path    string
+	json    bool
+	config  *Config
+	clock   Clock
+	include []string // --calendar
+	exclude []string // --exclude-calendar
+}
+
+// run writes the agenda once, or with watch keeps rewriting it after each
+// refresh interval and at midnight
+func (w agendaWriter) run(watch bool) error {
+	var radicaleConfig *RadicaleConfig
+	interval := defaultRefreshInterval
+	if w.config != nil {
+		radicaleConfig = w.config.Radicale
+		if w.config.RefreshMinutes > 0 {
+			interval = time.Duration(w.config.RefreshMinutes) * time.Minute
+		}
+	}
+
+	for {
+		events, _, _, err := loadAllCalendars(w.clock, radicaleConfig, nil, nil)
+		if err == nil {
+			err = w.write(filterEventsByCalendar(events, w.include, w.exclude), w.clock.Now())
+		}
+		if !watch {
+			return err
+		}
+		if err != nil {
+			// Keep the last agenda until the next try
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		now := w.clock.Now()
+		time.Sleep(min(interval, dayStart(now).AddDate(0, 0, 1).Sub(now)))
+	}
+}
+
+// write replaces the file with the agenda of now's day
+func (w agendaWriter) write(events []Event, now time.Time) error {
+	var today []Event
+	for _, event := range events {
+		if onDay(event, now) {
+			today = append(today, event)
+		}
+	}
+	sort.SliceStable(today, func(i, j int) bool {
+		if isPinned(today[i]) != isPinned(today[j]) {
+			return isPinned(today[i])
+		}
+		return today[i].Start.Before(today[j].Start)
+	})
+
+	var data string
+	if w.json {
+		out, err := renderEventsJSON(today)
+		if err != nil {
+			return err
+		}
+		data = out + "\n"
+	} else {
+		data = renderPlainAgenda(today, now)
+	}
+	return writeFileAtomic(w.path, []byte(data))
+}
+
+// renderPlainAgenda renders a day's events without colors, one per line
+func renderPlainAgenda(events []Event, day time.Time) string {
+	var b strings.Builder
+	b.WriteString(formatDate(day, "Mon Jan 2") + "\n")
+	if len(events) == 0 {
+		b.WriteString(tr("No events scheduled for this day") + "\n")
+	}
+	whenWidth := max(13, len([]rune(tr("all day"))))
+	for _, event := range events {
+		when := event.Start.Format("15:04") + " - " + event.End.Format("15:04")
+		if isAllDay(event) {
+			when = tr("all day")
+		}
+		title := event.Summary
+		if isPinned(event) {
+			title = "★ " + title
+		}
+		if location := strings.TrimSpace(event.Location); location != "" {
+			title += " · " + location
+		}
+		fmt.Fprintf(&b, "%-*s  %s\n", whenWidth, when, title)
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	alertCheckInterval = 30 * time.Second
+	alertFlashInterval = 500 * time.Millisecond
+	alertFlashes       = 12 // Toggles before the events stop flashing
+)
+
+// alertCheckMsg brings the reminders dismissed elsewhere, read from disk in
+// the background, to check the alerts against. Ticks schedule the next
+// check.
+type alertCheckMsg struct {
+	dismissed map[string]time.Time
+	tick      bool
+}
+
+type alertFlashMsg struct{}
+
+// startAlert flashes the events about to start
+type startAlert struct {
+	keys    map[string]bool // By eventKey
+	flashes int             // Toggles left
+	on      bool            // Highlighted in this frame
+}
+
+// alertsEnabled reports whether upcoming events are announced inside the
+// running TUI
+func (m model) alertsEnabled() bool {
+	if m.oneShot {
+		return false
+	}
+	return m.config == nil || m.config.Reminders == nil || m.config.Reminders.InApp == nil || *m.config.Reminders.InApp
+}
+
+func scheduleAlertCheck() tea.Cmd {
+	return tea.Tick(alertCheckInterval, func(time.Time) tea.Msg {
+		return readDismissed(true)
+	})
+}
+
+// checkAlertsCmd checks the alerts right away, e.g. after a load
+func checkAlertsCmd() tea.Msg {
+	return readDismissed(false)
+}
+
+func readDismissed(tick bool) alertCheckMsg {
+	// A missing or broken state dismisses nothing
+	state, _ := loadReminderState()
+	return alertCheckMsg{dismissed: state.Dismissed, tick: tick}
+}
+
+func scheduleAlertFlash() tea.Cmd {
+	return tea.Tick(alertFlashInterval, func(time.Time) tea.Msg {
+		return alertFlashMsg{}
+	})
+}
+
+// checkAlerts announces the events starting within the reminder lead time
+// in the status line, once per occurrence. Reminders dismissed with
+// "zebracal remind" or by the daemon are left out.
+func (m model) checkAlerts(dismissed map[string]time.Time) (model, tea.Cmd) {
+	now := m.clock.Now()
+	lead := reminderLead(m.config)
+
+	var due []Event
+	for _, event := range filterEventsByCalendar(m.events, nil, excludedFromNext(m.config)) {
+		key := eventKey(event)
+		if !m.visible(event) || !busy(event) || m.alerted[key] {
+			continue
+		}
+		if _, ok := dismissed[key]; ok {
+			continue
+		}
+		if event.Start.After(now) && !event.Start.After(now.Add(lead)) {
+			due = append(due, event)
+		}
+	}
+	if len(due) == 0 {
+		return m, nil
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Start.Before(due[j].Start)
+	})
+
+	if m.alerted == nil {
+		m.alerted = make(map[string]bool)
+	}
+	alert := startAlert{keys: make(map[string]bool), flashes: alertFlashes, on: true}
+	if m.alert != nil {
+		// Still flashing, so its tick is already running
+		for key := range m.alert.keys {
+			alert.keys[key] = true
+		}
+	}
+	for _, event := range due {
+		m.alerted[eventKey(event)] = true
+		alert.keys[eventKey(event)] = true
+	}
+
+	first := due[0]
+	minutes := int(first.Start.Sub(now).Minutes()) + 1
+	m.message = fmt.Sprintf(tr("⏰ %s starts in %dm (%s)"), m.eventTitle(first), minutes, first.Start.Format("15:04"))
+	if len(due) > 1 {
+		m.message += " " + fmt.Sprintf(tr("and %d more"), len(due)-1)
+	}
+
+	var cmds []tea.Cmd
+	if m.alert == nil {
+		cmds = append(cmds, scheduleAlertFlash())
+	}
+	m.alert = &alert
+	if m.config != nil && m.config.Reminders != nil && m.config.Reminders.Bell {
+		cmds = append(cmds, ringBell)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// updateAlertFlash toggles the highlight of the alerted events until the
+// flashes run out
+func (m model) updateAlertFlash() (model, tea.Cmd) {
+	if m.alert == nil {
+		return m, nil
+	}
+	alert := *m.alert
+	alert.flashes--
+	if alert.flashes <= 0 {
+		m.alert = nil
+		return m, nil
+	}
+	alert.on = !alert.on
+	m.alert = &alert
+	return m, scheduleAlertFlash()
+}
+
+// flashing reports whether an event is highlighted for its upcoming start
+func (m model) flashing(event Event) bool {
+	return m.alert != nil && m.alert.on && m.alert.keys[eventKey(event)]
+}
+
+// ringBell rings the terminal bell. The BEL character doesn't move the
+// cursor, so writing it past the renderer is safe.
+func ringBell() tea.Msg {
+	os.Stdout.WriteString("\a")
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// anniversaryWords mark yearly events as birthdays or anniversaries by
+// their summary, when the calendar doesn't
+var anniversaryWords = []struct{ word, kind string }{
+	{"birthday", "birthday"},
+	{"geburtstag", "birthday"},
+	{"anniversary", "anniversary"},
+	{"jahrestag", "anniversary"},
+}
+
+// anniversaryLabel counts the years of a birthday or anniversary up to the
+// occurrence: "turns 42" or "10th anniversary". It is "" for other events
+// and when the first year is unknown.
+func anniversaryLabel(event Event) string {
+	if event.Since == 0 {
+		return ""
+	}
+	kind := event.Anniversary
+	summary := strings.ToLower(event.Summary)
+	for _, marker := range anniversaryWords {
+		if kind == "" && strings.Contains(summary, marker.word) {
+			kind = marker.kind
+		}
+	}
+	years := event.Start.Year() - event.Since
+	switch {
+	case years <= 0:
+		return ""
+	case kind == "birthday":
+		return fmt.Sprintf(tr("turns %d"), years)
+	case kind == "anniversary":
+		return fmt.Sprintf(tr("%s anniversary"), ordinalNumber(years))
+	}
+	return ""
+}
+
+// ordinalNumber writes n as an ordinal, "1st" or "12th" in English. The
+// suffixes are translated, to "." in German.
+func ordinalNumber(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + tr(suffix)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bandColumnWidth is the width of one day in the all-day band, matching the
+// cells of the rolling view
+const bandColumnWidth = 12
+
+// spansDays reports whether an event belongs in the all-day band rather
+// than a day's list: all-day events and events running past midnight
+func spansDays(event Event) bool {
+	return isAllDay(event) || eventLastDay(event).After(dayStart(event.Start))
+}
+
+// eventLastDay returns the start of the last day an event covers. An end at
+// midnight doesn't cover the following day.
+func eventLastDay(event Event) time.Time {
+	end := event.End.Add(-time.Nanosecond)
+	if end.Before(event.Start) {
+		end = event.Start
+	}
+	return dayStart(end)
+}
+
+// dayDuration returns how much of an event falls on the given day
+func dayDuration(event Event, day time.Time) time.Duration {
+	from, to := dayStart(day), dayStart(day).AddDate(0, 0, 1)
+	if event.Start.After(from) {
+		from = event.Start
+	}
+	if event.End.Before(to) {
+		to = event.End
+	}
+	return max(0, to.Sub(from))
+}
+
+// onDay reports whether an event covers any of the given day
+func onDay(event Event, day time.Time) bool {
+	return !dayStart(event.Start).After(dayStart(day)) && !eventLastDay(event).Before(dayStart(day))
+}
+
+// timeRange formats an event's times, marking an end on a later day like
+// "22:00 - 01:00 (+1)"
+func timeRange(event Event) string {
+	s := event.Start.Format("15:04") + " - " + event.End.Format("15:04")
+	if days := dayIndex(event.Start, event.End); days > 0 {
+		s += fmt.Sprintf(" (+%d)", days)
+	}
+	return s
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dayIndex counts the days from from to t, ignoring DST changes
+func dayIndex(from, t time.Time) int {
+	return int(dayStart(t).Sub(dayStart(from)).Hours()/24 + 0.5)
+}
+
+// withoutSpanning drops the events shown in the all-day band
+func withoutSpanning(events []Event) []Event {
+	var timed []Event
+	for _, event := range events {
+		if !spansDays(event) {
+			timed = append(timed, event)
+		}
+	}
+	return timed
+}
+
+// bandEvents returns the all-day and multi-day events overlapping the given
+// days, earliest and then longest first
+func (m model) bandEvents(from time.Time, days int) []Event {
+	to := dayStart(from).AddDate(0, 0, days)
+	var band []Event
+	for _, event := range m.events {
+		if m.visible(event) && spansDays(event) && event.Start.Before(to) && !eventLastDay(event).Before(dayStart(from)) {
+			band = append(band, event)
+		}
+	}
+	sort.SliceStable(band, func(i, j int) bool {
+		if !band[i].Start.Equal(band[j].Start) {
+			return band[i].Start.Before(band[j].Start)
+		}
+		return band[i].End.After(band[j].End)
+	})
+	return band
+}
+
+// renderBand draws the all-day and multi-day events of the given days as
+// colored bars under the days they cover, packing events that don't overlap
+// into the same line. It returns "" if there are none.
+func (m model) renderBand(from time.Time, days, colWidth int) string {
+	type bar struct {
+		first, last int
+		event       Event
+	}
+	var lanes [][]bar
+	for _, event := range m.bandEvents(from, days) {
+		b := bar{
+			first: max(0, dayIndex(from, event.Start)),
+			last:  min(days-1, dayIndex(from, eventLastDay(event))),
+			event: event,
+		}
+		placed := false
+		for i, lane := range lanes {
+			if lane[len(lane)-1].last < b.first {
+				lanes[i] = append(lane, b)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			lanes = append(lanes, []bar{b})
+		}
+	}
+
+	var lines []string
+	for _, lane := range lanes {
+		var line strings.Builder
+		col := 0
+		for _, b := range lane {
+			line.WriteString(strings.Repeat(" ", (b.first-col)*colWidth))
+			width := (b.last - b.first + 1) * colWidth
+			barStyle := lipgloss.NewStyle().
+				Background(eventColor(b.event)).
+				Foreground(lipgloss.Color("0")).
+				Width(width)
+			line.WriteString(barStyle.Render(truncate("▌"+m.eventTitle(b.event), width)))
+			col = b.last + 1
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// weekBandColumnWidth fits the weekly view's band into the terminal
+func (m model) weekBandColumnWidth() int {
+	if m.width <= 0 {
+		return bandColumnWidth
+	}
+	return max(4, min(bandColumnWidth, (m.width-1)/7))
+}
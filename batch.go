@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+const (
+	maxConcurrentWrites = 4
+	writeInterval       = 100 * time.Millisecond // At most 10 writes started per second
+)
+
+// createBatch is a running batch of event writes, e.g. a recurring series
+type createBatch struct {
+	total     int
+	done      int
+	cancel    chan struct{}
+	cancelled bool
+	fallback  string // Calendar the events were meant for, if it can't be written and they go to the local store
+}
+
+// saveNewEvents writes new events of one calendar to Radicale or its vdir
+// in the background if it's writable, otherwise to the local store. Without
+// one they are only kept in memory, which the message says.
+func (m model) saveNewEvents(events []*Event) (model, tea.Cmd) {
+	if len(events) == 0 {
+		return m, nil
+	}
+	name := events[0].CalendarName
+	calendarURL := m.calendarURLs[name]
+	fallback := ""
+	if store := m.config.localStoreName(); calendarURL == "" && m.calendarURLs[store] != "" {
+		for _, event := range events {
+			event.CalendarName = store
+		}
+		calendarURL, fallback = m.calendarURLs[store], name
+	}
+	if calendarURL != "" {
+		batch, cmd := startCreateBatch(calendarURL, events, m.radicaleConfig)
+		batch.fallback = fallback
+		m.creating = batch
+		m.message = ""
+		return m, cmd
+	}
+
+	for _, event := range events {
+		m.markDirty(*event)
+		m.events = append(m.events, occurrences(*event, m.clock.Now())...)
+	}
+	if len(events) == 1 {
+		m.message = fmt.Sprintf("%s can't be written to, event only kept until quitting (see local_store)", name)
+	} else {
+		m.message = fmt.Sprintf("%s can't be written to, %d events only kept until quitting (see local_store)", name, len(events))
+	}
+	return m, nil
+}
+
+// occurrences expands a new recurring event the way loaded ones are, so it
+// shows up on every day it repeats; single events are returned as is
+func occurrences(event Event, now time.Time) []Event {
+	if event.RRule == "" {
+		return []Event{event}
+	}
+	var expanded []Event
+	for _, occ := range ical.Expand(event.Start, event.End, event.RRule, now.AddDate(1, 0, 0), now) {
+		occurrence := event
+		occurrence.Start, occurrence.End = occ.Start, occ.End
+		expanded = append(expanded, occurrence)
+	}
+	return expanded
+}
+
+// startCreateBatch writes events to the server concurrently, with a limit on
+// parallel and per-second writes. Progress is streamed as createProgressMsgs,
+// followed by a createDoneMsg.
+func startCreateBatch(calendarURL string, events []*Event, config *RadicaleConfig) (*createBatch, tea.Cmd) {
+	batch := &createBatch{total: len(events), cancel: make(chan struct{})}
+	updates := make(chan tea.Msg)
+
+	go func() {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var result createDoneMsg
+		done := 0
+
+		slots := make(chan struct{}, maxConcurrentWrites)
+		ticker := time.NewTicker(writeInterval)
+		defer ticker.Stop()
+
+	writes:
+		for _, event := range events {
+			select {
+			case <-batch.cancel:
+				result.cancelled = true
+				break writes
+			case <-ticker.C:
+			}
+			slots <- struct{}{}
+
+			wg.Add(1)
+			go func(event *Event) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				queued, err := putEventOrQueue(calendarURL, event, config)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					result.failed++
+					result.err = err
+				case queued:
+					result.queued = append(result.queued, *event)
+				default:
+					result.created = append(result.created, *event)
+				}
+				done++
+				progress := createProgressMsg{done: done, updates: updates}
+				mu.Unlock()
+
+				updates <- progress
+			}(event)
+		}
+
+		wg.Wait()
+		updates <- result
+		close(updates)
+	}()
+
+	return batch, waitForUpdate(updates)
+}
+
+// stop cancels the writes that haven't started yet
+func (b *createBatch) stop() {
+	if !b.cancelled {
+		b.cancelled = true
+		close(b.cancel)
+	}
+}
+
+func (m model) applyCreateProgress(msg createProgressMsg) (model, tea.Cmd) {
+	if m.creating != nil {
+		m.creating.done = msg.done
+	}
+	return m, waitForUpdate(msg.updates)
+}
+
+// applyCreateResults adds the written (or queued) events once a batch finishes
+func (m model) applyCreateResults(msg createDoneMsg) model {
+	total, fallback := 0, ""
+	if m.creating != nil {
+		total, fallback = m.creating.total, m.creating.fallback
+	}
+	m.creating = nil
+
+	for _, event := range msg.created {
+		m.events = append(m.events, occurrences(event, m.clock.Now())...)
+	}
+	for _, event := range msg.queued {
+		m.markDirty(event)
+		m.events = append(m.events, occurrences(event, m.clock.Now())...)
+	}
+	m.pendingCount = countPendingOps()
+
+	saved := len(msg.created) + len(msg.queued)
+	switch {
+	case msg.cancelled:
+		m.message = fmt.Sprintf("Cancelled after creating %d of %d events", saved, total)
+	case msg.failed > 0:
+		m.message = fmt.Sprintf("Created %d events, %d failed: %v", saved, msg.failed, msg.err)
+	case len(msg.queued) > 0:
+		m.message = fmt.Sprintf("Server unreachable, %d changes queued for sync", len(msg.queued))
+	case fallback != "" && saved == 1:
+		m.message = fmt.Sprintf("%s can't be written to, event saved to %s", fallback, m.config.localStoreName())
+	case fallback != "":
+		m.message = fmt.Sprintf("%s can't be written to, %d events saved to %s", fallback, saved, m.config.localStoreName())
+	case saved == 1:
+		m.message = "Event created successfully!"
+	default:
+		m.message = fmt.Sprintf("%d events created successfully!", saved)
+	}
+	return m
+}
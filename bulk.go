@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+// bulkAction is a bulk operation on the selected events awaiting confirmation
+type bulkAction struct {
+	kind           BulkActionKind
+	events         []Event
+	targetCalendar string // For BulkMove
+}
+
+// bulkResult is the outcome of a bulk action for a single event.
+// event holds the updated event (unchanged for deletes).
+type bulkResult struct {
+	key    string
+	event  Event
+	synced bool // Whether the change reached the server
+	err    error
+}
+
+func (m model) selectedEvents() []Event {
+	var events []Event
+	for _, event := range m.events {
+		if m.selected[eventKey(event)] {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// startBulkAction asks for confirmation of a bulk action on the current selection
+func (m model) startBulkAction(kind BulkActionKind) model {
+	events := m.selectedEvents()
+	if len(events) == 0 {
+		m.message = "No events selected (space: select, V: select day)"
+		return m
+	}
+
+	action := &bulkAction{kind: kind, events: events}
+	if kind == BulkMove {
+		// Default to the first calendar that differs from the first selected event's
+		for _, name := range m.moveTargets() {
+			if name != events[0].CalendarName {
+				action.targetCalendar = name
+				break
+			}
+		}
+		if action.targetCalendar == "" {
+			m.message = "No other writable calendar to move to"
+			return m
+		}
+	}
+
+	m.pendingBulk = action
+	m.message = ""
+	return m
+}
+
+// moveTargets returns the calendars events can be moved to: those on a
+// server, as a moved event is deleted from its own calendar there
+func (m model) moveTargets() []string {
+	var names []string
+	for _, name := range m.sortedCalendarNames() {
+		if m.calendarURLs[name] != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (m model) handleBulkConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		action := m.pendingBulk
+		m.pendingBulk = nil
+		m.message = "Applying changes..."
+		return m, runBulkAction(*action, m.calendarURLs, m.radicaleConfig)
+	case "tab":
+		if m.pendingBulk.kind == BulkMove {
+			calNames := m.moveTargets()
+			for i, name := range calNames {
+				if name == m.pendingBulk.targetCalendar {
+					m.pendingBulk.targetCalendar = calNames[(i+1)%len(calNames)]
+					break
+				}
+			}
+		}
+	case "n", "esc", "q":
+		m.pendingBulk = nil
+		m.message = "Cancelled"
+	}
+	return m, nil
+}
+
+func (a bulkAction) prompt() string {
+	count := len(a.events)
+	noun := "events"
+	if count == 1 {
+		noun = "event"
+	}
+
+	switch a.kind {
+	case BulkDelete:
+		// Deleting an occurrence deletes its whole series on the server
+		series := make(map[string]bool)
+		for _, event := range a.events {
+			if event.RRule != "" {
+				series[event.CalendarName+"/"+event.UID] = true
+			}
+		}
+		if len(series) > 0 {
+			return fmt.Sprintf("Delete %d %s, including %d whole recurring series? (y/n)", count, noun, len(series))
+		}
+		return fmt.Sprintf("Delete %d %s? (y/n)", count, noun)
+	case BulkMove:
+		return fmt.Sprintf("Move %d %s to %s? (y/n, tab: change calendar)", count, noun, a.targetCalendar)
+	case BulkShiftForward:
+		return fmt.Sprintf("Shift %d %s by +1 day? (y/n)", count, noun)
+	case BulkShiftBack:
+		return fmt.Sprintf("Shift %d %s by -1 day? (y/n)", count, noun)
+	case BulkExport:
+		return fmt.Sprintf("Export %d %s to ICS? (y/n)", count, noun)
+	}
+	return ""
+}
+
+// runBulkAction performs all server requests for a bulk action in one batch
+// and reports the per-event results in a single message
+func runBulkAction(action bulkAction, calendarURLs map[string]string, config *RadicaleConfig) tea.Cmd {
+	return func() tea.Msg {
+		done := bulkDoneMsg{kind: action.kind}
+
+		if action.kind == BulkExport {
+			filename := fmt.Sprintf("zebracal-selection-%s.ics", time.Now().Format("20060102-150405"))
+			if err := os.WriteFile(filename, []byte(ical.Build(action.events)), 0644); err != nil {
+				done.err = err
+				return done
+			}
+			for _, event := range action.events {
+				done.results = append(done.results, bulkResult{key: eventKey(event), event: event})
+			}
+			done.detail = "to " + filename
+			return done
+		}
+
+		// Recurring occurrences share one server resource, so the series is
+		// deleted once and never shifted or moved as a single occurrence
+		deletedSeries := make(map[string]bool)
+
+		for _, event := range action.events {
+			result := bulkResult{key: eventKey(event), event: event}
+			sourceURL := calendarURLs[event.CalendarName]
+			remote := sourceURL != "" && event.UID != ""
+
+			switch action.kind {
+			case BulkDelete:
+				seriesKey := event.CalendarName + "/" + event.UID
+				if event.RRule != "" && deletedSeries[seriesKey] {
+					break
+				}
+				if remote {
+					queued, err := deleteEventOrQueue(sourceURL, event, config)
+					result.err = err
+					result.synced = !queued
+				}
+				deletedSeries[seriesKey] = true
+
+			case BulkMove, BulkShiftForward, BulkShiftBack:
+				if event.RRule != "" {
+					done.skipped++
+					continue
+				}
+
+				updated := event
+				updated.Sequence++
+				updated.LastModified = time.Now()
+				switch action.kind {
+				case BulkMove:
+					updated.CalendarName = action.targetCalendar
+				case BulkShiftForward:
+					updated.Start = event.Start.AddDate(0, 0, 1)
+					updated.End = event.End.AddDate(0, 0, 1)
+				case BulkShiftBack:
+					updated.Start = event.Start.AddDate(0, 0, -1)
+					updated.End = event.End.AddDate(0, 0, -1)
+				}
+
+				targetURL := calendarURLs[updated.CalendarName]
+				if action.kind == BulkMove && targetURL == "" {
+					result.err = fmt.Errorf("%s can't be written to", updated.CalendarName)
+				} else if targetURL != "" {
+					queued, err := putEventOrQueue(targetURL, &updated, config)
+					result.err = err
+					result.synced = !queued
+				}
+				if result.err == nil && action.kind == BulkMove && remote {
+					_, result.err = deleteEventOrQueue(sourceURL, event, config)
+				}
+				result.event = updated
+			}
+
+			done.results = append(done.results, result)
+		}
+
+		return done
+	}
+}
+
+// applyBulkResults updates the local event list with the successful results
+func (m model) applyBulkResults(msg bulkDoneMsg) model {
+	if msg.err != nil {
+		m.message = fmt.Sprintf("Error: %v", msg.err)
+		return m
+	}
+
+	results := make(map[string]bulkResult)
+	deletedSeries := make(map[string]bool)
+	failed := 0
+	for _, result := range msg.results {
+		if result.err != nil {
+			failed++
+			continue
+		}
+		results[result.key] = result
+		if msg.kind == BulkDelete && result.event.RRule != "" {
+			deletedSeries[result.event.CalendarName+"/"+result.event.UID] = true
+		}
+	}
+
+	if msg.kind != BulkExport {
+		var events []Event
+		for _, event := range m.events {
+			if deletedSeries[event.CalendarName+"/"+event.UID] {
+				continue
+			}
+			result, ok := results[eventKey(event)]
+			if !ok {
+				events = append(events, event)
+				continue
+			}
+			if msg.kind == BulkDelete {
+				continue
+			}
+			if !result.synced {
+				m.markDirty(event)
+			}
+			updated := result.event
+			events = append(events, updated)
+		}
+		m.events = events
+	}
+
+	m.selected = make(map[string]bool)
+	m.pendingBulk = nil
+	m.pendingCount = countPendingOps()
+
+	verb := map[BulkActionKind]string{
+		BulkDelete:       "Deleted",
+		BulkMove:         "Moved",
+		BulkShiftForward: "Shifted",
+		BulkShiftBack:    "Shifted",
+		BulkExport:       "Exported",
+	}[msg.kind]
+	m.message = fmt.Sprintf("%s %d events", verb, len(results))
+	if msg.detail != "" {
+		m.message += " " + msg.detail
+	}
+	if msg.skipped > 0 {
+		m.message += fmt.Sprintf(", skipped %d recurring", msg.skipped)
+	}
+	if failed > 0 {
+		m.message += fmt.Sprintf(", %d failed", failed)
+	}
+	return m
+}
@@ -1,125 +1,27 @@
 package main
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	ics "github.com/arran4/golang-ical"
 	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/ical"
 )
 
-func loadICSFromReader(reader io.Reader, calendarName string, color lipgloss.Color) ([]Event, error) {
-	cal, err := ics.ParseCalendar(reader)
+func loadICSFromURL(cal CalendarConfig, opts ical.Options, onRetry caldav.RetryFunc) ([]Event, error) {
+	req, err := http.NewRequest("GET", cal.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var events []Event
-	now := time.Now()
-	// Expand recurring events up to 1 year in the future
-	maxDate := now.AddDate(1, 0, 0)
-
-	for _, event := range cal.Events() {
-		start, err := event.GetStartAt()
-		if err != nil {
-			continue
-		}
-
-		end, err := event.GetEndAt()
-		if err != nil {
-			end = start.Add(time.Hour)
-		}
-
-		summary := ""
-		if summaryProp := event.GetProperty(ics.ComponentPropertySummary); summaryProp != nil {
-			summary = summaryProp.Value
-		}
-
-		description := ""
-		if descProp := event.GetProperty(ics.ComponentPropertyDescription); descProp != nil {
-			description = descProp.Value
-		}
-
-		uid := ""
-		if uidProp := event.GetProperty(ics.ComponentPropertyUniqueId); uidProp != nil {
-			uid = uidProp.Value
-		}
-
-		if summary == "" {
-			summary = "(No title)"
-		}
-
-		// Check for RRULE (recurrence rule) - try multiple property access methods
-		var rruleValue string
-		
-		// First, try accessing all properties to find RRULE (most reliable)
-		for _, prop := range event.Properties {
-			// IANAToken is a field, not a method
-			if strings.ToUpper(prop.IANAToken) == "RRULE" {
-				rruleValue = prop.Value
-				break
-			}
-		}
-		
-		// If not found in Properties, try GetProperty with extended
-		if rruleValue == "" {
-			rruleProp := event.GetProperty(ics.ComponentPropertyExtended("RRULE"))
-			if rruleProp != nil {
-				rruleValue = rruleProp.Value
-			} else {
-				// Try with lowercase
-				rruleProp = event.GetProperty(ics.ComponentPropertyExtended("rrule"))
-				if rruleProp != nil {
-					rruleValue = rruleProp.Value
-				}
-			}
-		}
-
-		if rruleValue != "" {
-			// Parse RRULE and expand occurrences
-			occurrences := expandRecurringEvent(start, end, rruleValue, maxDate, now)
-			for _, occ := range occurrences {
-				events = append(events, Event{
-					Summary:       summary,
-					Start:         occ.Start,
-					End:           occ.End,
-					Description:   description,
-					CalendarName:  calendarName,
-					CalendarColor: color,
-					UID:           uid,
-				})
-			}
-		} else {
-			// Single event (non-recurring) - include even if in the past (for today's view)
-			events = append(events, Event{
-				Summary:       summary,
-				Start:         start,
-				End:           end,
-				Description:   description,
-				CalendarName:  calendarName,
-				CalendarColor: color,
-				UID:           uid,
-			})
-		}
-	}
-
-	return events, nil
-}
-
-func loadICSFromURL(url string, calendarName string, color lipgloss.Color) ([]Event, error) {
-	resp, err := http.Get(url)
+	resp, err := calendarClient(nil, cal).Do(req, onRetry)
 	if err != nil {
 		return nil, err
 	}
@@ -129,528 +31,446 @@ func loadICSFromURL(url string, calendarName string, color lipgloss.Color) ([]Ev
 		return nil, fmt.Errorf("failed to fetch calendar: %s", resp.Status)
 	}
 
-	return loadICSFromReader(resp.Body, calendarName, color)
+	return ical.ParseWith(resp.Body, cal.Name, opts)
 }
 
-func loadICSFromFile(filename string, calendarName string, color lipgloss.Color) ([]Event, error) {
+func loadICSFromFile(filename string, calendarName string, opts ical.Options) ([]Event, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return loadICSFromReader(file, calendarName, color)
+	return ical.ParseWith(file, calendarName, opts)
 }
 
-// Load calendars from Radicale server
-func loadCalendarsFromRadicale(config *RadicaleConfig) ([]CalDAVCalendar, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	// Normalize server URL (remove trailing slash)
-	serverURL := strings.TrimSuffix(config.ServerURL, "/")
-
-	// Radicale typically uses /username/ as the user collection path
-	// Try username-based path first, then root as fallback
-	userPath := "/" + config.Username + "/"
-	pathsToTry := []string{userPath, "/"}
+// radicaleProvider holds the calendars found on the Radicale server
+type radicaleProvider struct {
+	config  *Config
+	account *RadicaleConfig
+}
 
-	var calendars []CalDAVCalendar
-	var lastErr error
+func newRadicaleProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &radicaleProvider{config: config, account: config.Radicale}
+}
 
-	for _, basePath := range pathsToTry {
-		// Discover calendars using PROPFIND
-		fullURL := serverURL + basePath
-		req, err := http.NewRequest("PROPFIND", fullURL, nil)
+// Discover lists the server's calendars, with the limits of the "radicale"
+// entry of the same name
+func (p *radicaleProvider) Discover(now time.Time, onRetry caldav.RetryFunc) ([]CalendarConfig, error) {
+	if p.account == nil || p.account.ServerURL == "" {
+		return nil, fmt.Errorf("no Radicale server configured")
+	}
+	found, ok := cachedDiscovery(p.account, now)
+	if !ok {
+		var err error
+		found, err = newCalDAVClient(p.account).Discover(p.account.ServerURL, onRetry)
 		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		// Set authentication
-		auth := base64.StdEncoding.EncodeToString([]byte(config.Username + ":" + config.Password))
-		req.Header.Set("Authorization", "Basic "+auth)
-		req.Header.Set("Content-Type", "application/xml")
-		req.Header.Set("Depth", "1")
-
-		// Create PROPFIND request body
-		propfind := propfindRequest{
-			Prop: prop{
-				DisplayName: "",
-			},
-		}
-
-		var buf bytes.Buffer
-		buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-		enc := xml.NewEncoder(&buf)
-		enc.Indent("", "  ")
-		if err := enc.Encode(propfind); err != nil {
-			lastErr = err
-			continue
+			return nil, fmt.Errorf("failed to connect to Radicale server: %v", err)
 		}
+		// Only costs the next startup a discovery if it fails
+		saveDiscovery(p.account, found, now)
+	}
 
-		req.Body = io.NopCloser(&buf)
-		req.ContentLength = int64(buf.Len())
+	var cals []CalendarConfig
+	for _, cal := range found {
+		entry := radicaleLimits(p.config, cal.DisplayName)
+		entry.Name, entry.URL, entry.Type = cal.DisplayName, cal.URL, "radicale"
+		cals = append(cals, entry)
+	}
+	return cals, nil
+}
 
-		resp, err := client.Do(req)
+// Load events from a Radicale calendar. The download is skipped if the
+// calendar's ctag shows it unchanged since the last one.
+func (p *radicaleProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	client := calendarClient(p.account, cal)
+	// Without a ctag the calendar is downloaded as before
+	ctag, _ := client.CTag(cal.URL, onRetry)
+	docs, ok := cachedCollection(cal.URL, ctag)
+	if !ok {
+		var err error
+		docs, err = client.FetchCalendar(cal.URL, onRetry)
 		if err != nil {
-			lastErr = err
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 207 { // Multi-Status
-			body, _ := io.ReadAll(resp.Body)
-			bodyStr := string(body)
-			if len(bodyStr) > 500 {
-				bodyStr = bodyStr[:500] + "..."
-			}
-			lastErr = fmt.Errorf("failed to discover calendars at %s (status %d): %s", fullURL, resp.StatusCode, bodyStr)
-			continue
+			return nil, err
 		}
+		saveCollection(cal.URL, ctag, docs)
+	}
 
-		body, err := io.ReadAll(resp.Body)
+	var events []Event
+	opts := parseOptions(p.config, cal, from, to, now, true)
+	for _, doc := range docs {
+		docEvents, err := ical.ParseWith(strings.NewReader(doc), cal.Name, opts)
 		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		var ms multistatus
-		if err := xml.Unmarshal(body, &ms); err != nil {
-			lastErr = err
-			continue
-		}
-
-		// If no responses, try next path
-		if len(ms.Response) == 0 {
+			// One broken resource shouldn't hide the rest of the calendar
+			warn(fmt.Sprintf("Skipped an unreadable event of %s: %v", cal.Name, err))
 			continue
 		}
+		events = append(events, docEvents...)
+	}
+	return events, nil
+}
 
-		// Parse responses
-		for _, r := range ms.Response {
-			// Find the successful propstat (status 200)
-			var successfulPropstat *propstat
-			for i := range r.Propstat {
-				if strings.Contains(r.Propstat[i].Status, "200") {
-					successfulPropstat = &r.Propstat[i]
-					break
-				}
-			}
-
-			// Skip if no successful propstat found
-			if successfulPropstat == nil {
-				continue
-			}
-
-			// Filter out the collection itself and only get calendar collections
-			href := r.Href
-			// Normalize the href - handle relative and absolute paths
-			if !strings.HasPrefix(href, "/") {
-				// Relative path - prepend base path
-				if !strings.HasSuffix(basePath, "/") {
-					href = basePath + "/" + href
-				} else {
-					href = basePath + href
-				}
-			}
-			// Ensure href ends with / for collections
-			if !strings.HasSuffix(href, "/") {
-				href += "/"
-			}
+// Create event on Radicale server
+func (p *radicaleProvider) CreateEvent(cal CalendarConfig, event *Event) error {
+	// Generate a unique UID for the event
+	if event.UID == "" {
+		event.UID = ical.NewUID()
+	}
+	return p.UpdateEvent(cal, event)
+}
 
-			// Skip the base path itself
-			normalizedBasePath := basePath
-			if !strings.HasSuffix(normalizedBasePath, "/") {
-				normalizedBasePath += "/"
-			}
-			if href == normalizedBasePath || href == "/" || href == "//" {
-				continue
-			}
+// UpdateEvent writes the event, which replaces the resource of its UID
+func (p *radicaleProvider) UpdateEvent(cal CalendarConfig, event *Event) error {
+	return calendarClient(p.account, cal).Put(cal.URL, event.UID, ical.Build([]Event{*event}))
+}
 
-			// Get calendar name from DisplayName property, fallback to path if not available
-			calName := successfulPropstat.Prop.DisplayName
-			if calName == "" {
-				// Fallback to path-based name
-				calName = path.Base(strings.TrimSuffix(href, "/"))
-			}
+// Delete event from Radicale server
+func (p *radicaleProvider) DeleteEvent(cal CalendarConfig, event Event) error {
+	return calendarClient(p.account, cal).Delete(cal.URL, event.UID)
+}
 
-			// Get path name for filtering
-			pathName := path.Base(strings.TrimSuffix(href, "/"))
+// urlProvider reads an ICS feed
+type urlProvider struct {
+	singleCalendar
+	readOnly
+	config *Config
+}
 
-			// Skip system collections, but allow calendars under username path
-			// Calendars can be at /username/ or /username/calendarname/
-			skip := false
-			if pathName == "user" || pathName == "principals" {
-				skip = true
-			}
-			// Only skip if the pathName equals username AND it's a direct child of root
-			// (not if it's a calendar under the username)
-			if pathName == config.Username && strings.Count(href, "/") <= 2 {
-				// This is the username collection itself, not a calendar
-				skip = true
-			}
+func newURLProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &urlProvider{singleCalendar: singleCalendar{entry}, config: config}
+}
 
-			if !skip {
-				// Construct full URL (normalize to avoid double slashes)
-				calURL := serverURL + href
-				calendars = append(calendars, CalDAVCalendar{
-					DisplayName: calName,
-					URL:         calURL,
-				})
-			}
-		}
+func (p *urlProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	return loadICSFromURL(cal, parseOptions(p.config, cal, from, to, now, false), onRetry)
+}
 
-		// If we found calendars from this path, return them immediately
-		// Don't try the next path to avoid duplicates
-		if len(calendars) > 0 {
-			return calendars, nil
-		}
-	}
+// fileProvider reads a local .ics file
+type fileProvider struct {
+	singleCalendar
+	readOnly
+	config *Config
+}
 
-	// If we got here, we didn't find any calendars
-	if lastErr != nil {
-		return nil, lastErr
-	}
-	return nil, fmt.Errorf("no calendars found")
+func newFileProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &fileProvider{singleCalendar: singleCalendar{entry}, config: config}
 }
 
-// Load events from a Radicale calendar
-func loadICSFromRadicale(calendarURL string, calendarName string, color lipgloss.Color, config *RadicaleConfig) ([]Event, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+func (p *fileProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	return loadICSFromFile(cal.File, cal.Name, parseOptions(p.config, cal, from, to, now, false))
+}
 
-	// Radicale calendars can be accessed via .ics extension
-	// Try multiple URL formats
-	baseURL := strings.TrimSuffix(calendarURL, "/")
-	urlsToTry := []string{
-		baseURL + ".ics",     // Standard Radicale format
-		calendarURL + ".ics", // With trailing slash
-		baseURL,              // Without .ics
-		calendarURL,          // Original URL
+// calendarSources returns the config entries to load: the Radicale server,
+// the calendars, the local store and the local calendar files
+func calendarSources(config *Config, warn func(message string)) []CalendarConfig {
+	var sources []CalendarConfig
+	if config.Radicale != nil && config.Radicale.ServerURL != "" {
+		sources = append(sources, CalendarConfig{Name: config.Radicale.ServerURL, Type: "radicale"})
 	}
 
-	var lastErr error
-	var lastStatus int
-	var lastBody string
-
-	for _, url := range urlsToTry {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			lastErr = err
-			continue
+	for _, cal := range config.Calendars {
+		// Entries of type "radicale" only hold limits for the server's calendars
+		if cal.Type != "radicale" {
+			sources = append(sources, cal)
 		}
+	}
+	if config.localStoreName() != "" {
+		sources = append(sources, config.localStoreSource(warn))
+	}
 
-		auth := base64.StdEncoding.EncodeToString([]byte(config.Username + ":" + config.Password))
-		req.Header.Set("Authorization", "Basic "+auth)
-		req.Header.Set("Accept", "text/calendar")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-		defer resp.Body.Close()
-
-		lastStatus = resp.StatusCode
-		body, _ := io.ReadAll(resp.Body)
-		lastBody = string(body)
-
-		if resp.StatusCode == http.StatusOK {
-			// Check if it's actually calendar data (starts with BEGIN:VCALENDAR)
-			if strings.HasPrefix(strings.TrimSpace(lastBody), "BEGIN:VCALENDAR") {
-				// Try to parse as calendar
-				events, err := loadICSFromReader(bytes.NewReader(body), calendarName, color)
-				if err == nil {
-					return events, nil
-				}
-				lastErr = fmt.Errorf("failed to parse calendar data: %v", err)
+	// Load local .ics files (only if listed in local_calendars)
+	if len(config.LocalCalendars) > 0 {
+		// Determine base directory: try current directory first (dev mode), then config directory
+		var baseDir string
+		localConfig := "calendars.json"
+		if _, err := os.Stat(localConfig); err == nil {
+			// Dev mode: use current directory
+			baseDir = "."
+		} else {
+			// Build mode: use config directory
+			configDir, err := getConfigDir()
+			if err != nil {
+				baseDir = ""
 			} else {
-				lastErr = fmt.Errorf("response is not calendar data (status: %d)", resp.StatusCode)
+				baseDir = configDir
 			}
-		} else if resp.StatusCode == 207 {
-			// Multi-status response - try to extract calendar data from XML
-			return parseCalendarFromMultistatus(lastBody, calendarName, color)
-		} else {
-			// Log the error but try next URL
-			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, lastBody[:min(200, len(lastBody))])
 		}
-	}
 
-	// If all URLs failed, return detailed error
-	return nil, fmt.Errorf("failed to load calendar '%s' from %s (tried %d URLs, last: %d - %v)",
-		calendarName, calendarURL, len(urlsToTry), lastStatus, lastErr)
-}
+		if baseDir != "" {
+			for _, localCal := range config.LocalCalendars {
+				// Construct full path to .ics file
+				icsFile := localCal
+				if !strings.HasSuffix(icsFile, ".ics") {
+					icsFile += ".ics"
+				}
+				icsPath := filepath.Join(baseDir, icsFile)
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
+				// Check if file exists
+				if _, err := os.Stat(icsPath); err != nil {
+					warn(fmt.Sprintf("Local calendar file not found: %s", icsPath))
+					continue
+				}
 
-// Parse calendar data from CalDAV multistatus XML response
-func parseCalendarFromMultistatus(xmlBody string, calendarName string, color lipgloss.Color) ([]Event, error) {
-	// Look for calendar-data elements in the XML
-	// This is a simple regex-based approach - a proper XML parser would be better
-	re := regexp.MustCompile(`<C:calendar-data[^>]*>([\s\S]*?)</C:calendar-data>`)
-	matches := re.FindAllStringSubmatch(xmlBody, -1)
-
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no calendar-data found in multistatus response")
-	}
-
-	// Combine all calendar data blocks
-	var combinedCalendar strings.Builder
-	combinedCalendar.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\n")
-
-	for _, match := range matches {
-		if len(match) > 1 {
-			// Decode XML entities and extract calendar content
-			calData := match[1]
-			calData = strings.ReplaceAll(calData, "&lt;", "<")
-			calData = strings.ReplaceAll(calData, "&gt;", ">")
-			calData = strings.ReplaceAll(calData, "&amp;", "&")
-			calData = strings.ReplaceAll(calData, "&quot;", "\"")
-			calData = strings.ReplaceAll(calData, "&apos;", "'")
-			combinedCalendar.WriteString(calData)
+				calendarName := strings.TrimSuffix(filepath.Base(icsFile), ".ics")
+				sources = append(sources, CalendarConfig{Name: calendarName, File: icsPath, Type: "file"})
+			}
 		}
 	}
-
-	combinedCalendar.WriteString("END:VCALENDAR\n")
-
-	// Parse the combined calendar
-	return loadICSFromReader(strings.NewReader(combinedCalendar.String()), calendarName, color)
+	return sources
 }
 
-// Create event on Radicale server
-func createEventOnRadicale(calendarURL string, event *Event, config *RadicaleConfig) error {
-	// Generate a unique UID for the event
-	if event.UID == "" {
-		event.UID = fmt.Sprintf("%s@mytuicalendar", time.Now().Format("20060102T150405Z"))
-	}
-
-	// Create ICS content
-	icsContent := fmt.Sprintf(`BEGIN:VCALENDAR
-VERSION:2.0
-PRODID:-//MyTuiCalendar//EN
-BEGIN:VEVENT
-UID:%s
-DTSTART:%s
-DTEND:%s
-SUMMARY:%s
-DESCRIPTION:%s
-END:VEVENT
-END:VCALENDAR
-`, event.UID,
-		event.Start.Format("20060102T150405Z"),
-		event.End.Format("20060102T150405Z"),
-		escapeICSValue(event.Summary),
-		escapeICSValue(event.Description))
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	eventURL := calendarURL + "/" + event.UID + ".ics"
-
-	req, err := http.NewRequest("PUT", eventURL, bytes.NewBufferString(icsContent))
-	if err != nil {
-		return err
-	}
-
-	auth := base64.StdEncoding.EncodeToString([]byte(config.Username + ":" + config.Password))
-	req.Header.Set("Authorization", "Basic "+auth)
-	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 201 && resp.StatusCode != 204 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create event: %s - %s", resp.Status, string(body))
-	}
-
-	return nil
-}
-
-func escapeICSValue(value string) string {
-	value = strings.ReplaceAll(value, "\\", "\\\\")
-	value = strings.ReplaceAll(value, ",", "\\,")
-	value = strings.ReplaceAll(value, ";", "\\;")
-	value = strings.ReplaceAll(value, "\n", "\\n")
-	return value
+// loadAllCalendars loads every configured calendar. report, if not nil, is
+// called with the overall progress (0-1) and a status line as loading goes on.
+// Warnings about calendars that failed to load go to warn, or to stderr if
+// warn is nil.
+func loadAllCalendars(clock Clock, radicaleConfig *RadicaleConfig, report func(progress float64, message string), warn func(message string)) ([]Event, map[string]lipgloss.Color, map[string]string, error) {
+	return loadCalendarsAround(clock, clock.Now(), radicaleConfig, report, warn)
 }
 
-func loadAllCalendars(radicaleConfig *RadicaleConfig) ([]Event, map[string]lipgloss.Color, map[string]string, error) {
+// loadCalendarsAround is loadAllCalendars for a view of center, which
+// limits the events loaded in low memory mode
+func loadCalendarsAround(clock Clock, center time.Time, radicaleConfig *RadicaleConfig, report func(progress float64, message string), warn func(message string)) ([]Event, map[string]lipgloss.Color, map[string]string, error) {
+	startupProfile.step("initialize")
+	now := clock.Now()
 	var allEvents []Event
 	calendars := make(map[string]lipgloss.Color)
 	calendarURLs := make(map[string]string)
 	colorIndex := 0
-	loadedCalendars := make(map[string]bool)
+
+	if report == nil {
+		report = func(float64, string) {}
+	}
+	if warn == nil {
+		warn = func(message string) {
+			fmt.Fprintln(os.Stderr, "Warning: "+message)
+		}
+	}
+	// startCalendar reports the next calendar being loaded and returns a
+	// RetryFunc that reports its retries
+	step, total := 0, 1
+	startCalendar := func(name string) caldav.RetryFunc {
+		progress := float64(step) / float64(total)
+		step++
+		report(progress, fmt.Sprintf(tr("Loading %s..."), name))
+		return func(attempt int, err error) {
+			report(progress, fmt.Sprintf(tr("%s: %v, retrying (attempt %d/%d)"), name, err, attempt, maxAttempts))
+		}
+	}
 
 	config, configErr := loadConfig()
 	if configErr == nil && config != nil {
 		// Use config's Radicale if available, otherwise use passed parameter
-		if config.Radicale != nil {
-			radicaleConfig = config.Radicale
+		if config.Radicale == nil {
+			config.Radicale = radicaleConfig
 		}
 
-		// Load Radicale calendars if configured
-		if radicaleConfig != nil && radicaleConfig.ServerURL != "" {
-			radicaleCals, err := loadCalendarsFromRadicale(radicaleConfig)
-			if err == nil {
-				for _, cal := range radicaleCals {
-					color := calendarColors[colorIndex%len(calendarColors)]
-					calendars[cal.DisplayName] = color
-					calendarURLs[cal.DisplayName] = cal.URL
-
-					events, err := loadICSFromRadicale(cal.URL, cal.DisplayName, color, radicaleConfig)
-					if err == nil {
-						allEvents = append(allEvents, events...)
-					} else {
-						fmt.Fprintf(os.Stderr, "Warning: Failed to load Radicale calendar %s: %v\n", cal.DisplayName, err)
-					}
-					colorIndex++
-				}
-			} else {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to connect to Radicale server: %v\n", err)
-			}
+		// Ask every source for its calendars first, so progress can count them
+		type calendar struct {
+			provider CalendarProvider
+			entry    CalendarConfig
 		}
-
-		// Load other calendars
-		for _, cal := range config.Calendars {
-			// Skip if it's a Radicale calendar (already loaded above)
-			if cal.Type == "radicale" {
+		var found []calendar
+		for _, source := range calendarSources(config, warn) {
+			newProvider, ok := providers[providerType(source)]
+			if !ok {
+				warn(fmt.Sprintf("Unknown type %q of calendar %s", source.Type, source.Name))
 				continue
 			}
+			provider := newProvider(config, source)
+			cals, err := provider.Discover(now, startCalendar(source.Name))
+			step = 0
+			if err != nil {
+				warn(fmt.Sprintf("Failed to load calendar %s: %v", source.Name, err))
+				continue
+			}
+			for _, cal := range cals {
+				found = append(found, calendar{provider, cal})
+			}
+		}
+		total = max(1, len(found))
 
-			color := calendarColors[colorIndex%len(calendarColors)]
-			calendars[cal.Name] = color
-
-			var events []Event
-			var err error
+		startupProfile.step("discover calendars")
 
-			if cal.URL != "" {
-				events, err = loadICSFromURL(cal.URL, cal.Name, color)
-			} else if cal.File != "" {
-				events, err = loadICSFromFile(cal.File, cal.Name, color)
-				loadedCalendars[cal.File] = true
-			}
+		from, to := fetchRange(config, center, now)
+		for _, cal := range found {
+			color := calendarColors[colorIndex%len(calendarColors)]
+			calendars[cal.entry.Name] = color
 
+			startupProfile.startCalendar()
+			calFrom, calTo := calendarWindow(cal.entry, from, to, now)
+			events, err := cal.provider.FetchEvents(cal.entry, calFrom, calTo, now, startCalendar(cal.entry.Name), warn)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to load calendar %s: %v\n", cal.Name, err)
+				startupProfile.endCalendar(cal.entry.Name+" (failed)", 0)
+				warn(fmt.Sprintf("Failed to load calendar %s: %v", cal.entry.Name, err))
 				continue
 			}
+			if writable(cal.provider) {
+				calendarURLs[cal.entry.Name] = cal.entry.URL
+			}
 
+			events = limitEvents(filterEvents(events, cal.entry, warn), cal.entry, now)
+			startupProfile.endCalendar(cal.entry.Name, len(events))
 			allEvents = append(allEvents, events...)
 			colorIndex++
 		}
+	}
 
-		// Load local .ics files (only if listed in local_calendars)
-		if len(config.LocalCalendars) > 0 {
-			// Determine base directory: try current directory first (dev mode), then config directory
-			var baseDir string
-			localConfig := "calendars.json"
-			if _, err := os.Stat(localConfig); err == nil {
-				// Dev mode: use current directory
-				baseDir = "."
-			} else {
-				// Build mode: use config directory
-				configDir, err := getConfigDir()
-				if err != nil {
-					baseDir = ""
-				} else {
-					baseDir = configDir
-				}
-			}
+	report(1, tr("Done"))
+	setCalendarColors(calendars)
 
-			if baseDir != "" {
-				for _, localCal := range config.LocalCalendars {
-					// Construct full path to .ics file
-					icsFile := localCal
-					if !strings.HasSuffix(icsFile, ".ics") {
-						icsFile += ".ics"
-					}
-					icsPath := filepath.Join(baseDir, icsFile)
-
-					// Check if file exists
-					if _, err := os.Stat(icsPath); err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: Local calendar file not found: %s\n", icsPath)
-						continue
-					}
-
-					calendarName := strings.TrimSuffix(filepath.Base(icsFile), ".ics")
-					color := calendarColors[colorIndex%len(calendarColors)]
-					calendars[calendarName] = color
-
-					events, err := loadICSFromFile(icsPath, calendarName, color)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: Failed to load local calendar %s: %v\n", calendarName, err)
-						continue
-					}
-
-					allEvents = append(allEvents, events...)
-					colorIndex++
-				}
-			}
-		}
+	if config != nil && config.MergeDuplicates {
+		allEvents = mergeDuplicates(allEvents)
 	}
 
-	if len(allEvents) == 0 {
+	// colorIndex counts the calendars loaded. A low memory window may hold
+	// none of their events, which is no error.
+	if colorIndex == 0 {
 		return nil, nil, nil, fmt.Errorf("no calendars found")
 	}
 
+	// Keep the coming days for --motd, unless they weren't loaded
+	if dayIndex(center, now) == 0 || config.lowMemoryDays() == 0 {
+		if err := saveEventCache(allEvents, calendars, now); err != nil {
+			warn(fmt.Sprintf("Failed to save the event cache: %v", err))
+		}
+	}
+
+	startupProfile.step("merge and cache")
 	return allEvents, calendars, calendarURLs, nil
 }
 
-func getNextEvent(events []Event) *Event {
-	now := time.Now()
-	var upcoming []Event
+// radicaleLimits returns the limits configured for a Radicale calendar
+func radicaleLimits(config *Config, name string) CalendarConfig {
+	for _, cal := range config.Calendars {
+		if cal.Type == "radicale" && cal.Name == name {
+			return cal
+		}
+	}
+	return CalendarConfig{}
+}
 
-	for _, event := range events {
-		if event.Start.After(now) {
-			upcoming = append(upcoming, event)
+// calendarWindow narrows [from, to) to a calendar's window_days around now,
+// so that its events outside are never parsed or fetched
+func calendarWindow(cal CalendarConfig, from, to, now time.Time) (time.Time, time.Time) {
+	if cal.WindowDays <= 0 {
+		return from, to
+	}
+	if start := now.AddDate(0, 0, -cal.WindowDays); start.After(from) {
+		from = start
+	}
+	if end := now.AddDate(0, 0, cal.WindowDays); end.Before(to) {
+		to = end
+	}
+	// A low memory window far from today holds none of its events
+	if to.Before(from) {
+		to = from
+	}
+	return from, to
+}
+
+// limitEvents applies a calendar's max_events, keeping the events closest
+// to today
+func limitEvents(events []Event, cal CalendarConfig, now time.Time) []Event {
+	if cal.MaxEvents <= 0 || len(events) <= cal.MaxEvents {
+		return events
+	}
+
+	distance := func(event Event) time.Duration {
+		if d := event.Start.Sub(now); d >= 0 {
+			return d
 		}
+		return now.Sub(event.Start)
 	}
+	sort.Slice(events, func(i, j int) bool {
+		return distance(events[i]) < distance(events[j])
+	})
+	return events[:cal.MaxEvents]
+}
 
+func getNextEvent(events []Event, now time.Time) *Event {
+	upcoming := getUpcomingEvents(events, 1, 0, now)
 	if len(upcoming) == 0 {
 		return nil
 	}
+	return &upcoming[0]
+}
+
+// getUpcomingEvents returns up to count future events (all if count <= 0),
+// optionally limited to those starting within the given window
+func getUpcomingEvents(events []Event, count int, within time.Duration, now time.Time) []Event {
+	var upcoming []Event
+
+	for _, event := range events {
+		if !event.Start.After(now) {
+			continue
+		}
+		if within > 0 && event.Start.After(now.Add(within)) {
+			continue
+		}
+		upcoming = append(upcoming, event)
+	}
 
 	sort.Slice(upcoming, func(i, j int) bool {
 		return upcoming[i].Start.Before(upcoming[j].Start)
 	})
 
-	return &upcoming[0]
+	if count > 0 && len(upcoming) > count {
+		upcoming = upcoming[:count]
+	}
+	return upcoming
+}
+
+// formatTimeUntil renders the time until t as " (in 5m)", " (in 2.5h)" or " (in 3d)"
+func formatTimeUntil(t, now time.Time) string {
+	timeUntil := t.Sub(now)
+	if timeUntil < time.Hour {
+		return fmt.Sprintf(" (in %dm)", int(timeUntil.Minutes()))
+	} else if timeUntil < 24*time.Hour {
+		return fmt.Sprintf(" (in %.1fh)", timeUntil.Hours())
+	}
+	return fmt.Sprintf(" (in %dd)", int(timeUntil.Hours()/24))
 }
 
-func renderNextEvent(event *Event) string {
+// renderUpcomingEvents renders events as a compact list, one line per event
+func renderUpcomingEvents(events []Event, now time.Time) string {
+	if len(events) == 0 {
+		return noEventsStyle.Render(tr("No upcoming events"))
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + titleStyle.Render(tr("📅 Upcoming Events")) + "\n\n")
+	for _, event := range events {
+		timeStr := fmt.Sprintf(" %s - %s ",
+			formatDate(event.Start, "Mon Jan 2, 15:04"),
+			event.End.Format("15:04"),
+		)
+		titleStyle := lipgloss.NewStyle().Foreground(eventColor(event))
+		untilStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		b.WriteString(timeStyle.Render(timeStr) + titleStyle.Render(eventMarker(event)+event.Summary) + untilStyle.Render(formatTimeUntil(event.Start, now)) + "\n")
+	}
+	return b.String()
+}
+
+func renderNextEvent(event *Event, now time.Time) string {
 	if event == nil {
-		return noEventsStyle.Render("No upcoming events")
+		return noEventsStyle.Render(tr("No upcoming events"))
 	}
 
 	var boxContent strings.Builder
 
 	timeStr := fmt.Sprintf("%s - %s",
-		event.Start.Format("Mon Jan 2, 15:04"),
+		formatDate(event.Start, "Mon Jan 2, 15:04"),
 		event.End.Format("15:04"),
 	)
 
-	timeUntil := time.Until(event.Start)
-	timeUntilStr := ""
-	if timeUntil < time.Hour {
-		timeUntilStr = fmt.Sprintf(" (in %dm)", int(timeUntil.Minutes()))
-	} else if timeUntil < 24*time.Hour {
-		timeUntilStr = fmt.Sprintf(" (in %.1fh)", timeUntil.Hours())
-	} else {
-		timeUntilStr = fmt.Sprintf(" (in %dd)", int(timeUntil.Hours()/24))
-	}
+	timeUntilStr := formatTimeUntil(event.Start, now)
 
 	timeLineStyle := timeStyle.Foreground(lipgloss.Color("241"))
 	boxContent.WriteString(timeLineStyle.Render(timeStr+timeUntilStr) + "\n")
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(event.CalendarColor).
+		Foreground(eventColor(*event)).
 		Bold(true)
-	boxContent.WriteString(titleStyle.Render("● " + event.Summary))
+	boxContent.WriteString(titleStyle.Render(truncate(eventMarker(*event)+event.Summary, 56)))
 
 	if event.Description != "" && strings.TrimSpace(event.Description) != "" {
 		descStyle := lipgloss.NewStyle().
@@ -658,211 +478,13 @@ func renderNextEvent(event *Event) string {
 			Italic(true).
 			Width(56)
 
-		desc := strings.TrimSpace(event.Description)
-		if len(desc) > 150 {
-			desc = desc[:150] + "..."
-		}
-		boxContent.WriteString("\n" + descStyle.Render(desc))
+		desc := wrapText(strings.TrimSpace(plainDescription(event.Description)), 56, maxDescriptionLines)
+		boxContent.WriteString("\n" + descStyle.Render(strings.Join(desc, "\n")))
 	}
 
 	boxStyle := eventBoxStyle.
-		BorderForeground(event.CalendarColor).
+		BorderForeground(eventColor(*event)).
 		Width(60)
 
-	return "\n" + titleStyle.Foreground(lipgloss.Color("86")).Bold(true).Render("📅 Next Event") + "\n\n" + boxStyle.Render(boxContent.String())
-}
-
-// expandRecurringEvent expands a recurring event based on RRULE
-type occurrence struct {
-	Start time.Time
-	End   time.Time
-}
-
-func expandRecurringEvent(start, end time.Time, rrule string, maxDate time.Time, now time.Time) []occurrence {
-	var occurrences []occurrence
-	duration := end.Sub(start)
-
-	// Parse RRULE - basic support for common patterns
-	// Format: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY[;INTERVAL=n][;COUNT=n][;UNTIL=YYYYMMDDTHHMMSSZ]
-	rrule = strings.ToUpper(rrule)
-	
-	var freq string
-	interval := 1
-	var until time.Time
-	count := -1
-
-	parts := strings.Split(rrule, ";")
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if strings.HasPrefix(part, "FREQ=") {
-			freq = strings.TrimPrefix(part, "FREQ=")
-		} else if strings.HasPrefix(part, "INTERVAL=") {
-			if val, err := strconv.Atoi(strings.TrimPrefix(part, "INTERVAL=")); err == nil {
-				interval = val
-			}
-		} else if strings.HasPrefix(part, "UNTIL=") {
-			untilStr := strings.TrimPrefix(part, "UNTIL=")
-			// Try parsing different date formats
-			if t, err := time.Parse("20060102T150405Z", untilStr); err == nil {
-				until = t
-			} else if t, err := time.Parse("20060102T150405", untilStr); err == nil {
-				until = t
-			} else if t, err := time.Parse("20060102", untilStr); err == nil {
-				until = t
-			}
-		} else if strings.HasPrefix(part, "COUNT=") {
-			if val, err := strconv.Atoi(strings.TrimPrefix(part, "COUNT=")); err == nil {
-				count = val
-			}
-		}
-	}
-
-	// Determine end date
-	endDate := maxDate
-	if !until.IsZero() && until.Before(maxDate) {
-		endDate = until
-	}
-
-	// Start from the original start date
-	currentStart := start
-	iteration := 0
-	maxIterations := 1000 // Safety limit
-
-	// Check if we need to fast-forward past occurrences
-	// Only fast-forward if the event is more than 1 day in the past
-	// We want to include events from yesterday (they're still relevant)
-	originalIsToday := currentStart.Format("2006-01-02") == now.Format("2006-01-02")
-	yesterday := now.AddDate(0, 0, -1)
-	originalIsYesterday := currentStart.Format("2006-01-02") == yesterday.Format("2006-01-02")
-	// Only fast-forward if it's before yesterday (more than 1 day old)
-	needsFastForward := currentStart.Before(yesterday) && !originalIsToday && !originalIsYesterday
-	
-	// If the original event is today or in the future, we'll include it in the loop
-	// If it's in the past (not today), we need to fast-forward to today or the next occurrence
-	if needsFastForward {
-		// For past events, fast-forward to today's occurrence (if it exists) or the next occurrence after now
-		// We want to include today's occurrence even if the event started in the past
-		todayDate := now.Format("2006-01-02")
-		switch freq {
-		case "DAILY":
-			// Fast-forward until we reach today (date-wise) or the future
-			for {
-				nextStart := currentStart.AddDate(0, 0, interval)
-				nextDate := nextStart.Format("2006-01-02")
-				
-				// Stop if we've reached today (same date) - regardless of time
-				// OR if we've reached the future
-				if nextDate == todayDate {
-					currentStart = nextStart
-					break
-				}
-				
-				// If we've reached the future (after today), stop
-				if nextStart.After(now) {
-					currentStart = nextStart
-					break
-				}
-				
-				// If still in the past (before today), continue
-				currentStart = nextStart
-			}
-		case "WEEKLY":
-			// Fast-forward until we reach today (date-wise) or the future
-			for {
-				nextStart := currentStart.AddDate(0, 0, 7*interval)
-				nextDate := nextStart.Format("2006-01-02")
-				if nextDate == todayDate {
-					currentStart = nextStart
-					break
-				}
-				if nextStart.After(now) {
-					currentStart = nextStart
-					break
-				}
-				currentStart = nextStart
-			}
-		case "MONTHLY":
-			// Fast-forward until we reach today (date-wise) or the future
-			for {
-				nextStart := currentStart.AddDate(0, interval, 0)
-				nextDate := nextStart.Format("2006-01-02")
-				if nextDate == todayDate {
-					currentStart = nextStart
-					break
-				}
-				if nextStart.After(now) {
-					currentStart = nextStart
-					break
-				}
-				currentStart = nextStart
-			}
-		case "YEARLY":
-			// Fast-forward until we reach today (date-wise) or the future
-			for {
-				nextStart := currentStart.AddDate(interval, 0, 0)
-				nextDate := nextStart.Format("2006-01-02")
-				if nextDate == todayDate {
-					currentStart = nextStart
-					break
-				}
-				if nextStart.After(now) {
-					currentStart = nextStart
-					break
-				}
-				currentStart = nextStart
-			}
-		default:
-			// Unknown frequency, return empty
-			return occurrences
-		}
-		// Make sure we don't skip too far
-		if currentStart.After(endDate) {
-			return occurrences
-		}
-	} else {
-		// Original event is today or in the future - start from the original start
-		// This ensures we include the first occurrence
-		currentStart = start
-	}
-
-	// Generate occurrences starting from currentStart
-	// Always include the first occurrence if it's today or in the future
-	for currentStart.Before(endDate) && iteration < maxIterations {
-		if count > 0 && iteration >= count {
-			break
-		}
-
-		// Include occurrences that are yesterday, today, or in the future
-		// We include yesterday's events because they're still relevant (just happened)
-		occIsToday := currentStart.Format("2006-01-02") == now.Format("2006-01-02")
-		occIsYesterday := currentStart.Format("2006-01-02") == yesterday.Format("2006-01-02")
-		occIsFuture := currentStart.After(now)
-		
-		// Always include if it's yesterday, today, or in the future
-		if occIsYesterday || occIsToday || occIsFuture {
-			occurrences = append(occurrences, occurrence{
-				Start: currentStart,
-				End:   currentStart.Add(duration),
-			})
-		}
-
-		// Move to next occurrence based on frequency
-		switch freq {
-		case "DAILY":
-			currentStart = currentStart.AddDate(0, 0, interval)
-		case "WEEKLY":
-			currentStart = currentStart.AddDate(0, 0, 7*interval)
-		case "MONTHLY":
-			currentStart = currentStart.AddDate(0, interval, 0)
-		case "YEARLY":
-			currentStart = currentStart.AddDate(interval, 0, 0)
-		default:
-			// Unknown frequency, stop expansion
-			return occurrences
-		}
-
-		iteration++
-	}
-
-	return occurrences
+	return "\n" + titleStyle.Foreground(lipgloss.Color("86")).Bold(true).Render(tr("📅 Next Event")) + "\n\n" + boxStyle.Render(boxContent.String())
 }
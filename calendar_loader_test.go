@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarWindow(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	yearAround := [2]time.Time{now.AddDate(0, -1, 0), now.AddDate(1, 0, 0)}
+
+	tests := []struct {
+		name       string
+		windowDays int
+		from, to   time.Time
+		want       [2]time.Time
+	}{
+		{"no window", 0, yearAround[0], yearAround[1], yearAround},
+		{"within the range", 7, yearAround[0], yearAround[1], [2]time.Time{day(6, 8), day(6, 22)}},
+		{"wider than the range", 90, yearAround[0], yearAround[1], [2]time.Time{yearAround[0], day(9, 13)}},
+		{"low memory range around another date", 7, day(6, 20), day(7, 20), [2]time.Time{day(6, 20), day(6, 22)}},
+		{"low memory range far from today", 7, day(9, 1), day(10, 1), [2]time.Time{day(9, 1), day(9, 1)}},
+	}
+	for _, test := range tests {
+		from, to := calendarWindow(CalendarConfig{WindowDays: test.windowDays}, test.from, test.to, now)
+		if !from.Equal(test.want[0]) || !to.Equal(test.want[1]) {
+			t.Errorf("%s: got %v - %v, want %v - %v", test.name, from, to, test.want[0], test.want[1])
+		}
+	}
+}
+
+func TestLimitEventsKeepsClosest(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	var events []Event
+	for _, days := range []int{-30, 10, -2, 1, 60} {
+		events = append(events, Event{Start: now.AddDate(0, 0, days)})
+	}
+
+	limited := limitEvents(events, CalendarConfig{MaxEvents: 3}, now)
+	if len(limited) != 3 {
+		t.Fatalf("kept %d events, want 3", len(limited))
+	}
+	for _, event := range limited {
+		if days := event.Start.Sub(now).Hours() / 24; days < -2.5 || days > 10.5 {
+			t.Errorf("kept the event %.0f days away", days)
+		}
+	}
+}
+
+// A broken item is reported and skipped, keeping the rest of the calendar
+func TestFetchSkipsUnreadableEvents(t *testing.T) {
+	dir := t.TempDir()
+	good := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:good@test\r\nDTSTART:20250710T090000Z\r\n" +
+		"DTEND:20250710T100000Z\r\nSUMMARY:Readable\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	for name, data := range map[string]string{"good.ics": good, "broken.ics": "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cal := CalendarConfig{Name: "Notes", URL: vdirScheme + dir}
+	var warnings []string
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	events, err := newVdirProvider(&Config{}, cal).FetchEvents(cal, now.AddDate(0, -1, 0), now.AddDate(1, 0, 0), now, nil,
+		func(message string) { warnings = append(warnings, message) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Summary != "Readable" {
+		t.Errorf("got %v, want the readable event", events)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "broken.ics") {
+		t.Errorf("got warnings %q, want one for broken.ics", warnings)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// descriptionCleaner removes the boilerplate a meeting service adds to
+// invites and finds the link to join
+type descriptionCleaner struct {
+	cut  []*regexp.Regexp // Removed from the description
+	join *regexp.Regexp   // The join link, nil if none
+}
+
+// Link characters, up to the brackets and quotes around links in text
+// and Markdown
+const linkChars = `[^\s<>"'()\[\]]+`
+
+// cleanerPresets are the built-in cleaners by name
+var cleanerPresets = map[string]descriptionCleaner{
+	"teams": {
+		cut: []*regexp.Regexp{
+			// Between two lines of underscores
+			regexp.MustCompile(`(?s)_{20,}\s*(\*\*)?Microsoft Teams.*?(_{20,}|\z)`),
+			regexp.MustCompile(`(?s)(\*\*)?Microsoft Teams (meeting|Need help\?).*?(Meeting options|Learn More|Reset dial-in PIN)[^\n]*`),
+		},
+		join: regexp.MustCompile(`https://teams\.microsoft\.com/l/meetup-join/` + linkChars),
+	},
+	"zoom": {
+		cut: []*regexp.Regexp{
+			regexp.MustCompile(`[^\n]*is inviting you to a scheduled Zoom meeting\.?`),
+			// The dial-in numbers run to the end
+			regexp.MustCompile(`(?s)(\*\*)?Join Zoom Meeting.*`),
+		},
+		join: regexp.MustCompile(`https://[\w.-]*zoom\.us/(j|my|w)/` + linkChars),
+	},
+	"meet": {
+		cut: []*regexp.Regexp{
+			// Between two lines of "-::~:~::~:~:~…::-"
+			regexp.MustCompile(`(?s)-::~[:~]*::-.*?(-::~[:~]*::-|\z)`),
+			regexp.MustCompile(`(?s)(\*\*)?Join with Google Meet.*?(Please do not edit this section\.|Learn more about Meet at:?[^\n]*|\z)`),
+		},
+		join: regexp.MustCompile(`https://meet\.google\.com/[a-z]{3}-[a-z]{4}-[a-z]{3}`),
+	},
+}
+
+// descriptionCleaners clean event descriptions, all presets unless
+// configured otherwise
+var descriptionCleaners = []descriptionCleaner{cleanerPresets["teams"], cleanerPresets["zoom"], cleanerPresets["meet"]}
+
+// setDescriptionCleaners picks the presets and compiles the patterns of the
+// descriptions config. Invalid patterns are left out.
+func setDescriptionCleaners(config *DescriptionConfig) error {
+	if config == nil {
+		return nil
+	}
+	var cleaners []descriptionCleaner
+	if config.Presets != nil {
+		for _, name := range config.Presets {
+			if name == "none" {
+				continue
+			}
+			preset, ok := cleanerPresets[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("unknown description preset %q, expected teams, zoom, meet or none", name)
+			}
+			cleaners = append(cleaners, preset)
+		}
+	} else {
+		cleaners = append(cleaners, descriptionCleaners...)
+	}
+
+	var custom descriptionCleaner
+	var err error
+	for _, pattern := range config.Patterns {
+		re, compileErr := regexp.Compile(pattern)
+		if compileErr != nil {
+			err = fmt.Errorf("description pattern %q: %v", pattern, compileErr)
+			continue
+		}
+		custom.cut = append(custom.cut, re)
+	}
+	if len(custom.cut) > 0 {
+		cleaners = append(cleaners, custom)
+	}
+	descriptionCleaners = cleaners
+	return err
+}
+
+// cleanDescription removes meeting boilerplate from a description and
+// returns the human-written rest and the join link, "" if none was found
+func cleanDescription(description string) (text, join string) {
+	text = description
+	for _, cleaner := range descriptionCleaners {
+		if join == "" && cleaner.join != nil {
+			join = cleaner.join.FindString(description)
+		}
+		for _, cut := range cleaner.cut {
+			text = cut.ReplaceAllString(text, "")
+		}
+	}
+	if text == description {
+		return text, join
+	}
+	return tidyMarkdown(text), join
+}
@@ -0,0 +1,15 @@
+package main
+
+import "time"
+
+// Clock tells the current time. Everything that depends on "now" (today's
+// highlight, the next event, recurrence expansion) asks the clock of the
+// model or loader instead of calling time.Now, so tests can pin it.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the clock the app runs at
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
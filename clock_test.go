@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fixedClock is always at the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// berlin is a zone with a DST change at 02:00 on March 30, 2025
+func berlin(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no time zone data for Europe/Berlin: %v", err)
+	}
+	return loc
+}
+
+// Pressing t goes to the day of the clock, on either side of midnight
+func TestJumpToToday(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2026, 1, 14, 23, 59, 59, 0, time.Local), "2026-01-14"},
+		{time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local), "2026-01-15"},
+	}
+	for _, test := range tests {
+		m := initialModel(DailyView, false, nil, fixedClock(test.now))
+		m.currentDate = time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		if got := updated.(model).currentDate.Format("2006-01-02"); got != test.want {
+			t.Errorf("at %v jumped to %s, want %s", test.now, got, test.want)
+		}
+	}
+}
+
+// The events of yesterday and today around midnight: an event starting
+// right now is no longer upcoming, one at midnight is
+func TestUpcomingAroundMidnight(t *testing.T) {
+	midnight := time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local)
+	events := []Event{
+		{Summary: "Late call", Start: midnight.Add(-time.Minute), End: midnight.Add(30 * time.Minute)},
+		{Summary: "Midnight release", Start: midnight, End: midnight.Add(2 * time.Hour)},
+		{Summary: "Breakfast", Start: midnight.Add(8 * time.Hour), End: midnight.Add(9 * time.Hour)},
+	}
+
+	clock := fixedClock(midnight.Add(-time.Minute))
+	upcoming := getUpcomingEvents(events, 0, 0, clock.Now())
+	if len(upcoming) != 2 || upcoming[0].Summary != "Midnight release" {
+		t.Fatalf("upcoming at 23:59 = %v, want the release and breakfast", upcoming)
+	}
+	if got := formatTimeUntil(upcoming[0].Start, clock.Now()); got != " (in 1m)" {
+		t.Errorf("release %q, want in 1m", got)
+	}
+
+	clock = fixedClock(midnight)
+	if next := getNextEvent(events, clock.Now()); next == nil || next.Summary != "Breakfast" {
+		t.Errorf("next at midnight = %v, want breakfast", next)
+	}
+}
+
+// A daily event from long ago is fast-forwarded to today's occurrence, even
+// once it is over; one that started yesterday keeps its first occurrence.
+// Both change at midnight.
+func TestOccurrencesFromYesterday(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)
+	event := Event{Summary: "Standup", Start: start, End: start.Add(15 * time.Minute), RRule: "FREQ=DAILY"}
+
+	tests := []struct {
+		now   time.Time
+		first string
+	}{
+		{time.Date(2026, 1, 15, 23, 59, 59, 0, time.Local), "2026-01-15"},
+		{time.Date(2026, 1, 16, 0, 0, 0, 0, time.Local), "2026-01-16"},
+		{time.Date(2026, 1, 2, 23, 59, 59, 0, time.Local), "2026-01-01"},
+		{time.Date(2026, 1, 3, 0, 0, 0, 0, time.Local), "2026-01-03"},
+	}
+	for _, test := range tests {
+		clock := fixedClock(test.now)
+		expanded := occurrences(event, clock.Now())
+		if len(expanded) == 0 {
+			t.Fatalf("no occurrences at %v", test.now)
+		}
+		if got := expanded[0].Start.Format("2006-01-02"); got != test.first {
+			t.Errorf("at %v the first occurrence is on %s, want %s", test.now, got, test.first)
+		}
+		if last := expanded[len(expanded)-1].Start; last.After(test.now.AddDate(1, 0, 0)) {
+			t.Errorf("at %v expanded up to %v, more than a year ahead", test.now, last)
+		}
+	}
+}
+
+// Fast-forwarding a daily event from winter time to the day of the DST
+// change keeps its wall-clock time and length, whether the clock is before
+// or after the change
+func TestOccurrencesAcrossDST(t *testing.T) {
+	loc := berlin(t)
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, loc)
+	event := Event{Summary: "Standup", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY"}
+
+	for _, now := range []time.Time{
+		time.Date(2025, 3, 30, 1, 59, 0, 0, loc),
+		time.Date(2025, 3, 30, 3, 0, 0, 0, loc),
+	} {
+		clock := fixedClock(now)
+		expanded := occurrences(event, clock.Now())
+		if len(expanded) < 3 {
+			t.Fatalf("got %d occurrences at %v", len(expanded), now)
+		}
+		for i, want := range []string{"2025-03-30", "2025-03-31", "2025-04-01"} {
+			got := expanded[i].Start
+			if got.Format("2006-01-02") != want || got.Hour() != 9 || got.Minute() != 0 {
+				t.Errorf("at %v occurrence %d at %v, want 09:00 on %s", now, i, got, want)
+			}
+			if length := expanded[i].End.Sub(got); length != time.Hour {
+				t.Errorf("at %v occurrence %d lasts %v, want 1h", now, i, length)
+			}
+		}
+		// The 23 hour day is still one day
+		if days := dayIndex(clock.Now(), expanded[1].Start); days != 1 {
+			t.Errorf("at %v March 31 is %d days away, want 1", now, days)
+		}
+	}
+}
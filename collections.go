@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// collectionsDir holds the last download of each Radicale calendar
+const collectionsDir = "collections"
+
+// collectionCache is a calendar's iCalendar documents as downloaded, with
+// the getctag they were downloaded at
+type collectionCache struct {
+	URL  string   `json:"url"`
+	CTag string   `json:"ctag"`
+	Docs []string `json:"docs"`
+}
+
+func collectionPath(calendarURL string) (string, error) {
+	dir, err := getStatePath(collectionsDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(calendarURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// cachedCollection returns the documents last downloaded from a calendar if
+// its ctag hasn't changed since
+func cachedCollection(calendarURL, ctag string) ([]string, bool) {
+	if ctag == "" {
+		return nil, false
+	}
+	cache, err := loadCollection(calendarURL)
+	if err != nil || cache == nil || cache.CTag != ctag {
+		return nil, false
+	}
+	return cache.Docs, true
+}
+
+// loadCollection returns the last download of a calendar, nil if there is
+// none
+func loadCollection(calendarURL string) (*collectionCache, error) {
+	cachePath, err := collectionPath(calendarURL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cache collectionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	if cache.URL != calendarURL {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+func saveCollection(calendarURL, ctag string, docs []string) error {
+	if ctag == "" {
+		return nil
+	}
+	cachePath, err := collectionPath(calendarURL)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(collectionCache{URL: calendarURL, CTag: ctag, Docs: docs})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, data, 0644)
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// subcommand is a command given as the first argument, as in "zebracal add"
+type subcommand struct {
+	name    string
+	summary string
+	run     func(args []string)
+	flags   func() *flag.FlagSet // For completion, nil without flags
+	args    []string             // Completions of the first argument
+}
+
+var subcommands = []subcommand{
+	{name: "view", summary: "Start the calendar, or print the day, week, month or rolling weeks and quit", run: runViewCommand,
+		flags: func() *flag.FlagSet { return viewFlags(&runOptions{}) },
+		args:  []string{"day", "week", "month", "rolling"}},
+	{name: "next", summary: "Show the next N upcoming events and quit, like --next", run: runNextCommand,
+		flags: func() *flag.FlagSet { return nextCommandFlags(&runOptions{}) }},
+	{name: "add", summary: "Add an event without starting the calendar", run: runAddCommand,
+		flags: func() *flag.FlagSet { return addFlags(&addOptions{}) }},
+	{name: "export", summary: "Print the events as Markdown or HTML, a VFREEBUSY, a PDF month or a static site", run: runExportCommand,
+		flags: func() *flag.FlagSet { return exportFlags(&runOptions{}) },
+		args:  []string{"md", "html", "freebusy", "pdf", "site"}},
+	{name: "import", summary: "Preview the events of an ICS file or of stdin to accept them into a calendar", run: runImportCommand,
+		flags: func() *flag.FlagSet { return importFlags(&runOptions{}) }},
+	{name: "reply", summary: "Answer an invitation read from stdin with a reply for the organizer", run: runReplyCommand,
+		flags: func() *flag.FlagSet { return replyFlags(&replyOptions{}) }, args: []string{"accept", "decline", "tentative"}},
+	{name: "remind", summary: "List, snooze or dismiss reminders, or run the reminder daemon", run: runRemindCommand,
+		args: []string{"list", "snooze", "dismiss", "daemon"}},
+	{name: "sync", summary: "Load all calendars, or check the cached CalDAV calendars against the server", run: runSyncCommand,
+		args: []string{"verify"}},
+	{name: "doctor", summary: "Check the config and that every calendar loads", run: func([]string) { runDoctorCommand() }},
+	{name: "config", summary: "Print the path of the config file, or open it in the editor", run: runConfigCommand,
+		args: []string{"path", "edit"}},
+	{name: "refresh-cache", summary: "Load all calendars to update the cache of --motd", run: func([]string) { runRefreshCacheCommand() }},
+	{name: "google-login", summary: "Authorize access to Google calendars", run: func([]string) { runGoogleLoginCommand() }},
+	{name: "outlook-login", summary: "Authorize access to Outlook calendars", run: func([]string) { runOutlookLoginCommand() }},
+	{name: "completion", summary: "Print the shell completion script for bash, zsh or fish", run: runCompletionCommand,
+		args: []string{"bash", "zsh", "fish"}},
+}
+
+func findSubcommand(name string) *subcommand {
+	for i := range subcommands {
+		if subcommands[i].name == name {
+			return &subcommands[i]
+		}
+	}
+	return nil
+}
+
+const (
+	viewUsage   = "Usage: zebracal view [day|week|month|rolling] [--calendar NAME]"
+	nextUsage   = "Usage: zebracal next [N] [--within DURATION] [--json] [--calendar NAME]"
+	exportUsage = "Usage: zebracal export md|html [RANGE] | freebusy DAYS | pdf MONTH [--output FILE] | site DIR"
+	importUsage = "Usage: zebracal import [FILE]"
+)
+
+// commandFlags returns an empty flag set of the command name that prints
+// usage before its flags
+func commandFlags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseCommandArgs parses the flags in args with fs and returns the other
+// arguments, which may come before, between or after the flags
+func parseCommandArgs(fs *flag.FlagSet, args []string) []string {
+	var rest []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return rest
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// commandUsageError prints err and the usage of a command and exits
+func commandUsageError(err error, usage string) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n%s\n", err, usage)
+	os.Exit(2)
+}
+
+// viewFlags returns the flags of the view command, read into opts
+func viewFlags(opts *runOptions) *flag.FlagSet {
+	fs := commandFlags("view", viewUsage)
+	calendarFilterFlags(fs, opts)
+	debugFlags(fs, opts)
+	return fs
+}
+
+// runViewCommand starts the calendar, or prints the day, week, month or
+// rolling weeks and quits like --day, --week, ...
+func runViewCommand(args []string) {
+	var opts runOptions
+	views := parseCommandArgs(viewFlags(&opts), args)
+	if len(views) > 1 {
+		commandUsageError(fmt.Errorf("more than one view"), viewUsage)
+	}
+	if len(views) == 1 {
+		switch views[0] {
+		case "day":
+			opts.day = true
+		case "week":
+			opts.week = true
+		case "month":
+			opts.month = true
+		case "rolling":
+			opts.rolling = true
+		default:
+			commandUsageError(fmt.Errorf("unknown view %q (use day, week, month or rolling)", views[0]), viewUsage)
+		}
+	}
+	run(&opts, nil, time.Now())
+}
+
+// nextCommandFlags returns the flags of the next command, read into opts
+func nextCommandFlags(opts *runOptions) *flag.FlagSet {
+	fs := commandFlags("next", nextUsage)
+	nextFlags(fs, opts)
+	calendarFilterFlags(fs, opts)
+	debugFlags(fs, opts)
+	return fs
+}
+
+// runNextCommand shows the next N upcoming events and quits, like --next
+func runNextCommand(args []string) {
+	var opts runOptions
+	counts := parseCommandArgs(nextCommandFlags(&opts), args)
+	switch len(counts) {
+	case 0:
+		opts.next.set = true
+	case 1:
+		if err := opts.next.Set(counts[0]); err != nil {
+			commandUsageError(err, nextUsage)
+		}
+	default:
+		commandUsageError(fmt.Errorf("more than one count"), nextUsage)
+	}
+	run(&opts, nil, time.Now())
+}
+
+// exportFlags returns the flags of the export command, read into opts
+func exportFlags(opts *runOptions) *flag.FlagSet {
+	fs := commandFlags("export", exportUsage)
+	outputFlag(fs, opts)
+	calendarFilterFlags(fs, opts)
+	debugFlags(fs, opts)
+	return fs
+}
+
+// runExportCommand prints or writes the events in a format and quits, like
+// the flag of the format
+func runExportCommand(args []string) {
+	var opts runOptions
+	args = parseCommandArgs(exportFlags(&opts), args)
+	if len(args) == 0 || len(args) > 2 {
+		commandUsageError(fmt.Errorf("expected a format and its argument"), exportUsage)
+	}
+	format, arg := args[0], ""
+	if len(args) == 2 {
+		arg = args[1]
+	}
+
+	var err error
+	switch format {
+	case "md", "html":
+		export := &opts.exportMD
+		if format == "html" {
+			export = &opts.exportHTML
+		}
+		export.set = true
+		if arg != "" {
+			err = export.Set(arg)
+		}
+	case "freebusy":
+		if opts.freeBusy, err = strconv.Atoi(arg); err != nil || opts.freeBusy < 1 {
+			err = fmt.Errorf("expected a number of days, got %q", arg)
+		}
+	case "pdf":
+		if opts.printMonth = arg; arg == "" {
+			err = fmt.Errorf("expected a month like 2025-06")
+		}
+	case "site":
+		if opts.publish = arg; arg == "" {
+			err = fmt.Errorf("expected the directory to write to")
+		}
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		commandUsageError(err, exportUsage)
+	}
+	run(&opts, nil, time.Now())
+}
+
+// importFlags returns the flags of the import command, read into opts
+func importFlags(opts *runOptions) *flag.FlagSet {
+	fs := commandFlags("import", importUsage)
+	debugFlags(fs, opts)
+	return fs
+}
+
+// runImportCommand starts the calendar with a preview of the events of an
+// ICS file, or of stdin without one, like --stdin
+func runImportCommand(args []string) {
+	var opts runOptions
+	files := parseCommandArgs(importFlags(&opts), args)
+	if len(files) > 1 {
+		commandUsageError(fmt.Errorf("more than one file"), importUsage)
+	}
+
+	var input io.Reader = os.Stdin
+	if len(files) == 1 {
+		file, err := os.Open(files[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		input = file
+	}
+	run(&opts, input, time.Now())
+}
+
+// usage prints the subcommands and the flags of the calendar
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: zebracal [flags]\n       zebracal COMMAND [args]\n\nCommands:\n")
+	for _, cmd := range subcommands {
+		fmt.Fprintf(out, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// Completion scripts ask "zebracal __complete" for the candidates, passing
+// the words of the command line up to the one completed
+const (
+	bashCompletion = `_zebracal() {
+	local IFS=$'\n'
+	COMPREPLY=($(zebracal __complete "${COMP_WORDS[@]:0:COMP_CWORD+1}" 2>/dev/null))
+}
+complete -o default -F _zebracal zebracal
+`
+	zshCompletion = `#compdef zebracal
+_zebracal() {
+	local -a candidates
+	candidates=(${(f)"$(zebracal __complete "${(@)words[1,CURRENT]}" 2>/dev/null)"})
+	if (( ${#candidates} )); then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _zebracal zebracal
+`
+	fishCompletion = `complete -c zebracal -f -a '(zebracal __complete (commandline -opc) (commandline -ct) 2>/dev/null)'
+`
+)
+
+// runCompletionCommand prints a completion script, e.g. for
+// "source <(zebracal completion bash)" in .bashrc
+func runCompletionCommand(args []string) {
+	scripts := map[string]string{"bash": bashCompletion, "zsh": zshCompletion, "fish": fishCompletion}
+	if len(args) != 1 || scripts[args[0]] == "" {
+		fmt.Fprintln(os.Stderr, "Usage: zebracal completion <bash|zsh|fish>")
+		os.Exit(1)
+	}
+	fmt.Print(scripts[args[0]])
+}
+
+// calendarFlags are the flags that take a calendar name
+var calendarFlags = map[string]bool{"calendar": true, "exclude-calendar": true}
+
+// runCompleteCommand prints the candidates for the last of words, one per
+// line. words start with the program name; the flags of the calendar must
+// be defined.
+func runCompleteCommand(words []string) {
+	if len(words) < 2 {
+		return
+	}
+	words = words[1:]
+	current := words[len(words)-1]
+
+	var candidates []string
+	fs := flag.CommandLine
+	if cmd := findSubcommand(words[0]); cmd != nil && len(words) > 1 {
+		if len(words) == 2 {
+			candidates = append(candidates, cmd.args...)
+		}
+		fs = nil
+		if cmd.flags != nil {
+			fs = cmd.flags()
+		}
+	} else if len(words) == 1 {
+		for _, cmd := range subcommands {
+			candidates = append(candidates, cmd.name)
+		}
+	}
+
+	if fs != nil {
+		previous := ""
+		if len(words) > 1 {
+			previous = strings.TrimLeft(words[len(words)-2], "-")
+		}
+		switch {
+		case calendarFlags[previous] && fs.Lookup(previous) != nil:
+			candidates = completionCalendars()
+		case strings.HasPrefix(current, "-"):
+			candidates = nil
+			fs.VisitAll(func(f *flag.Flag) {
+				candidates = append(candidates, "--"+f.Name)
+			})
+		}
+	}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(current)) {
+			fmt.Println(candidate)
+		}
+	}
+}
+
+// completionCalendars returns the calendar names known without loading
+// the calendars: those in the config and in the cache of the last load
+func completionCalendars() []string {
+	seen := make(map[string]bool)
+	config, _ := loadConfig()
+	if config != nil {
+		for _, cal := range config.Calendars {
+			seen[cal.Name] = true
+		}
+		for _, local := range config.LocalCalendars {
+			seen[strings.TrimSuffix(filepath.Base(local), ".ics")] = true
+		}
+	}
+	if cache, _ := loadEventCache(); cache != nil {
+		for _, event := range cache.Events {
+			seen[event.CalendarName] = true
+		}
+	}
+
+	var names []string
+	for name := range seen {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
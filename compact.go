@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+)
+
+// minCompactSummary is the fewest cells of the summary worth showing before
+// the start and the countdown are dropped to make room
+const minCompactSummary = 8
+
+// renderCompactEvents renders events one per line of at most width cells,
+// for embedding in a status bar (--next --max-width). Lines are cut before
+// styling, so no escape sequence is ever split.
+func renderCompactEvents(events []Event, width int, now time.Time) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if len(events) == 0 {
+		return dimStyle.Render(truncate(tr("No upcoming events"), width))
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, renderCompactEvent(event, width, now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderCompactEvent renders "15:04 Summary (in 5m)" in at most width
+// cells. The summary is cut first; the countdown and then the start are
+// left out when not even minCompactSummary cells of it would fit.
+func renderCompactEvent(event Event, width int, now time.Time) string {
+	when := event.Start.Format("15:04")
+	if isAllDay(event) {
+		when = tr("all day")
+	}
+	if dayIndex(now, event.Start) > 0 {
+		when = formatDate(event.Start, "Mon") + " " + when
+	}
+	until := strings.TrimSpace(formatTimeUntil(event.Start, now))
+	summary := strings.Join(strings.Fields(event.Summary), " ")
+
+	whenWidth, untilWidth := uniseg.StringWidth(when)+1, uniseg.StringWidth(until)+1
+	if whenWidth+minCompactSummary+untilWidth > width {
+		until, untilWidth = "", 0
+	}
+	if whenWidth+minCompactSummary > width {
+		when, whenWidth = "", 0
+	}
+
+	var b strings.Builder
+	if when != "" {
+		b.WriteString(timeStyle.Render(when) + " ")
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(eventColor(event)).Render(truncate(summary, width-whenWidth-untilWidth)))
+	if until != "" {
+		b.WriteString(" " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(until))
+	}
+	return b.String()
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/user"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"mytuiapp/internal/ical"
 )
 
 func getConfigDir() (string, error) {
@@ -17,33 +26,128 @@ func getConfigDir() (string, error) {
 	return configDir, nil
 }
 
-func loadConfig() (*Config, error) {
-	// Try current directory first (dev mode)
-	localConfig := "calendars.json"
-	if _, err := os.Stat(localConfig); err == nil {
-		file, err := os.Open(localConfig)
-		if err != nil {
-			return nil, err
-		}
-		defer file.Close()
+// getStatePath returns the path of a state file kept next to the config
+func getStatePath(name string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, name), nil
+}
+
+// writeFileAtomic writes a file by renaming a temporary one over it, so
+// readers see the old or the new contents but never a partial write
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// setFloatingTimezone sets the time zone floating event times are read in
+func setFloatingTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid floating_timezone: %v", err)
+	}
+	ical.FloatingLocation = loc
+	return nil
+}
 
-		var config Config
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(&config); err != nil {
-			return nil, err
+// setPalette selects the calendar colors. Without a name the default
+// palette is used, or the basic one if the terminal lacks 256 colors.
+func setPalette(name string) error {
+	if name == "" {
+		name = "default"
+		if lipgloss.ColorProfile() >= termenv.ANSI {
+			name = "basic"
 		}
+	}
+	palette, ok := palettes[name]
+	if !ok {
+		return fmt.Errorf("unknown palette %q (use default, colorblind or basic)", name)
+	}
+	calendarColors = palette
+	return nil
+}
 
-		return &config, nil
+// configPath returns the config file in use
+func configPath() (string, error) {
+	// Try current directory first (dev mode)
+	localConfig := "calendars.json"
+	if _, err := os.Stat(localConfig); err == nil {
+		return localConfig, nil
 	}
 
 	// Fall back to standard config directory (build version)
 	configDir, err := getConfigDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get config directory: %v", err)
+		return "", fmt.Errorf("failed to get config directory: %v", err)
+	}
+	return filepath.Join(configDir, "calendars.json"), nil
+}
+
+const configUsage = "Usage: zebracal config [path|edit]"
+
+// runConfigCommand prints the path of the config file, or opens it in the
+// editor and checks that it still loads
+func runConfigCommand(args []string) {
+	path, err := configPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	switch {
+	case len(args) == 0 || len(args) == 1 && args[0] == "path":
+		fmt.Println(path)
+	case len(args) == 1 && args[0] == "edit":
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		editor := strings.Fields(cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi"))
+		cmd := exec.Command(editor[0], append(editor[1:], path)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := loadConfig(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: the config doesn't load: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, configUsage)
+		os.Exit(2)
 	}
+}
 
-	configPath := filepath.Join(configDir, "calendars.json")
-	file, err := os.Open(configPath)
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+// contact is one address of a contact, offered when adding attendees
+type contact struct {
+	name    string
+	address string // lowercase
+}
+
+// String formats the contact as an entry of the attendees field
+func (c contact) String() string {
+	if c.name == "" {
+		return c.address
+	}
+	name := c.name
+	if strings.ContainsAny(name, `,;:<>@"()`) {
+		name = `"` + strings.ReplaceAll(name, `"`, "") + `"`
+	}
+	return name + " <" + c.address + ">"
+}
+
+// contactsLoadedMsg brings the contacts loaded in the background
+type contactsLoadedMsg struct {
+	contacts []contact
+	err      error
+}
+
+// loadContactsCmd loads the configured contacts source in the background
+func loadContactsCmd(config *Config) tea.Cmd {
+	if config == nil || config.Contacts == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		contacts, err := loadContacts(config.Contacts, config.Radicale)
+		return contactsLoadedMsg{contacts: contacts, err: err}
+	}
+}
+
+// loadContacts reads the contacts file and the CardDAV address book, sorted
+// by name with each address once
+func loadContacts(config *ContactsConfig, radicale *RadicaleConfig) ([]contact, error) {
+	var contacts []contact
+	if config.File != "" {
+		data, err := os.ReadFile(expandHome(config.File))
+		if err != nil {
+			return nil, fmt.Errorf("contacts file: %v", err)
+		}
+		if strings.Contains(string(data), "BEGIN:VCARD") {
+			contacts = append(contacts, parseVCards(string(data))...)
+		} else {
+			contacts = append(contacts, parseContactLines(string(data))...)
+		}
+	}
+	if config.URL != "" {
+		cards, err := newCalDAVClient(radicale).FetchAddressBook(config.URL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("address book: %v", err)
+		}
+		for _, card := range cards {
+			contacts = append(contacts, parseVCards(card)...)
+		}
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(contacts[i].String()) < strings.ToLower(contacts[j].String())
+	})
+	seen := make(map[string]bool)
+	unique := contacts[:0]
+	for _, c := range contacts {
+		if !seen[c.address] {
+			seen[c.address] = true
+			unique = append(unique, c)
+		}
+	}
+	return unique, nil
+}
+
+// parseVCards returns a contact for each EMAIL of the vCards in data, named
+// by the card's FN
+func parseVCards(data string) []contact {
+	// Unfold continuation lines first
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var contacts []contact
+	var name string
+	var addresses []string
+	for _, line := range strings.Split(data, "\n") {
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters and group prefixes like "item1.EMAIL;TYPE=work"
+		property, _, _ = strings.Cut(property, ";")
+		if i := strings.LastIndex(property, "."); i >= 0 {
+			property = property[i+1:]
+		}
+
+		switch strings.ToUpper(property) {
+		case "BEGIN":
+			name, addresses = "", nil
+		case "FN":
+			name = strings.ReplaceAll(strings.TrimSpace(value), `\,`, ",")
+		case "EMAIL":
+			if address := ical.MailAddress(value); address != "" {
+				addresses = append(addresses, address)
+			}
+		case "END":
+			for _, address := range addresses {
+				contacts = append(contacts, contact{name: name, address: address})
+			}
+		}
+	}
+	return contacts
+}
+
+// parseContactLines reads a contacts file of "Name <address>" or bare
+// address lines. Blank lines and lines starting with # are skipped.
+func parseContactLines(data string) []contact {
+	var contacts []contact
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if addr, err := mail.ParseAddress(line); err == nil {
+			contacts = append(contacts, contact{name: addr.Name, address: strings.ToLower(addr.Address)})
+		}
+	}
+	return contacts
+}
+
+// parseAttendees reads the attendees field, a comma-separated list of
+// "Name <address>" or bare addresses. Names missing from the field are
+// taken from the contacts.
+func parseAttendees(field string, contacts []contact) ([]string, map[string]string, error) {
+	field = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(field), ","))
+	if field == "" {
+		return nil, nil, nil
+	}
+	list, err := mail.ParseAddressList(field)
+	if err != nil {
+		return nil, nil, fmt.Errorf("use addresses like \"Jane Doe <jane@example.com>\" separated by commas")
+	}
+
+	var addresses []string
+	names := make(map[string]string)
+	for _, addr := range list {
+		address := strings.ToLower(addr.Address)
+		addresses = append(addresses, address)
+		name := addr.Name
+		if name == "" {
+			for _, c := range contacts {
+				if c.address == address {
+					name = c.name
+					break
+				}
+			}
+		}
+		if name != "" {
+			names[address] = name
+		}
+	}
+	return addresses, names, nil
+}
+
+// attendeeSuggestions completes the last entry of the attendees field with
+// the contacts, keeping the entries before it. Completion matches the start
+// of the entry, so contacts are offered by name and by address.
+func attendeeSuggestions(field string, contacts []contact) []string {
+	prefix := ""
+	if i := strings.LastIndex(field, ","); i >= 0 {
+		prefix = strings.TrimRight(field[:i+1], " ") + " "
+	}
+	suggestions := make([]string, 0, 2*len(contacts))
+	for _, c := range contacts {
+		suggestions = append(suggestions, prefix+c.String())
+		if c.name != "" {
+			suggestions = append(suggestions, prefix+c.address)
+		}
+	}
+	return suggestions
+}
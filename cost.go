@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// hourlyRate returns the meeting cost per attendee and hour of a calendar,
+// 0 if none is configured
+func (c *Config) hourlyRate(name string) float64 {
+	if c == nil {
+		return 0
+	}
+	for _, cal := range c.Calendars {
+		if cal.Name == name {
+			return cal.HourlyRate
+		}
+	}
+	return 0
+}
+
+// meetingCost estimates what d of an event costs: the calendar's hourly
+// rate for each attendee, counting the organizer, or just for me without
+// attendees. All-day events cost nothing.
+func (c *Config) meetingCost(event Event, d time.Duration) float64 {
+	rate := c.hourlyRate(event.CalendarName)
+	if rate <= 0 || isAllDay(event) || d <= 0 {
+		return 0
+	}
+	people := make(map[string]bool)
+	for _, address := range append([]string{event.Organizer}, event.Attendees...) {
+		if address != "" {
+			people[address] = true
+		}
+	}
+	return rate * d.Hours() * float64(max(1, len(people)))
+}
+
+// formatCost shows a cost as a rounded estimate, like "~CHF 450"
+func (c *Config) formatCost(cost float64) string {
+	currency := ""
+	if c != nil && strings.TrimSpace(c.Currency) != "" {
+		currency = strings.TrimSpace(c.Currency) + " "
+	}
+	return fmt.Sprintf("~%s%.0f", currency, cost)
+}
+
+// eventCost is the estimated cost shown after an event, "" without an
+// hourly rate
+func (m model) eventCost(event Event) string {
+	cost := m.config.meetingCost(event, event.End.Sub(event.Start))
+	if cost == 0 {
+		return ""
+	}
+	return m.config.formatCost(cost)
+}
+
+// weekCost is the estimated cost of the week's meetings
+func (m model) weekCost(weekStart time.Time) float64 {
+	total := 0.0
+	for i := 0; i < 7; i++ {
+		day := weekStart.AddDate(0, 0, i)
+		for _, event := range m.getEventsForDay(day) {
+			total += m.config.meetingCost(event, dayDuration(event, day))
+		}
+	}
+	return total
+}
@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// focusEvent shows the day of the event with the given UID in the daily
+// view with the cursor on it. For recurring events the next occurrence is
+// used, or the last one if all are past.
+func (m model) focusEvent(uid string) model {
+	var target *Event
+	now := m.clock.Now()
+	for i := range m.events {
+		event := &m.events[i]
+		if event.UID != uid {
+			continue
+		}
+		switch {
+		case target == nil:
+			target = event
+		case !event.End.Before(now) && (target.End.Before(now) || event.Start.Before(target.Start)):
+			// An ongoing or upcoming occurrence, earlier than the current pick
+			target = event
+		case target.End.Before(now) && event.Start.After(target.Start):
+			// Only past occurrences so far: prefer the latest
+			target = event
+		}
+	}
+
+	if target == nil {
+		m.message = fmt.Sprintf("Event %s not found", uid)
+		return m
+	}
+
+	m = m.jumpTo(target.Start, DailyView)
+	for i, event := range m.dailyEvents() {
+		if eventKey(event) == eventKey(*target) {
+			m.cursor = i
+			break
+		}
+	}
+	return m
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/net/html"
+)
+
+// htmlTag finds the tags of HTML descriptions, as sent by Outlook and Google
+var htmlTag = regexp.MustCompile(`(?i)<(/?(p|div|span|br|b|strong|i|em|a|ul|ol|li|table|tr|td|html|body|font|h[1-6])\b[^>]*|!--)>?`)
+
+// descriptionMarkdown returns an event's description as Markdown: the HTML
+// version (X-ALT-DESC) or an HTML description converted, else the text as
+// is, which is often Markdown-ish already
+func descriptionMarkdown(event Event) string {
+	if event.HTML != "" {
+		return htmlToMarkdown(event.HTML)
+	}
+	if htmlTag.MatchString(event.Description) {
+		return htmlToMarkdown(event.Description)
+	}
+	return event.Description
+}
+
+// plainDescription returns a description as readable text, with HTML tags
+// turned into line breaks and list bullets and meeting boilerplate removed
+func plainDescription(description string) string {
+	if htmlTag.MatchString(description) {
+		description = htmlToMarkdown(description)
+		// Keep the link texts and drop the addresses, which the boxes have no room for
+		description = markdownLink.ReplaceAllString(description, "$1")
+		description = markdownEmphasis.ReplaceAllString(description, "$1")
+	}
+	text, _ := cleanDescription(description)
+	return text
+}
+
+var (
+	markdownLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownEmphasis = regexp.MustCompile(`\*\*?([^*]+)\*\*?`)
+)
+
+// htmlToMarkdown converts the formatting invites use (paragraphs, bold,
+// italics, lists and links) to Markdown and drops the rest of the markup
+func htmlToMarkdown(source string) string {
+	var b strings.Builder
+	var links []string // Targets of the open <a> tags
+	var lists []int    // Item numbers of the open lists, 0 for bullets
+	skip := 0          // Depth inside <style>, <script> and <head>
+
+	tokenizer := html.NewTokenizer(strings.NewReader(source))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return tidyMarkdown(b.String())
+		case html.TextToken:
+			if skip == 0 {
+				writeText(&b, string(tokenizer.Text()))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "style", "script", "head", "title":
+				skip++
+			case "br":
+				b.WriteString("\n")
+			case "p", "div", "tr", "table", "h1", "h2", "h3", "h4", "h5", "h6":
+				b.WriteString("\n\n")
+			case "b", "strong":
+				b.WriteString("**")
+			case "i", "em":
+				b.WriteString("*")
+			case "ul":
+				lists = append(lists, 0)
+				b.WriteString("\n")
+			case "ol":
+				lists = append(lists, 1)
+				b.WriteString("\n")
+			case "li":
+				indent := strings.Repeat("  ", max(0, len(lists)-1))
+				if len(lists) > 0 && lists[len(lists)-1] > 0 {
+					fmt.Fprintf(&b, "\n%s%d. ", indent, lists[len(lists)-1])
+					lists[len(lists)-1]++
+				} else {
+					b.WriteString("\n" + indent + "- ")
+				}
+			case "a":
+				href := ""
+				for hasAttr {
+					var key, value []byte
+					key, value, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						href = string(value)
+					}
+				}
+				links = append(links, href)
+				b.WriteString("[")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "style", "script", "head", "title":
+				skip = max(0, skip-1)
+			case "p", "div", "table", "h1", "h2", "h3", "h4", "h5", "h6":
+				b.WriteString("\n\n")
+			case "b", "strong":
+				b.WriteString("**")
+			case "i", "em":
+				b.WriteString("*")
+			case "ul", "ol":
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				b.WriteString("\n\n")
+			case "a":
+				if len(links) > 0 {
+					href := links[len(links)-1]
+					links = links[:len(links)-1]
+					if href != "" && !strings.HasPrefix(href, "#") {
+						b.WriteString("](" + href + ")")
+					} else {
+						b.WriteString("]")
+					}
+				}
+			}
+		}
+	}
+}
+
+// writeText writes text with its whitespace collapsed, since line breaks
+// come from the tags
+func writeText(b *strings.Builder, text string) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		if text != "" && !strings.HasSuffix(b.String(), "\n") && !strings.HasSuffix(b.String(), " ") {
+			b.WriteString(" ")
+		}
+		return
+	}
+	if strings.TrimLeft(text, " \t\r\n") != text && b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") && !strings.HasSuffix(b.String(), " ") {
+		b.WriteString(" ")
+	}
+	b.WriteString(strings.Join(words, " "))
+	if strings.TrimRight(text, " \t\r\n") != text {
+		b.WriteString(" ")
+	}
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// tidyMarkdown drops the trailing spaces and extra blank lines the
+// conversion leaves
+func tidyMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// renderMarkdown renders Markdown for the terminal, wrapped to width and
+// keeping the line breaks of plain text descriptions. The style follows the
+// background lipgloss detected at startup, as glamour's own detection would
+// query the terminal while Bubble Tea reads from it.
+func renderMarkdown(text string, width int) string {
+	style := "light"
+	if lipgloss.HasDarkBackground() {
+		style = "dark"
+	}
+	renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(width), glamour.WithPreservedNewLines())
+	if err == nil {
+		if out, err := renderer.Render(text); err == nil {
+			return strings.Trim(out, "\n")
+		}
+	}
+	return lipgloss.NewStyle().Width(width).Render(text)
+}
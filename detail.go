@@ -0,0 +1,159 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// eventDetail is the detail panel of an event (enter in the daily view)
+type eventDetail struct {
+	event  Event
+	offset int // First line shown, when the panel is taller than the terminal
+}
+
+// openDetail shows the focused event of the daily view in the detail panel
+func (m model) openDetail() model {
+	if dayEvents := m.dailyEvents(); m.cursor < len(dayEvents) {
+		m.detail = &eventDetail{event: dayEvents[m.cursor]}
+		m.message = ""
+	}
+	return m
+}
+
+// handleDetailKey scrolls the detail panel, and esc, enter or q closes it
+func (m model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	detail := *m.detail
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc", "enter", "q":
+		m.detail = nil
+		return m, nil
+	case "down", "j":
+		detail.offset++
+	case "up", "k":
+		detail.offset--
+	case "pgdown", "f":
+		detail.offset += max(1, m.height-4)
+	case "pgup", "b":
+		detail.offset -= max(1, m.height-4)
+	case "g", "home":
+		detail.offset = 0
+	case "o":
+		// The event's own URL, else the meeting's join link
+		_, join := cleanDescription(descriptionMarkdown(detail.event))
+		if link := cmp.Or(detail.event.URL, join); link != "" && !m.redact {
+			if err := openURL(link); err != nil {
+				m.message = fmt.Sprintf("Error: %v", err)
+			}
+		}
+	}
+	// The view clamps the end, which depends on the width
+	detail.offset = max(0, detail.offset)
+	m.detail = &detail
+	return m, nil
+}
+
+// viewDetail renders the event with its attendees and its description as
+// Markdown
+func (m model) viewDetail() string {
+	width := 80
+	if m.width > 0 {
+		width = min(100, m.width)
+	}
+	lines := strings.Split(strings.TrimSuffix(m.renderDetail(m.detail.event, width), "\n"), "\n")
+	help := renderHelp([]string{"j/k: scroll", "o: open link", "esc: close"})
+	if m.message != "" {
+		help += "  " + helpStyle.Render(m.message)
+	}
+	if m.height > 0 {
+		// Leave a line for the help
+		page := max(1, m.height-2)
+		offset := min(m.detail.offset, max(0, len(lines)-page))
+		lines = lines[offset:min(len(lines), offset+page)]
+	}
+	return strings.Join(lines, "\n") + "\n\n" + help
+}
+
+// renderDetail renders an event's title, fields and description
+func (m model) renderDetail(event Event, width int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	field := func(label, value string) string {
+		return labelStyle.Render(fmt.Sprintf("%-10s", tr(label))) + " " + value + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(eventColor(event)).Bold(true).Render(eventMarker(event)+m.eventTitle(event)) + "\n\n")
+
+	when := formatDate(event.Start, "Mon Jan 2, 2006") + "  " + event.Start.Format("15:04") + " - " + event.End.Format("15:04")
+	if isAllDay(event) {
+		when = formatDate(event.Start, "Mon Jan 2, 2006") + "  " + tr("all day")
+	}
+	b.WriteString(field("When", when))
+	if event.RRule != "" {
+		b.WriteString(field("Repeats", humanizeRRule(event.RRule, event.Start)))
+	}
+	if location := m.eventLocation(event); location != "" {
+		b.WriteString(field("Where", location))
+	}
+	b.WriteString(field("Calendar", calendarLabel(event)))
+	if cost := m.eventCost(event); cost != "" {
+		b.WriteString(field("Cost", cost))
+	}
+	// Meeting boilerplate is reduced to its join link
+	description, join := cleanDescription(descriptionMarkdown(event))
+	if !m.redact {
+		if event.URL != "" {
+			b.WriteString(field("URL", event.URL))
+		}
+		if join != "" {
+			b.WriteString(field("Join", join))
+		}
+		if event.Organizer != "" {
+			b.WriteString(field("Organizer", attendeeLabel(event, event.Organizer)))
+		}
+		for i, attendee := range event.Attendees {
+			label := ""
+			if i == 0 {
+				label = "Attendees"
+			}
+			b.WriteString(field(label, attendeeLabel(event, attendee)))
+		}
+	}
+
+	if m.redact && event.Description != "" {
+		b.WriteString("\n" + noEventsStyle.Render(tr(redactedDescription)) + "\n")
+	} else if description = strings.TrimSpace(description); description != "" {
+		b.WriteString("\n" + renderMarkdown(description, width-4) + "\n")
+	}
+	return b.String()
+}
+
+// openURL opens a link in the desktop's browser
+func openURL(link string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+	cmd := exec.Command(opener, link)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap it without waiting for the browser
+	go cmd.Wait()
+	return nil
+}
+
+// attendeeLabel names an attendee by their display name and address
+func attendeeLabel(event Event, address string) string {
+	if name := event.AttendeeNames[address]; name != "" && name != address {
+		return name + " <" + address + ">"
+	}
+	return cmp.Or(address, "-")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"mytuiapp/internal/caldav"
+)
+
+const (
+	discoveryCacheFile  = "discovery.json"
+	defaultDiscoveryTTL = 24 * time.Hour
+)
+
+// discoveryCache is the result of the last calendar discovery on the
+// Radicale server, so startup can skip the PROPFINDs
+type discoveryCache struct {
+	ServerURL string            `json:"server_url"`
+	Username  string            `json:"username"`
+	SavedAt   time.Time         `json:"saved_at"`
+	Calendars []caldav.Calendar `json:"calendars"`
+}
+
+func discoveryTTL(account *RadicaleConfig) time.Duration {
+	if account.DiscoveryTTLMinutes > 0 {
+		return time.Duration(account.DiscoveryTTLMinutes) * time.Minute
+	}
+	return defaultDiscoveryTTL
+}
+
+// cachedDiscovery returns the calendars discovered for the account within
+// the TTL, or false if they need discovering again
+func cachedDiscovery(account *RadicaleConfig, now time.Time) ([]caldav.Calendar, bool) {
+	cachePath, err := getStatePath(discoveryCacheFile)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+	var cache discoveryCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false
+	}
+	if cache.ServerURL != account.ServerURL || cache.Username != account.Username ||
+		now.Sub(cache.SavedAt) > discoveryTTL(account) || len(cache.Calendars) == 0 {
+		return nil, false
+	}
+	return cache.Calendars, true
+}
+
+func saveDiscovery(account *RadicaleConfig, calendars []caldav.Calendar, now time.Time) error {
+	cachePath, err := getStatePath(discoveryCacheFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(discoveryCache{
+		ServerURL: account.ServerURL,
+		Username:  account.Username,
+		SavedAt:   now,
+		Calendars: calendars,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, data, 0644)
+}
+
+// clearDiscovery makes the next load discover the calendars again
+func clearDiscovery() {
+	if cachePath, err := getStatePath(discoveryCacheFile); err == nil {
+		os.Remove(cachePath)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import "strings"
+
+// Nil receivers are allowed so missing day_view/week_view/month_view
+// sections give the defaults.
+
+func (c *DayViewConfig) showDescription() bool {
+	return c == nil || c.ShowDescription == nil || *c.ShowDescription
+}
+
+func (c *DayViewConfig) showLocation() bool {
+	return c == nil || c.ShowLocation == nil || *c.ShowLocation
+}
+
+func (c *DayViewConfig) showCalendarName() bool {
+	return c != nil && c.ShowCalendarName
+}
+
+func (c *DayViewConfig) splitLayout() bool {
+	return c != nil && c.Layout == "split"
+}
+
+func (c *WeekViewConfig) showEndTime() bool {
+	return c == nil || c.ShowEndTime == nil || *c.ShowEndTime
+}
+
+func (c *WeekViewConfig) showLocation() bool {
+	return c != nil && c.ShowLocation
+}
+
+func (c *WeekViewConfig) showCalendarName() bool {
+	return c != nil && c.ShowCalendarName
+}
+
+func (c *MonthViewConfig) showHeatmap() bool {
+	return c == nil || c.Heatmap == nil || *c.Heatmap
+}
+
+// dayViewConfig returns the day_view section, nil if not configured
+func (m model) dayViewConfig() *DayViewConfig {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.DayView
+}
+
+// weekViewConfig returns the week_view section, nil if not configured
+func (m model) weekViewConfig() *WeekViewConfig {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.WeekView
+}
+
+// monthViewConfig returns the month_view section, nil if not configured
+func (m model) monthViewConfig() *MonthViewConfig {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.MonthView
+}
+
+// eventLocation returns the location to display, hidden in redact mode
+func (m model) eventLocation(event Event) string {
+	if m.redact {
+		return ""
+	}
+	return event.Location
+}
+
+// eventDetails joins the optional location and calendar name shown after
+// an event's title, "" if neither is enabled or set
+func (m model) eventDetails(event Event, showLocation, showCalendar bool) string {
+	details := ""
+	if location := m.eventLocation(event); showLocation && location != "" {
+		details += " · " + location
+	}
+	// Merged duplicates always list their calendars
+	if showCalendar || len(event.AlsoIn) > 0 {
+		details += " · " + calendarLabel(event)
+	}
+	if cost := m.eventCost(event); cost != "" {
+		details += " · " + cost
+	}
+	return details
+}
+
+// calendarLabel names the event's calendar, or all of them for merged
+// duplicates: "Work + Personal"
+func calendarLabel(event Event) string {
+	return strings.Join(append([]string{event.CalendarName}, event.AlsoIn...), " + ")
+}
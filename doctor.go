@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// runDoctorCommand checks the config, its settings and that every calendar
+// loads, printing a line for each check. It exits with 1 if any failed.
+func runDoctorCommand() {
+	failed := false
+	check := func(name string, err error) {
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", name, err)
+			failed = true
+		} else {
+			fmt.Printf("✓ %s\n", name)
+		}
+	}
+
+	path, err := configPath()
+	if err != nil {
+		check("config", err)
+		os.Exit(1)
+	}
+	config, err := loadConfig()
+	check("config "+path, err)
+	if config == nil {
+		os.Exit(1)
+	}
+
+	setLocale(config.Locale)
+	check("http", setupHTTPClient(config.HTTP))
+	check("floating_timezone", setFloatingTimezone(config.FloatingTimezone))
+	check("descriptions", setDescriptionCleaners(config.Descriptions))
+	check("day_parts", setDayParts(config.DayParts))
+	check("palette", setPalette(config.Palette))
+
+	var warnings []string
+	events, calendars, _, err := loadAllCalendars(systemClock{}, config.Radicale, nil, func(message string) {
+		warnings = append(warnings, message)
+	})
+	for _, warning := range warnings {
+		fmt.Printf("✗ %s\n", warning)
+		failed = true
+	}
+	if err != nil {
+		check("calendars", err)
+	}
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.CalendarName]++
+	}
+	var names []string
+	for name := range calendars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("✓ %s: %d events\n", name, counts[name])
+	}
+
+	ops, err := loadPendingOps()
+	check("offline queue", err)
+	if len(ops) > 0 {
+		fmt.Printf("  %d writes waiting for the server\n", len(ops))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
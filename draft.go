@@ -0,0 +1,169 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const draftFile = "draft.json"
+
+// formDraft is what was typed into the creation form before it was left
+// without saving. It is kept on disk so it survives the terminal closing.
+type formDraft struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	Attendees   string   `json:"attendees,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Date        string   `json:"date"`
+	StartTime   string   `json:"start_time,omitempty"`
+	EndTime     string   `json:"end_time,omitempty"`
+	EndDate     string   `json:"end_date,omitempty"`
+	Calendar    string   `json:"calendar,omitempty"`
+	Repeat      string   `json:"repeat,omitempty"`
+	RepeatUntil string   `json:"repeat_until,omitempty"`
+	RepeatEvery string   `json:"repeat_every,omitempty"`
+	RepeatDays  []string `json:"repeat_days,omitempty"`
+	RepeatOn    string   `json:"repeat_on,omitempty"`
+	RepeatCount string   `json:"repeat_count,omitempty"`
+}
+
+// empty reports whether nothing worth restoring was typed
+func (d formDraft) empty() bool {
+	return strings.TrimSpace(d.Summary) == "" && strings.TrimSpace(d.Description) == ""
+}
+
+// currentDraft returns the form's current values
+func (m model) currentDraft() formDraft {
+	return formDraft{
+		Summary:     *m.formSummary,
+		Description: *m.formDescription,
+		Attendees:   *m.formAttendees,
+		URL:         *m.formURL,
+		Date:        *m.formDate,
+		StartTime:   *m.formStartTime,
+		EndTime:     *m.formEndTime,
+		EndDate:     *m.formEndDate,
+		Calendar:    *m.formCalendar,
+		Repeat:      *m.formRepeatOptions,
+		RepeatUntil: *m.formRepeatEndDate,
+		RepeatEvery: *m.formRepeatEvery,
+		RepeatDays:  *m.formRepeatDays,
+		RepeatOn:    *m.formRepeatMonthly,
+		RepeatCount: *m.formRepeatCount,
+	}
+}
+
+// restoreDraft fills the form with the unsaved draft and rebuilds it
+func (m model) restoreDraft() (tea.Model, tea.Cmd) {
+	d := *m.draft
+	*m.formSummary = d.Summary
+	*m.formDescription = d.Description
+	*m.formAttendees = d.Attendees
+	*m.formURL = d.URL
+	*m.formDate = d.Date
+	*m.formStartTime = d.StartTime
+	*m.formEndTime = d.EndTime
+	*m.formEndDate = d.EndDate
+	*m.formRepeatOptions = d.Repeat
+	*m.formRepeatEndDate = d.RepeatUntil
+	*m.formRepeatEvery = d.RepeatEvery
+	*m.formRepeatDays = d.RepeatDays
+	*m.formRepeatMonthly = cmp.Or(d.RepeatOn, "day")
+	*m.formRepeatCount = d.RepeatCount
+	// The calendar may be gone since the draft was written
+	if _, ok := m.calendars[d.Calendar]; ok {
+		*m.formCalendar = d.Calendar
+	}
+	m.draftPrompt = false
+	m.eventForm = m.buildForm()
+	if m.width > 0 {
+		m.eventForm = m.eventForm.WithWidth(m.width)
+	}
+	return m, m.eventForm.Init()
+}
+
+// handleDraftPrompt answers the question whether to restore the draft when
+// the form opens. Declining discards the draft.
+func (m model) handleDraftPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		return m.restoreDraft()
+	case "n", "esc":
+		m.draftPrompt = false
+		m.draft = nil
+		clearDraft()
+	}
+	return m, nil
+}
+
+// trackDraft keeps the draft in step with the form after each change.
+// Clearing the summary and description drops it.
+func (m model) trackDraft() model {
+	if m.draftPrompt {
+		// The form still holds the empty values, not the draft
+		return m
+	}
+	d := m.currentDraft()
+	if d.empty() {
+		if m.draft != nil {
+			m.draft = nil
+			clearDraft()
+		}
+		return m
+	}
+	if m.draft == nil || !reflect.DeepEqual(*m.draft, d) {
+		m.draft = &d
+		// A failed write still leaves the draft in memory until quitting
+		saveDraft(d)
+	}
+	return m
+}
+
+func loadDraft() (*formDraft, error) {
+	draftPath, err := getStatePath(draftFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(draftPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var d formDraft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	if d.empty() {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+func saveDraft(d formDraft) error {
+	draftPath, err := getStatePath(draftFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(draftPath, data, 0644)
+}
+
+// clearDraft removes the draft once it is saved or discarded
+func clearDraft() {
+	if draftPath, err := getStatePath(draftFile); err == nil {
+		os.Remove(draftPath)
+	}
+}
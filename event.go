@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,14 +23,18 @@ func parseNaturalLanguage(input string, baseTime time.Time) (*Event, error) {
 
 	// Parse date
 	date := baseTime
+	weekdayNames := make([]string, 0, 14)
+	for name := range nlWeekdays() {
+		weekdayNames = append(weekdayNames, regexp.QuoteMeta(name))
+	}
 	datePatterns := []struct {
 		pattern *regexp.Regexp
 		parse   func(string, time.Time) time.Time
 	}{
-		{regexp.MustCompile(`\btoday\b`), func(_ string, base time.Time) time.Time { return base }},
-		{regexp.MustCompile(`\btomorrow\b`), func(_ string, base time.Time) time.Time { return base.AddDate(0, 0, 1) }},
-		{regexp.MustCompile(`\bnext week\b`), func(_ string, base time.Time) time.Time { return base.AddDate(0, 0, 7) }},
-		{regexp.MustCompile(`\b(monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`), parseWeekday},
+		{regexp.MustCompile(`\b(` + nlAlternatives(func(w nlWords) []string { return w.today }) + `)\b`), func(_ string, base time.Time) time.Time { return base }},
+		{regexp.MustCompile(`\b(` + nlAlternatives(func(w nlWords) []string { return w.tomorrow }) + `)\b`), func(_ string, base time.Time) time.Time { return base.AddDate(0, 0, 1) }},
+		{regexp.MustCompile(`\b(` + nlAlternatives(func(w nlWords) []string { return w.nextWeek }) + `)\b`), func(_ string, base time.Time) time.Time { return base.AddDate(0, 0, 7) }},
+		{regexp.MustCompile(`\b(` + strings.Join(weekdayNames, "|") + `)\b`), parseWeekday},
 	}
 
 	for _, dp := range datePatterns {
@@ -40,15 +45,51 @@ func parseNaturalLanguage(input string, baseTime time.Time) (*Event, error) {
 		}
 	}
 
+	// A part of the day like "all afternoon" sets both ends, so no time or
+	// duration is read
+	parts := nlDayParts()
+	partNames := make([]string, 0, len(parts))
+	for name := range parts {
+		partNames = append(partNames, name)
+	}
+	// Longest first, so "all day" wins over a configured "day"
+	sort.Slice(partNames, func(i, j int) bool {
+		return len(partNames[i]) > len(partNames[j])
+	})
+	for _, name := range partNames {
+		pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+		if !pattern.MatchString(input) {
+			continue
+		}
+		part := parts[name]
+		if part.allDay {
+			event.Start = dayStart(date)
+			event.End = event.Start.AddDate(0, 0, 1)
+		} else {
+			event.Start = atClock(date, part.start)
+			event.End = atClock(date, part.end)
+		}
+		// A single word like "lunch" names the event, "all afternoon" doesn't
+		if strings.Contains(name, " ") {
+			input = pattern.ReplaceAllString(input, "")
+		}
+		event.Summary = nlSummary(input)
+		return event, nil
+	}
+
 	// Parse time
 	startTime := date
+	timeWordNames := make([]string, 0)
+	for word := range nlTimeWords() {
+		timeWordNames = append(timeWordNames, regexp.QuoteMeta(word))
+	}
 	timePatterns := []struct {
 		pattern *regexp.Regexp
 		parse   func(string, time.Time) time.Time
 	}{
 		{regexp.MustCompile(`\b(\d{1,2}):(\d{2})\s*(am|pm)?\b`), parseTime},
 		{regexp.MustCompile(`\b(\d{1,2})\s*(am|pm)\b`), parseTimeSimple},
-		{regexp.MustCompile(`\b(morning|afternoon|evening|noon|midnight)\b`), parseTimeWord},
+		{regexp.MustCompile(`\b(` + strings.Join(timeWordNames, "|") + `)\b`), parseTimeWord},
 	}
 
 	for _, tp := range timePatterns {
@@ -61,26 +102,96 @@ func parseNaturalLanguage(input string, baseTime time.Time) (*Event, error) {
 
 	// Extract duration
 	duration := time.Hour
-	if match := regexp.MustCompile(`\b(\d+)\s*(hour|hours|h|minute|minutes|min)\b`).FindStringSubmatch(input); match != nil {
+	hourUnits := nlAlternatives(func(w nlWords) []string { return w.hourUnits })
+	minuteUnits := nlAlternatives(func(w nlWords) []string { return w.minuteUnits })
+	durationPattern := regexp.MustCompile(`\b(\d+)\s*(` + hourUnits + `|` + minuteUnits + `)\b`)
+	if match := durationPattern.FindStringSubmatch(input); match != nil {
 		val, _ := strconv.Atoi(match[1])
-		if strings.Contains(match[2], "hour") || match[2] == "h" {
+		if regexp.MustCompile(`^(` + hourUnits + `)$`).MatchString(match[2]) {
 			duration = time.Duration(val) * time.Hour
 		} else {
 			duration = time.Duration(val) * time.Minute
 		}
-		input = regexp.MustCompile(`\b(\d+)\s*(hour|hours|h|minute|minutes|min)\b`).ReplaceAllString(input, "")
+		input = durationPattern.ReplaceAllString(input, "")
 	}
 
 	event.Start = startTime
 	event.End = startTime.Add(duration)
 
-	// Extract summary (everything else, cleaned up)
-	event.Summary = strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(input, " "))
-	if event.Summary == "" {
-		event.Summary = "New Event"
+	event.Summary = nlSummary(input)
+	return event, nil
+}
+
+// nlSummary is what is left of the input once dates and times are taken
+// out, cleaned up
+func nlSummary(rest string) string {
+	if summary := strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(rest, " ")); summary != "" {
+		return summary
 	}
+	return "New Event"
+}
 
-	return event, nil
+// dayPart is a part of the day named in natural language
+type dayPart struct {
+	allDay     bool
+	start, end time.Time // Clock times, unless allDay
+}
+
+// dayPartOverrides are the day_parts config, set with setDayParts
+var dayPartOverrides map[string]string
+
+// setDayParts checks the day_parts config and uses it for natural
+// language. Parts that can't be read are left out.
+func setDayParts(parts map[string]string) error {
+	dayPartOverrides = make(map[string]string, len(parts))
+	var err error
+	for name, value := range parts {
+		if value != "" {
+			if _, partErr := parseDayPart(value); partErr != nil {
+				err = fmt.Errorf("day part %q: %v", name, partErr)
+				continue
+			}
+		}
+		dayPartOverrides[strings.ToLower(strings.TrimSpace(name))] = value
+	}
+	return err
+}
+
+// nlDayParts returns the parts of the day of the locale and the config
+func nlDayParts() map[string]dayPart {
+	values := nlDayPartWords()
+	for name, value := range dayPartOverrides {
+		values[name] = value
+	}
+	parts := make(map[string]dayPart, len(values))
+	for name, value := range values {
+		if part, err := parseDayPart(value); name != "" && err == nil {
+			parts[name] = part
+		}
+	}
+	return parts
+}
+
+// parseDayPart reads the hours of a part of the day, "HH:MM-HH:MM" or
+// "all day"
+func parseDayPart(value string) (dayPart, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "all day") {
+		return dayPart{allDay: true}, nil
+	}
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return dayPart{}, fmt.Errorf("expected HH:MM-HH:MM or all day, got %q", value)
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(from))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(to))
+	if err1 != nil || err2 != nil {
+		return dayPart{}, fmt.Errorf("expected HH:MM-HH:MM or all day, got %q", value)
+	}
+	if !end.After(start) {
+		return dayPart{}, fmt.Errorf("%q ends before it starts", value)
+	}
+	return dayPart{start: start, end: end}, nil
 }
 
 func parseTime(match string, base time.Time) time.Time {
@@ -122,33 +233,14 @@ func parseTimeSimple(match string, base time.Time) time.Time {
 }
 
 func parseTimeWord(match string, base time.Time) time.Time {
-	switch match {
-	case "morning":
-		return time.Date(base.Year(), base.Month(), base.Day(), 9, 0, 0, 0, base.Location())
-	case "afternoon":
-		return time.Date(base.Year(), base.Month(), base.Day(), 14, 0, 0, 0, base.Location())
-	case "evening":
-		return time.Date(base.Year(), base.Month(), base.Day(), 18, 0, 0, 0, base.Location())
-	case "noon":
-		return time.Date(base.Year(), base.Month(), base.Day(), 12, 0, 0, 0, base.Location())
-	case "midnight":
-		return time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, base.Location())
+	if hour, ok := nlTimeWords()[match]; ok {
+		return time.Date(base.Year(), base.Month(), base.Day(), hour, 0, 0, 0, base.Location())
 	}
 	return base
 }
 
 func parseWeekday(match string, base time.Time) time.Time {
-	weekdays := map[string]time.Weekday{
-		"monday":    time.Monday,
-		"tuesday":   time.Tuesday,
-		"wednesday": time.Wednesday,
-		"thursday":  time.Thursday,
-		"friday":    time.Friday,
-		"saturday":  time.Saturday,
-		"sunday":    time.Sunday,
-	}
-
-	targetDay := weekdays[match]
+	targetDay := nlWeekdays()[match]
 	daysAhead := int(targetDay - base.Weekday())
 	if daysAhead <= 0 {
 		daysAhead += 7
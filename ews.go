@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/ews"
+)
+
+// ewsProvider reads the user's calendar from an Exchange server. EWS
+// calendars are read-only.
+type ewsProvider struct {
+	singleCalendar
+	readOnly
+	config *EWSConfig
+}
+
+func newEWSProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &ewsProvider{singleCalendar: singleCalendar{entry}, config: config.EWS}
+}
+
+func (p *ewsProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	config := p.config
+	if config == nil || config.URL == "" {
+		return nil, fmt.Errorf(`set "ews": {"url": ..., "username": ..., "password": ...} in the config`)
+	}
+	client := &ews.Client{
+		HTTP:     httpClient,
+		URL:      config.URL,
+		Username: config.Username,
+		Password: config.Password,
+		NTLM:     strings.EqualFold(config.Auth, "ntlm"),
+	}
+
+	items, err := client.Events(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, item := range items {
+		if item.IsCancelled {
+			continue
+		}
+		start, end := item.Start.In(time.Local), item.End.In(time.Local)
+		if item.IsAllDayEvent {
+			// All-day items start at midnight in the mailbox's time zone,
+			// which may not be ours
+			start, end = nearestMidnight(start), nearestMidnight(end)
+		}
+
+		summary := item.Subject
+		if summary == "" {
+			summary = "(No title)"
+		}
+		transp := ""
+		if item.LegacyFreeBusyStatus == "Free" {
+			transp = "TRANSPARENT"
+		}
+		var attendees []string
+		for _, attendee := range append(item.RequiredAttendees, item.OptionalAttendees...) {
+			attendees = append(attendees, strings.ToLower(attendee.EmailAddress))
+		}
+		events = append(events, Event{
+			Summary:      summary,
+			Start:        start,
+			End:          end,
+			Description:  strings.TrimSpace(item.Body),
+			Location:     item.Location,
+			CalendarName: cal.Name,
+			UID:          item.UID,
+			LastModified: item.LastModifiedTime,
+			Transp:       transp,
+			Organizer:    strings.ToLower(item.Organizer.EmailAddress),
+			Attendees:    attendees,
+		})
+	}
+	return events, nil
+}
+
+// nearestMidnight rounds t to the closest local midnight
+func nearestMidnight(t time.Time) time.Time {
+	t = t.Add(12 * time.Hour)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// exportFlag is a boolean-style flag that optionally takes the range to
+// export, so "--export-md", "--export-md=month" and "--export-md month"
+// (see parseExportRange) all work
+type exportFlag struct {
+	set   bool
+	value string // "" for the current week
+}
+
+func (f *exportFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *exportFlag) Set(value string) error {
+	switch value {
+	case "true":
+		f.set = true
+		return nil
+	case "false":
+		f.set = false
+		return nil
+	}
+	if !validExportRange(value) {
+		return fmt.Errorf("expected day, week, month or a span like 2w, got %q", value)
+	}
+	f.set, f.value = true, value
+	return nil
+}
+
+func (f *exportFlag) IsBoolFlag() bool { return true }
+
+// parseExportRange picks up the range in "--export-md month", which the flag
+// package leaves as a positional argument, and parses any flags after it
+func parseExportRange(f *exportFlag) {
+	if !f.set || f.value != "" || flag.NArg() == 0 {
+		return
+	}
+	if !validExportRange(flag.Arg(0)) {
+		return
+	}
+	f.value = flag.Arg(0)
+	flag.CommandLine.Parse(flag.Args()[1:])
+}
+
+// validExportRange reports whether value names a range of exportRange
+func validExportRange(value string) bool {
+	switch value {
+	case "day", "week", "month":
+		return true
+	}
+	_, err := parseSpan(value)
+	return err == nil
+}
+
+// exportRange returns the days [from, to) to export around the current
+// date: "day", "week" (the default), "month", or a span like 2w from it
+func (m model) exportRange(value string) (time.Time, time.Time) {
+	day := dayStart(m.currentDate)
+	switch value {
+	case "day":
+		return day, day.AddDate(0, 0, 1)
+	case "", "week":
+		weekStart := m.getWeekStart(day)
+		return weekStart, weekStart.AddDate(0, 0, 7)
+	case "month":
+		first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return first, first.AddDate(0, 1, 0)
+	}
+	span, _ := parseSpan(value)
+	days := max(1, int((span+24*time.Hour-1)/(24*time.Hour)))
+	return day, day.AddDate(0, 0, days)
+}
+
+// exportDay is a day of the export with its events
+type exportDay struct {
+	date   time.Time
+	events []Event
+}
+
+// exportDays returns the days of [from, to) that have events
+func (m model) exportDays(from, to time.Time) []exportDay {
+	var days []exportDay
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if events := m.getEventsForDay(day); len(events) > 0 {
+			days = append(days, exportDay{day, events})
+		}
+	}
+	return days
+}
+
+// exportTitle names the exported range: the day, or its first and last day
+func exportTitle(from, to time.Time) string {
+	last := to.AddDate(0, 0, -1)
+	if !last.After(from) {
+		return formatDate(from, "Monday, January 2, 2006")
+	}
+	return formatDate(from, "Mon Jan 2, 2006") + " – " + formatDate(last, "Mon Jan 2, 2006")
+}
+
+// exportTime is the time column of an export, "All day" for all-day events
+func exportTime(event Event) string {
+	if isAllDay(event) {
+		return tr("All day")
+	}
+	return timeRange(event)
+}
+
+// exportColumns are the headers of the exported tables
+var exportColumns = []string{"Day", "Time", "Event", "Calendar", "Location"}
+
+// exportMarkdown renders the events of [from, to) as a Markdown table, one
+// row per event, for wikis and team updates
+func (m model) exportMarkdown(from, to time.Time) string {
+	// Pipes would end the cell, newlines the row
+	cell := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace
+
+	var b strings.Builder
+	b.WriteString("# " + exportTitle(from, to) + "\n\n")
+	days := m.exportDays(from, to)
+	if len(days) == 0 {
+		b.WriteString(tr("No events scheduled") + "\n")
+		return b.String()
+	}
+
+	headers := make([]string, len(exportColumns))
+	for i, column := range exportColumns {
+		headers[i] = tr(column)
+	}
+	b.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	b.WriteString(strings.Repeat("| --- ", len(headers)) + "|\n")
+	for _, day := range days {
+		for i, event := range day.events {
+			date := ""
+			if i == 0 {
+				// The day once, at its first event
+				date = "**" + formatDate(day.date, "Mon Jan 2") + "**"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				date, exportTime(event), cell(m.eventTitle(event)), cell(event.CalendarName), cell(m.eventLocation(event)))
+		}
+	}
+	return b.String()
+}
+
+// exportHTMLStyle styles the exported page, readable on screen and in print
+const exportHTMLStyle = `body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+h1 { font-size: 1.4em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.35em 0.6em; text-align: left; vertical-align: top; border-bottom: 1px solid #ddd; }
+tr.day th { background: #f3f3f3; padding-top: 0.8em; }
+td.time { white-space: nowrap; font-variant-numeric: tabular-nums; color: #555; }
+.dot { font-size: 0.9em; }
+@media print { body { margin: 0; } tr { break-inside: avoid; } }
+`
+
+// exportHTML renders the events of [from, to) as a standalone HTML page, a
+// table with a header row per day, for sharing and printing
+func (m model) exportHTML(from, to time.Time) string {
+	title := html.EscapeString(exportTitle(from, to))
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + title + "</title>\n<style>\n" + exportHTMLStyle + "</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>" + title + "</h1>\n")
+	days := m.exportDays(from, to)
+	if len(days) == 0 {
+		b.WriteString("<p>" + html.EscapeString(tr("No events scheduled")) + "</p>\n</body>\n</html>\n")
+		return b.String()
+	}
+
+	b.WriteString("<table>\n<thead><tr>")
+	for _, column := range exportColumns[1:] {
+		b.WriteString("<th>" + html.EscapeString(tr(column)) + "</th>")
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for _, day := range days {
+		fmt.Fprintf(&b, "<tr class=\"day\"><th colspan=\"%d\">%s</th></tr>\n",
+			len(exportColumns)-1, html.EscapeString(formatDate(day.date, "Monday, January 2")))
+		for _, event := range day.events {
+			fmt.Fprintf(&b, "<tr><td class=\"time\">%s</td><td><span class=\"dot\" style=\"color: %s\">%s</span>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(exportTime(event)),
+				cssColor(eventColor(event)), strings.TrimSpace(eventMarker(event))+" ",
+				html.EscapeString(m.eventTitle(event)),
+				html.EscapeString(event.CalendarName),
+				html.EscapeString(m.eventLocation(event)))
+		}
+	}
+	b.WriteString("</tbody>\n</table>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// ansiColors are the 16 basic terminal colors as xterm draws them
+var ansiColors = [][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cssColor converts a calendar color, "#rrggbb" or a 256-color terminal
+// index, to CSS
+func cssColor(color lipgloss.Color) string {
+	r, g, b, ok := rgbColor(color)
+	if !ok {
+		return html.EscapeString(string(color))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// rgbColor returns the components of a calendar color, "#rrggbb" or a
+// 256-color terminal index
+func rgbColor(color lipgloss.Color) (r, g, b uint8, ok bool) {
+	if hex, found := strings.CutPrefix(string(color), "#"); found {
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return 0, 0, 0, false
+		}
+		return uint8(value >> 16), uint8(value >> 8), uint8(value), true
+	}
+	n, err := strconv.Atoi(string(color))
+	switch {
+	case err != nil || n < 0 || n > 255:
+		return 0, 0, 0, false
+	case n < 16:
+		return ansiColors[n][0], ansiColors[n][1], ansiColors[n][2], true
+	case n >= 232:
+		gray := uint8(8 + 10*(n-232))
+		return gray, gray, gray, true
+	}
+	// The 6×6×6 color cube
+	levels := []uint8{0, 95, 135, 175, 215, 255}
+	n -= 16
+	return levels[n/36], levels[n/6%6], levels[n%6], true
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// eventFilter is a compiled filter expression of a calendar. Events for
+// which it returns false are dropped when the calendar is loaded.
+type eventFilter func(event Event) bool
+
+// filterOperators are tried longest first so ">=" isn't read as ">"
+var filterOperators = []string{"!~", "==", "!=", ">=", "<=", "~", ">", "<"}
+
+// parseFilter compiles one filter expression:
+//
+//	summary !~ "Canceled"    text fields: summary, description, location,
+//	location == "Room 4"     organizer; ~ and !~ match a regexp ignoring case
+//	duration >= 15m          durations like 15m or 1h30m
+//	after 08:00              starts at or after a time of day
+//	before 18:00             starts before a time of day
+//
+// All-day events have no time of day and pass after and before.
+func parseFilter(expr string) (eventFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if keyword, value, ok := strings.Cut(expr, " "); ok && (keyword == "after" || keyword == "before") {
+		clock, err := time.Parse("15:04", strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", value)
+		}
+		minutes := clock.Hour()*60 + clock.Minute()
+		return func(event Event) bool {
+			if isAllDay(event) {
+				return true
+			}
+			start := event.Start.Hour()*60 + event.Start.Minute()
+			if keyword == "after" {
+				return start >= minutes
+			}
+			return start < minutes
+		}, nil
+	}
+
+	end := strings.IndexAny(expr, " !~=<>")
+	if end <= 0 {
+		return nil, fmt.Errorf("expected <field> <operator> <value>")
+	}
+	field := expr[:end]
+	rest := strings.TrimSpace(expr[end:])
+	op := ""
+	for _, candidate := range filterOperators {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("missing operator after %q", field)
+	}
+	value := strings.TrimSpace(strings.TrimPrefix(rest, op))
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	if field == "duration" {
+		return durationFilter(op, value)
+	}
+	text, ok := filterFields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+	return textFilter(text, op, value)
+}
+
+// filterFields are the text fields filters can test
+var filterFields = map[string]func(Event) string{
+	"summary":     func(e Event) string { return e.Summary },
+	"description": func(e Event) string { return e.Description },
+	"location":    func(e Event) string { return e.Location },
+	"organizer":   func(e Event) string { return e.Organizer },
+}
+
+func textFilter(text func(Event) string, op, value string) (eventFilter, error) {
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return nil, err
+		}
+		return func(event Event) bool { return re.MatchString(text(event)) == (op == "~") }, nil
+	case "==":
+		return func(event Event) bool { return strings.EqualFold(text(event), value) }, nil
+	case "!=":
+		return func(event Event) bool { return !strings.EqualFold(text(event), value) }, nil
+	}
+	return nil, fmt.Errorf("operator %s can't compare text", op)
+}
+
+func durationFilter(op, value string) (eventFilter, error) {
+	limit, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %q", value)
+	}
+	compare := map[string]func(d time.Duration) bool{
+		"==": func(d time.Duration) bool { return d == limit },
+		"!=": func(d time.Duration) bool { return d != limit },
+		">=": func(d time.Duration) bool { return d >= limit },
+		"<=": func(d time.Duration) bool { return d <= limit },
+		">":  func(d time.Duration) bool { return d > limit },
+		"<":  func(d time.Duration) bool { return d < limit },
+	}[op]
+	if compare == nil {
+		return nil, fmt.Errorf("operator %s can't compare durations", op)
+	}
+	return func(event Event) bool { return compare(event.End.Sub(event.Start)) }, nil
+}
+
+// filterEvents keeps the events passing all of a calendar's filters.
+// Invalid filters are reported to warn and ignored.
+func filterEvents(events []Event, cal CalendarConfig, warn func(message string)) []Event {
+	var filters []eventFilter
+	for _, expr := range cal.Filter {
+		filter, err := parseFilter(expr)
+		if err != nil {
+			warn(fmt.Sprintf("Invalid filter %q of calendar %s: %v", expr, cal.Name, err))
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return events
+	}
+
+	var kept []Event
+	for _, event := range events {
+		keep := true
+		for _, filter := range filters {
+			if !filter(event) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
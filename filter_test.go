@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 10, hour, minute, 0, 0, time.Local)
+	}
+	standup := Event{Summary: "Team Standup", Location: "Room 4", Organizer: "boss@example.com", Start: at(9, 0), End: at(9, 15)}
+	canceled := Event{Summary: "Canceled: Review", Description: "Moved to Friday", Start: at(14, 0), End: at(15, 30)}
+	allDay := Event{Summary: "Holiday", Start: at(0, 0), End: at(0, 0).AddDate(0, 0, 1)}
+
+	tests := []struct {
+		expr string
+		want []bool // For standup, canceled and allDay
+	}{
+		{`summary !~ "Canceled"`, []bool{true, false, true}},
+		{`summary ~ "^team"`, []bool{true, false, false}},
+		{`summary~standup`, []bool{true, false, false}},
+		{`description ~ "friday"`, []bool{false, true, false}},
+		{`location == "room 4"`, []bool{true, false, false}},
+		{`location != "Room 4"`, []bool{false, true, true}},
+		{`organizer == boss@example.com`, []bool{true, false, false}},
+		{`duration >= 15m`, []bool{true, true, true}},
+		{`duration > 15m`, []bool{false, true, true}},
+		{`duration < 1h`, []bool{true, false, false}},
+		{`duration <= 1h30m`, []bool{true, true, false}},
+		{`duration == 15m`, []bool{true, false, false}},
+		{`duration != 15m`, []bool{false, true, true}},
+		{`after 08:00`, []bool{true, true, true}},
+		{`after 10:00`, []bool{false, true, true}},
+		{`before 10:00`, []bool{true, false, true}},
+		{`  before 14:00  `, []bool{true, false, true}},
+	}
+	for _, test := range tests {
+		filter, err := parseFilter(test.expr)
+		if err != nil {
+			t.Errorf("parseFilter(%q): %v", test.expr, err)
+			continue
+		}
+		for i, event := range []Event{standup, canceled, allDay} {
+			if got := filter(event); got != test.want[i] {
+				t.Errorf("%q on %q = %v, want %v", test.expr, event.Summary, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`summary`,
+		`summary "Canceled"`,
+		`title ~ "x"`,
+		`~ "x"`,
+		`summary ~ "("`,
+		`summary >= "x"`,
+		`duration >= soon`,
+		`duration ~ 15m`,
+		`after noon`,
+		`before 25:00`,
+	} {
+		if _, err := parseFilter(expr); err == nil {
+			t.Errorf("parseFilter(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	events := []Event{
+		{Summary: "Standup", Start: start, End: start.Add(15 * time.Minute)},
+		{Summary: "Room booking", Start: start, End: start.Add(time.Hour)},
+		{Summary: "Quick sync", Start: start, End: start.Add(5 * time.Minute)},
+	}
+	var warnings []string
+	cal := CalendarConfig{Name: "Work", Filter: []string{`summary !~ "booking"`, `duration >= 15m`, `bogus`}}
+
+	kept := filterEvents(events, cal, func(message string) { warnings = append(warnings, message) })
+	if len(kept) != 1 || kept[0].Summary != "Standup" {
+		t.Errorf("kept %v, want only Standup", kept)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("got warnings %q, want one for the invalid filter", warnings)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+const (
+	defaultFocusTitle    = "Focus time"
+	defaultFocusDuration = 90 * time.Minute
+	focusSearchDays      = 14 // How far ahead to look for a free slot
+)
+
+func (m model) focusConfig() FocusConfig {
+	var focus FocusConfig
+	if m.config != nil && m.config.Focus != nil {
+		focus = *m.config.Focus
+	}
+	if focus.Title == "" {
+		focus.Title = defaultFocusTitle
+	}
+	if focus.Calendar == "" {
+		focus.Calendar = m.selectedCalendar
+	}
+	return focus
+}
+
+// nextFocusSlot finds the first free working-hours slot long enough for a
+// focus block, starting on the current day (not before now)
+func (m model) nextFocusSlot(duration time.Duration) (timeSlot, bool) {
+	after := nextQuarterHour(m.clock.Now())
+	day := m.currentDate
+	for i := 0; i < focusSearchDays; i++ {
+		for _, slot := range m.freeWorkingSlots(m.events, day, after) {
+			if slot.end.Sub(slot.start) >= duration {
+				return timeSlot{start: slot.start, end: slot.start.Add(duration)}, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return timeSlot{}, false
+}
+
+// blockFocusTime creates a focus event for slot
+func (m model) blockFocusTime(slot timeSlot) (model, tea.Cmd) {
+	focus := m.focusConfig()
+	event := &Event{
+		Summary:      focus.Title,
+		Start:        slot.start,
+		End:          slot.end,
+		CalendarName: focus.Calendar,
+		Transp:       "OPAQUE",
+	}
+	if focus.Transparent {
+		event.Transp = "TRANSPARENT"
+	}
+
+	m, cmd := m.saveNewEvents([]*Event{event})
+	if m.creating == nil {
+		m.message = fmt.Sprintf(tr("Blocked %s %s–%s"), focus.Title,
+			formatDate(slot.start, "Mon Jan 2 15:04"), slot.end.Format("15:04"))
+	}
+	m = m.jumpTo(slot.start, m.viewMode)
+	return m, cmd
+}
+
+// blockNextFreeSlot reserves the next free slot as focus time
+func (m model) blockNextFreeSlot() (model, tea.Cmd) {
+	duration := defaultFocusDuration
+	if focus := m.focusConfig(); focus.DurationMinutes > 0 {
+		duration = time.Duration(focus.DurationMinutes) * time.Minute
+	}
+
+	slot, ok := m.nextFocusSlot(duration)
+	if !ok {
+		m.message = fmt.Sprintf(tr("No free slot in the next %d days"), focusSearchDays)
+		return m, nil
+	}
+	return m.blockFocusTime(slot)
+}
+
+// startFocusRange asks for a time range on the current day to block
+func (m model) startFocusRange() (model, tea.Cmd) {
+	text := ""
+	m.focusRange = &text
+	m.focusForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf(tr("Block focus time on %s"), formatDate(m.currentDate, "Monday, January 2"))).
+				Placeholder("14:00-16:00").
+				Value(m.focusRange).
+				Validate(func(s string) error {
+					_, err := parseClockRange(m.currentDate, s)
+					return err
+				}),
+		),
+	)
+	return m, m.focusForm.Init()
+}
+
+// updateFocusForm passes messages to the range prompt while it is open
+func (m model) updateFocusForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.focusForm = nil
+		return m, nil
+	}
+
+	form, cmd := m.focusForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.focusForm = f
+	}
+
+	switch m.focusForm.State {
+	case huh.StateCompleted:
+		m.focusForm = nil
+		slot, err := parseClockRange(m.currentDate, *m.focusRange)
+		if err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		return m.blockFocusTime(slot)
+	case huh.StateAborted:
+		m.focusForm = nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// parseClockRange parses "HH:MM-HH:MM" on date
+func parseClockRange(date time.Time, s string) (timeSlot, error) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(strings.TrimSpace(s), "–", "-"), "-")
+	if !ok {
+		return timeSlot{}, fmt.Errorf("use HH:MM-HH:MM")
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(from))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(to))
+	if err1 != nil || err2 != nil {
+		return timeSlot{}, fmt.Errorf("use HH:MM-HH:MM")
+	}
+	if !end.After(start) {
+		return timeSlot{}, fmt.Errorf("end must be after start")
+	}
+	return timeSlot{start: atClock(date, start), end: atClock(date, end)}, nil
+}
@@ -1,25 +1,43 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
-	"sort"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/ical"
 )
 
+// repeatUnits name the interval of each repeat option
+var repeatUnits = map[string]string{"daily": "days", "weekly": "weeks", "monthly": "months", "yearly": "years"}
+
+// weekdayOptions are the days a weekly repeat can be on, as BYDAY codes
+var weekdayOptions = []huh.Option[string]{
+	huh.NewOption("Mon", "MO"), huh.NewOption("Tue", "TU"), huh.NewOption("Wed", "WE"),
+	huh.NewOption("Thu", "TH"), huh.NewOption("Fri", "FR"), huh.NewOption("Sat", "SA"),
+	huh.NewOption("Sun", "SU"),
+}
+
+// buildForm rebuilds the creation form on the model's form values
+func (m model) buildForm() *huh.Form {
+	return buildEventForm(m.formSummary, m.formDescription, m.formAttendees, m.formURL, m.formDate, m.formStartTime, m.formEndTime, m.formEndDate, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.formRepeatEvery, m.formRepeatDays, m.formRepeatMonthly, m.formRepeatCount, m.sortedCalendarNames(), m.contacts)
+}
+
 // buildEventForm creates a huh form for event creation
-func buildEventForm(summary, description, dateStr, startTime, endTime, selectedCal *string, repeatOption *string, repeatEndDate *string, calendars map[string]lipgloss.Color) *huh.Form {
-	// Build calendar options
-	calOptions := make([]huh.Option[string], 0, len(calendars))
-	calNames := make([]string, 0, len(calendars))
-	for name := range calendars {
-		calNames = append(calNames, name)
-	}
-	sort.Strings(calNames)
+func buildEventForm(summary, description, attendees, eventURL, dateStr, startTime, endTime, endDate, selectedCal *string, repeatOption *string, repeatEndDate *string, repeatEvery *string, repeatDays *[]string, repeatMonthly *string, repeatCount *string, calNames []string, contacts []contact) *huh.Form {
+	// Build calendar options, in the calendar order
+	calOptions := make([]huh.Option[string], 0, len(calNames))
 	for _, name := range calNames {
 		calOptions = append(calOptions, huh.NewOption(name, name))
 	}
@@ -46,7 +64,29 @@ func buildEventForm(summary, description, dateStr, startTime, endTime, selectedC
 		huh.NewText().
 			Title("Description").
 			Value(description).
-			Placeholder("Optional description"),
+			Placeholder("Optional description").
+			ExternalEditor(false), // ctrl+e works on the whole form instead
+
+		huh.NewInput().
+			Key("attendees").
+			Title("Attendees").
+			Prompt("> ").
+			Value(attendees).
+			Placeholder("Optional, Name <address>, ...").
+			SuggestionsFunc(func() []string {
+				return attendeeSuggestions(*attendees, contacts)
+			}, attendees).
+			Validate(func(s string) error {
+				_, _, err := parseAttendees(s, contacts)
+				return err
+			}),
+
+		huh.NewInput().
+			Title("URL").
+			Prompt("> ").
+			Value(eventURL).
+			Placeholder("Optional, https://...").
+			Validate(validateEventURL),
 
 		huh.NewInput().
 			Title("Date").
@@ -59,6 +99,7 @@ func buildEventForm(summary, description, dateStr, startTime, endTime, selectedC
 			}),
 
 		huh.NewInput().
+			Key("start").
 			Title("Start Time").
 			Prompt("> ").
 			Value(startTime).
@@ -72,15 +113,36 @@ func buildEventForm(summary, description, dateStr, startTime, endTime, selectedC
 			}),
 
 		huh.NewInput().
+			Key("end").
 			Title("End Time").
 			Prompt("> ").
 			Value(endTime).
 			Placeholder("HH:MM").
 			Validate(func(s string) error {
+				// Both times or neither, for an all-day event
 				if s == "" {
-					return nil // Optional field
+					if *startTime != "" {
+						return fmt.Errorf("set an end time too, or clear the start time")
+					}
+					return nil
 				}
-				_, err := time.Parse("15:04", s)
+				if _, err := time.Parse("15:04", s); err != nil {
+					return err
+				}
+				if *startTime == "" {
+					return fmt.Errorf("set a start time too, or clear the end time")
+				}
+				return nil
+			}),
+
+		huh.NewInput().
+			Title("End Date").
+			Prompt("> ").
+			Value(endDate).
+			Placeholder("DD-MM-YYYY (optional, same day)").
+			Validate(func(s string) error {
+				// Checks the end comes after the start, now that both are known
+				_, _, _, err := formTimes(*dateStr, *startTime, *endTime, s)
 				return err
 			}),
 
@@ -96,13 +158,32 @@ func buildEventForm(summary, description, dateStr, startTime, endTime, selectedC
 				huh.NewOption("Daily", "daily"),
 				huh.NewOption("Weekly", "weekly"),
 				huh.NewOption("Monthly", "monthly"),
+				huh.NewOption("Yearly", "yearly"),
 			).
 			Value(repeatOption),
 	}
 
-	// Only add "Repeat Until" field if a repeat option (other than "none") is selected
-	if hasRepeat() {
-		fields = append(fields, huh.NewInput().
+	// The repeat details are on pages of their own, shown once a repeat
+	// option (other than "none") is selected
+	repeatFields := huh.NewGroup(
+		huh.NewInput().
+			TitleFunc(func() string {
+				return fmt.Sprintf("Repeat Every N %s", repeatUnits[*repeatOption])
+			}, repeatOption).
+			Prompt("> ").
+			Value(repeatEvery).
+			Placeholder("1").
+			Validate(func(s string) error {
+				if s == "" {
+					return nil // Defaults to 1
+				}
+				if n, err := strconv.Atoi(s); err != nil || n < 1 {
+					return fmt.Errorf("enter a number of at least 1")
+				}
+				return nil
+			}),
+
+		huh.NewInput().
 			Title("Repeat Until (DD-MM-YYYY)").
 			Prompt("> ").
 			Value(repeatEndDate).
@@ -113,168 +194,490 @@ func buildEventForm(summary, description, dateStr, startTime, endTime, selectedC
 				}
 				_, err := time.Parse("02-01-2006", s)
 				return err
-			}))
-	}
+			}),
+
+		huh.NewInput().
+			Title("Or End After N Occurrences").
+			Prompt("> ").
+			Value(repeatCount).
+			Placeholder("(optional)").
+			Validate(func(s string) error {
+				if s == "" {
+					return nil // Optional field
+				}
+				if n, err := strconv.Atoi(s); err != nil || n < 1 {
+					return fmt.Errorf("enter a number of at least 1")
+				}
+				if *repeatEndDate != "" {
+					return fmt.Errorf("set either an end date or a number of occurrences")
+				}
+				return nil
+			}),
+	).WithHideFunc(func() bool { return !hasRepeat() })
+
+	weekdays := huh.NewGroup(
+		huh.NewMultiSelect[string]().
+			Title("Repeat On").
+			Description("None selected repeats on the event's weekday").
+			Options(weekdayOptions...).
+			Value(repeatDays),
+	).WithHideFunc(func() bool { return repeatOption == nil || *repeatOption != "weekly" })
+
+	monthDay := huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Repeat On").
+			OptionsFunc(func() []huh.Option[string] {
+				return monthlyOptions(*dateStr)
+			}, dateStr).
+			Value(repeatMonthly),
+	).WithHideFunc(func() bool { return repeatOption == nil || *repeatOption != "monthly" })
+
+	// Esc leaves the form, as its help bar says
+	keymap := huh.NewDefaultKeyMap()
+	keymap.Quit = key.NewBinding(key.WithKeys("esc", "ctrl+c"))
 
 	return huh.NewForm(
 		huh.NewGroup(fields...),
-	).WithTheme(huh.ThemeCharm())
+		repeatFields,
+		weekdays,
+		monthDay,
+	).WithTheme(huh.ThemeCharm()).WithKeyMap(keymap)
 }
 
-func (m model) saveEventFromForm() (tea.Model, tea.Cmd) {
-	// Parse form data - DD-MM-YYYY format
-	date, err := time.Parse("02-01-2006", *m.formDate)
-	if err != nil {
-		m.message = fmt.Sprintf("Invalid date: %v (use DD-MM-YYYY)", err)
-		m.creationMode = NoCreation
-		m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-		return m, m.eventForm.Init()
-	}
-
-	// Parse times (optional - can be empty)
-	var start, end time.Time
-	if *m.formStartTime != "" && *m.formEndTime != "" {
-		startTime, err1 := time.Parse("15:04", *m.formStartTime)
-		endTime, err2 := time.Parse("15:04", *m.formEndTime)
-		if err1 != nil || err2 != nil {
-			m.message = "Invalid time format (use HH:MM)"
-			m.creationMode = NoCreation
-			m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-			return m, m.eventForm.Init()
+// validateEventURL accepts an empty URL or an absolute one, such as a
+// meeting or ticket link
+func validateEventURL(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if u, err := url.Parse(s); err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return fmt.Errorf("enter a full URL, e.g. https://example.com/meeting")
+	}
+	return nil
+}
+
+// openEventForm opens an empty creation form for m.currentDate, the focused
+// day in every view, with the given start and end times ("" for none)
+func (m model) openEventForm(startTime, endTime string) (tea.Model, tea.Cmd) {
+	m.creationMode = UIFormInput
+	// Reset form values
+	*m.formSummary = ""
+	*m.formDescription = ""
+	*m.formAttendees = ""
+	*m.formURL = ""
+	*m.formDate = m.currentDate.Format("02-01-2006") // DD-MM-YYYY format
+	*m.formStartTime = startTime
+	*m.formEndTime = endTime
+	*m.formEndDate = ""
+	*m.formCalendar = m.selectedCalendar
+	*m.formRepeatOptions = "none" // Default to "None"
+	*m.formRepeatEndDate = ""
+	*m.formRepeatEvery = "1"
+	*m.formRepeatDays = nil
+	*m.formRepeatMonthly = "day"
+	*m.formRepeatCount = ""
+	m.formScrollOffset = 0
+	m.formError = ""
+	m.draftPrompt = m.draft != nil
+	// Rebuild form
+	m.eventForm = m.buildForm()
+	return m, m.eventForm.Init()
+}
+
+// focusedSlot returns the hour after the focused event in the daily view, or
+// the next full hour on an empty today, as "HH:MM" start and end times.
+// Elsewhere only the day is focused and the times are empty.
+func (m model) focusedSlot() (string, string) {
+	if m.viewMode != DailyView {
+		return "", ""
+	}
+
+	var start time.Time
+	if dayEvents := m.dailyEvents(); m.cursor < len(dayEvents) {
+		start = dayEvents[m.cursor].End
+	} else if now := m.clock.Now(); sameDay(m.currentDate, now) {
+		start = now.Truncate(time.Hour).Add(time.Hour)
+	}
+	// The slot has to end on the focused day, it doesn't set an end date
+	if start.IsZero() || !sameDay(start, m.currentDate) || !sameDay(start.Add(time.Hour), m.currentDate) {
+		return "", ""
+	}
+	return start.Format("15:04"), start.Add(time.Hour).Format("15:04")
+}
+
+// Positions of the form's first page fields, to focus one after an error
+const (
+	fieldSummary = iota
+	fieldDescription
+	fieldAttendees
+	fieldURL
+	fieldDate
+	fieldStartTime
+	fieldEndTime
+	fieldEndDate
+	fieldCalendar
+	fieldRepetition
+)
+
+// defaultTimeStep is how far up and down move a time in the form
+const defaultTimeStep = 15
+
+func (c *Config) timeStep() int {
+	if c == nil || c.TimeStep <= 0 || c.TimeStep > 60 {
+		return defaultTimeStep
+	}
+	return c.TimeStep
+}
+
+// stepFormTime moves the focused start or end time to the next (up) or
+// previous (down) step of the grid. It reports false for other fields.
+func (m model) stepFormTime(msg tea.KeyMsg) (model, bool) {
+	input, ok := m.eventForm.GetFocusedField().(*huh.Input)
+	if !ok || (msg.String() != "up" && msg.String() != "down") {
+		return m, false
+	}
+	var value *string
+	switch input.GetKey() {
+	case "start":
+		value = m.formStartTime
+	case "end":
+		value = m.formEndTime
+	default:
+		return m, false
+	}
+
+	current := *value
+	if current == "" && value == m.formEndTime && *m.formStartTime != "" {
+		// An hour after the start, then stepped from there
+		if start, err := time.Parse("15:04", *m.formStartTime); err == nil {
+			*value = start.Add(time.Hour).Format("15:04")
+			input.Value(value)
+			return m.trackDraft(), true
 		}
+	}
+	if current == "" {
+		current = m.clock.Now().Format("15:04")
+	}
+	t, err := time.Parse("15:04", current)
+	if err != nil {
+		// Leave what is being typed alone
+		return m, true
+	}
+	*value = stepTime(t, m.config.timeStep(), msg.String() == "up").Format("15:04")
+	input.Value(value)
+	return m.trackDraft(), true
+}
 
-		start = time.Date(date.Year(), date.Month(), date.Day(),
-			startTime.Hour(), startTime.Minute(), 0, 0, date.Location())
-		end = time.Date(date.Year(), date.Month(), date.Day(),
-			endTime.Hour(), endTime.Minute(), 0, 0, date.Location())
+// stepTime snaps t to the next or previous multiple of step minutes,
+// wrapping around midnight
+func stepTime(t time.Time, step int, up bool) time.Time {
+	minutes := t.Hour()*60 + t.Minute()
+	if up {
+		minutes = (minutes/step + 1) * step
 	} else {
-		// If times are empty, use start of day and end of day
-		start = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-		end = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 0, 0, date.Location())
-	}
-
-	// Only validate time order if both times are provided
-	if *m.formStartTime != "" && *m.formEndTime != "" {
-		if end.Before(start) || end.Equal(start) {
-			m.message = "End time must be after start time"
-			m.creationMode = NoCreation
-			m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-			return m, m.eventForm.Init()
-		}
+		minutes = (minutes+step-1)/step*step - step
 	}
+	minutes = (minutes + 24*60) % (24 * 60)
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC)
+}
 
-	// Determine repeat interval from single select
+// reopenForm rebuilds the creation form on what was typed, with the field at
+// index field focused and err, if any, shown below the form. It keeps the
+// form open after saving failed.
+func (m model) reopenForm(field int, err string) (tea.Model, tea.Cmd) {
+	m.creationMode = UIFormInput
+	m.formError = err
+	m.eventForm = m.buildForm()
+	if m.width > 0 {
+		m.eventForm = m.eventForm.WithWidth(m.width)
+	}
+	cmd := m.eventForm.Init()
+	for i := 0; i < field; i++ {
+		m.eventForm.NextField()
+	}
+	return m, cmd
+}
+
+func (m model) saveEventFromForm() (tea.Model, tea.Cmd) {
+	start, end, field, err := formTimes(*m.formDate, *m.formStartTime, *m.formEndTime, *m.formEndDate)
+	if err != nil {
+		return m.reopenForm(field, err.Error())
+	}
+
+	event := &Event{
+		Summary:      *m.formSummary,
+		Description:  *m.formDescription,
+		Start:        start,
+		End:          end,
+		CalendarName: *m.formCalendar,
+	}
+	event.URL = strings.TrimSpace(*m.formURL)
+	event.Attendees, event.AttendeeNames, err = parseAttendees(*m.formAttendees, m.contacts)
+	if err != nil {
+		return m.reopenForm(fieldAttendees, err.Error())
+	}
+	if len(event.Attendees) > 0 && m.config != nil && len(m.config.Emails) > 0 {
+		event.Organizer = strings.ToLower(m.config.Emails[0])
+	}
+
+	// A repeating event is saved once, with the RRULE of its repetition
+	event.RRule, event.Start, event.End, field, err = m.formRepetition(event.Start, event.End)
+	if err != nil {
+		return m.reopenForm(field, err.Error())
+	}
+	eventsToCreate := []*Event{event}
+
+	m.creationMode = NoCreation
+	m.formError = ""
+	m.draft = nil
+	clearDraft()
+	// Rebuild form for next time
+	m.eventForm = m.buildForm()
+
+	m, cmd := m.saveNewEvents(eventsToCreate)
+	return m, tea.Batch(m.eventForm.Init(), cmd)
+}
+
+// formRepetition returns the RRULE of the form's repetition, "" for none,
+// and the start and end moved to its first occurrence. On errors it also
+// returns the field to correct.
+func (m model) formRepetition(start, end time.Time) (string, time.Time, time.Time, int, error) {
 	repeatType := ""
 	if m.formRepeatOptions != nil && *m.formRepeatOptions != "" && *m.formRepeatOptions != "none" {
 		repeatType = *m.formRepeatOptions
 	}
+	if repeatType == "" {
+		return "", start, end, 0, nil
+	}
 
 	// Parse repeat end date if provided - DD-MM-YYYY format
 	var repeatEnd time.Time
-	if repeatType != "" && m.formRepeatEndDate != nil && *m.formRepeatEndDate != "" {
-		repeatEnd, err = time.Parse("02-01-2006", *m.formRepeatEndDate)
+	if m.formRepeatEndDate != nil && *m.formRepeatEndDate != "" {
+		var err error
+		repeatEnd, err = time.ParseInLocation("02-01-2006", *m.formRepeatEndDate, time.Local)
 		if err != nil {
-			m.message = fmt.Sprintf("Invalid repeat end date: %v (use DD-MM-YYYY)", err)
-			m.creationMode = NoCreation
-			m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-			return m, m.eventForm.Init()
+			return "", start, end, fieldRepetition, fmt.Errorf("Invalid repeat end date: %v (use DD-MM-YYYY)", err)
 		}
+		// The series ends with the last occurrence on that day
+		repeatEnd = repeatEnd.AddDate(0, 0, 1).Add(-time.Second)
 	}
 
-	// Create events (single or recurring)
-	var eventsToCreate []*Event
+	interval, _ := strconv.Atoi(*m.formRepeatEvery)
+	days := []string(nil)
+	if repeatType == "weekly" {
+		days = *m.formRepeatDays
+	}
+	var monthDays []string
+	if repeatType == "monthly" && *m.formRepeatMonthly != "day" {
+		byDay, ok := monthlyByDay(start, *m.formRepeatMonthly)
+		if !ok {
+			return "", start, end, fieldDate, fmt.Errorf("%s isn't the last %s of the month", *m.formDate, start.Weekday())
+		}
+		monthDays = []string{byDay}
+	}
+	count, _ := strconv.Atoi(*m.formRepeatCount)
+	rrule := formRRule(repeatType, interval, append(days, monthDays...), repeatEnd, count)
 
-	if repeatType != "" {
-		// Create recurring events for the selected repeat type
-		currentStart := start
-		currentEnd := end
-		maxIterations := 365 // Safety limit
-		iteration := 0
+	// Start on the first selected weekday so DTSTART is an occurrence
+	for len(days) > 0 && !slices.Contains(days, weekdayCode(start)) {
+		start = start.AddDate(0, 0, 1)
+		end = end.AddDate(0, 0, 1)
+	}
+	return rrule, start, end, 0, nil
+}
 
-		for iteration < maxIterations {
-			event := &Event{
-				Summary:      *m.formSummary,
-				Description:  *m.formDescription,
-				Start:        currentStart,
-				End:          currentEnd,
-				CalendarName: *m.formCalendar,
-			}
+// recurrencePreviewCount is how many occurrences the form summary lists
+const recurrencePreviewCount = 5
 
-			if color, ok := m.calendars[*m.formCalendar]; ok {
-				event.CalendarColor = color
-			}
+// renderRecurrencePreview lists the next occurrences of the form's
+// repetition and, if it ends, how many there are, "" while the date or
+// repetition is incomplete
+func (m model) renderRecurrencePreview() string {
+	start, end, _, err := formTimes(*m.formDate, *m.formStartTime, *m.formEndTime, *m.formEndDate)
+	if err != nil {
+		return ""
+	}
+	rrule, start, end, _, err := m.formRepetition(start, end)
+	if err != nil || rrule == "" {
+		return ""
+	}
 
-			eventsToCreate = append(eventsToCreate, event)
+	// Expanded from the start rather than today, over enough years to count
+	// the events of a series that ends
+	interval, _ := strconv.Atoi(*m.formRepeatEvery)
+	all := ical.Expand(start, end, rrule, start.AddDate(10*max(1, interval), 0, 0), start)
+	if len(all) == 0 {
+		return ""
+	}
+	// A date a line, as the summary box is narrow
+	var b strings.Builder
+	for i, occurrence := range all[:min(len(all), recurrencePreviewCount)] {
+		label := "      "
+		if i == 0 {
+			label = "Next: "
+		}
+		b.WriteString(label + formatDate(occurrence.Start, "Mon 2.1.2006") + "\n")
+	}
+	more := ""
+	if len(all) > recurrencePreviewCount {
+		more = "…"
+	}
+	if strings.Contains(rrule, "UNTIL=") || strings.Contains(rrule, "COUNT=") {
+		more = strings.TrimSpace(fmt.Sprintf("%s %d events", more, len(all)))
+	}
+	if more != "" {
+		b.WriteString("      " + more + "\n")
+	}
+	return b.String()
+}
 
-			// Check if we've reached the end date
-			if !repeatEnd.IsZero() && currentStart.After(repeatEnd) {
-				break
-			}
+// descriptionEditedMsg brings the description back from the external editor
+type descriptionEditedMsg struct {
+	text string
+	err  error
+}
 
-			// Move to next occurrence based on repeat type
-			switch repeatType {
-			case "daily":
-				currentStart = currentStart.AddDate(0, 0, 1)
-				currentEnd = currentEnd.AddDate(0, 0, 1)
-			case "weekly":
-				currentStart = currentStart.AddDate(0, 0, 7)
-				currentEnd = currentEnd.AddDate(0, 0, 7)
-			case "monthly":
-				currentStart = currentStart.AddDate(0, 1, 0)
-				currentEnd = currentEnd.AddDate(0, 1, 0)
-			}
+// editDescription suspends the TUI to edit the form's description in
+// $VISUAL or $EDITOR, or vi without either
+func editDescription(description string) tea.Cmd {
+	file, err := os.CreateTemp("", "zebracal-*.txt")
+	if err != nil {
+		return func() tea.Msg { return descriptionEditedMsg{err: err} }
+	}
+	path := file.Name()
+	_, err = file.WriteString(description)
+	file.Close()
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return descriptionEditedMsg{err: err} }
+	}
 
-			// If no end date specified, create a reasonable number of occurrences
-			if repeatEnd.IsZero() && iteration >= 52 { // Stop after 52 weeks for weekly, etc.
-				break
-			}
+	editor := strings.Fields(cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi"))
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return descriptionEditedMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		// Editors end the file with a newline the description doesn't need
+		return descriptionEditedMsg{text: strings.TrimRight(string(data), "\n"), err: err}
+	})
+}
 
-			iteration++
+// formTimes parses the form's date, times and end date into an event's start
+// and end. Without times the event lasts from the start of the date to the
+// end of the end date, which defaults to the date. On errors it also returns
+// the field to correct.
+func formTimes(dateStr, startTime, endTime, endDate string) (time.Time, time.Time, int, error) {
+	date, err := time.ParseInLocation("02-01-2006", dateStr, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fieldDate, fmt.Errorf("Invalid date: %v (use DD-MM-YYYY)", err)
+	}
+	endDay := date
+	if endDate != "" {
+		endDay, err = time.ParseInLocation("02-01-2006", endDate, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fieldEndDate, fmt.Errorf("Invalid end date: %v (use DD-MM-YYYY)", err)
 		}
-	} else {
-		// Single event
-		event := &Event{
-			Summary:      *m.formSummary,
-			Description:  *m.formDescription,
-			Start:        start,
-			End:          end,
-			CalendarName: *m.formCalendar,
+		if endDay.Before(date) {
+			return time.Time{}, time.Time{}, fieldEndDate, fmt.Errorf("End date must not be before the date")
 		}
+	}
 
-		if color, ok := m.calendars[*m.formCalendar]; ok {
-			event.CalendarColor = color
-		}
+	if startTime == "" || endTime == "" {
+		start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+		end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), 23, 59, 0, 0, time.Local)
+		return start, end, 0, nil
+	}
 
-		eventsToCreate = append(eventsToCreate, event)
+	startClock, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fieldStartTime, fmt.Errorf("Invalid time format (use HH:MM)")
+	}
+	endClock, err := time.Parse("15:04", endTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fieldEndTime, fmt.Errorf("Invalid time format (use HH:MM)")
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), startClock.Hour(), startClock.Minute(), 0, 0, time.Local)
+	end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), endClock.Hour(), endClock.Minute(), 0, 0, time.Local)
+	if !end.After(start) {
+		if endDate == "" {
+			return time.Time{}, time.Time{}, fieldEndDate, fmt.Errorf("End time must be after start time, set the end date for overnight events")
+		}
+		return time.Time{}, time.Time{}, fieldEndDate, fmt.Errorf("End must be after start")
 	}
+	return start, end, 0, nil
+}
 
-	// Save events to Radicale if configured, otherwise save locally
-	savedCount := 0
-	for _, event := range eventsToCreate {
-		if m.radicaleConfig != nil && m.calendarURLs[*m.formCalendar] != "" {
-			if err := createEventOnRadicale(m.calendarURLs[*m.formCalendar], event, m.radicaleConfig); err != nil {
-				m.message = fmt.Sprintf("Error creating event: %v", err)
-				m.creationMode = NoCreation
-				m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-				return m, m.eventForm.Init()
+// formRRule builds the RRULE of a repeat chosen in the form: freq is
+// "daily", "weekly", "monthly" or "yearly" and days are BYDAY codes, with an
+// ordinal for monthly ones ("-1FR"). The series ends at until or after count
+// occurrences, or never if neither is set.
+func formRRule(freq string, interval int, days []string, until time.Time, count int) string {
+	rule := "FREQ=" + strings.ToUpper(freq)
+	if interval > 1 {
+		rule += fmt.Sprintf(";INTERVAL=%d", interval)
+	}
+	if len(days) > 0 {
+		// Keep the week's order whatever order the days were picked in
+		var ordered []string
+		for _, option := range weekdayOptions {
+			for _, day := range days {
+				if strings.HasSuffix(day, option.Value) {
+					ordered = append(ordered, day)
+				}
 			}
 		}
-		m.events = append(m.events, *event)
-		savedCount++
+		rule += ";BYDAY=" + strings.Join(ordered, ",")
+	}
+	if !until.IsZero() {
+		rule += ";UNTIL=" + until.UTC().Format("20060102T150405Z")
+	} else if count > 0 {
+		rule += fmt.Sprintf(";COUNT=%d", count)
 	}
+	return rule
+}
+
+// ordinals name the weeks of a month for the monthly repeat options
+var ordinals = []string{"first", "second", "third", "fourth", "fifth"}
 
-	if savedCount > 0 {
-		if savedCount == 1 {
-			m.message = "Event created successfully!"
-		} else {
-			m.message = fmt.Sprintf("%d events created successfully!", savedCount)
+// monthlyOptions are the ways a monthly repeat can follow the date: on its
+// day of the month, on its weekday by number ("third Friday"), and on the
+// last of its weekday if it is
+func monthlyOptions(dateStr string) []huh.Option[string] {
+	date, err := time.Parse("02-01-2006", dateStr)
+	if err != nil {
+		return []huh.Option[string]{huh.NewOption("On the same day each month", "day")}
+	}
+	weekday := date.Weekday().String()
+	options := []huh.Option[string]{
+		huh.NewOption(fmt.Sprintf("On day %d", date.Day()), "day"),
+		huh.NewOption(fmt.Sprintf("On the %s %s", ordinals[(date.Day()-1)/7], weekday), "nth"),
+	}
+	if _, ok := monthlyByDay(date, "last"); ok {
+		options = append(options, huh.NewOption("On the last "+weekday, "last"))
+	}
+	return options
+}
+
+// monthlyByDay returns the BYDAY code of a monthly repeat on t's weekday,
+// "nth" counting from the start of the month and "last" from its end. It
+// is false if t isn't the last of its weekday in the month.
+func monthlyByDay(t time.Time, choice string) (string, bool) {
+	if choice == "last" {
+		if t.AddDate(0, 0, 7).Month() == t.Month() {
+			return "", false
 		}
+		return "-1" + weekdayCode(t), true
 	}
+	return fmt.Sprintf("%d%s", (t.Day()-1)/7+1, weekdayCode(t)), true
+}
 
-	m.creationMode = NoCreation
-	// Rebuild form for next time
-	m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-	return m, m.eventForm.Init()
+// weekdayCode returns the BYDAY code of t's weekday
+func weekdayCode(t time.Time) string {
+	return strings.ToUpper(t.Weekday().String()[:2])
 }
 
 func (m model) renderFormSummary() string {
@@ -295,17 +698,34 @@ func (m model) renderFormSummary() string {
 	}
 
 	if m.formDescription != nil && *m.formDescription != "" {
-		desc := *m.formDescription
-		if len(desc) > 40 {
-			desc = desc[:40] + "..."
+		lines := strings.Split(*m.formDescription, "\n")
+		desc := truncate(lines[0], 40)
+		if len(lines) > 1 {
+			desc += fmt.Sprintf(" (+%d lines)", len(lines)-1)
 		}
 		b.WriteString(fmt.Sprintf("Description: %s\n", desc))
 	}
 
+	if attendees, names, err := parseAttendees(*m.formAttendees, m.contacts); err == nil && len(attendees) > 0 {
+		var labels []string
+		for _, address := range attendees {
+			labels = append(labels, cmp.Or(names[address], address))
+		}
+		b.WriteString(fmt.Sprintf("Attendees: %s\n", truncate(strings.Join(labels, ", "), 40)))
+	}
+
+	if m.formURL != nil && *m.formURL != "" {
+		b.WriteString(fmt.Sprintf("URL: %s\n", truncate(*m.formURL, 40)))
+	}
+
 	if m.formDate != nil && *m.formDate != "" {
 		b.WriteString(fmt.Sprintf("Date: %s\n", *m.formDate))
 	}
 
+	if m.formEndDate != nil && *m.formEndDate != "" {
+		b.WriteString(fmt.Sprintf("End Date: %s\n", *m.formEndDate))
+	}
+
 	if m.formStartTime != nil && m.formEndTime != nil {
 		b.WriteString(fmt.Sprintf("Time: %s - %s\n", *m.formStartTime, *m.formEndTime))
 	}
@@ -315,16 +735,15 @@ func (m model) renderFormSummary() string {
 	}
 
 	if m.formRepeatOptions != nil && *m.formRepeatOptions != "" && *m.formRepeatOptions != "none" {
-		// Capitalize first letter for display
-		opt := *m.formRepeatOptions
-		displayOpt := opt
-		if len(opt) > 0 {
-			displayOpt = strings.ToUpper(opt[:1]) + opt[1:]
-		}
-		b.WriteString(fmt.Sprintf("Repeat: %s\n", displayOpt))
-		if m.formRepeatEndDate != nil && *m.formRepeatEndDate != "" {
-			b.WriteString(fmt.Sprintf("Until: %s\n", *m.formRepeatEndDate))
+		// Described from the RRULE that will be saved, while it is valid
+		repeat := *m.formRepeatOptions
+		if start, end, _, err := formTimes(*m.formDate, *m.formStartTime, *m.formEndTime, *m.formEndDate); err == nil {
+			if rrule, start, _, _, err := m.formRepetition(start, end); err == nil && rrule != "" {
+				repeat = humanizeRRule(rrule, start)
+			}
 		}
+		b.WriteString(fmt.Sprintf("Repeat: %s\n", repeat))
+		b.WriteString(m.renderRecurrencePreview())
 	}
 
 	return summaryStyle.Render(b.String())
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+// freeBusyDays is how far ahead colleagues' busy times are queried in the
+// app, the same range free slots are searched in
+const freeBusyDays = 14
+
+// busyPeriods returns the times my events block within [from, to)
+func busyPeriods(events []Event, from, to time.Time) []ical.Period {
+	var periods []ical.Period
+	for _, event := range events {
+		if busy(event) && event.End.After(from) && event.Start.Before(to) {
+			periods = append(periods, ical.Period{Start: event.Start, End: event.End})
+		}
+	}
+	return periods
+}
+
+// exportFreeBusy renders a VFREEBUSY of the next days, starting today, for
+// publishing availability (--freebusy)
+func exportFreeBusy(events []Event, config *Config, days int, now time.Time) string {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, days)
+	organizer := ""
+	if config != nil && len(config.Emails) > 0 {
+		organizer = config.Emails[0]
+	}
+	return ical.BuildFreeBusy(busyPeriods(events, from, to), from, to, organizer)
+}
+
+// loadFreeBusyCmd queries the busy times of the configured colleagues for
+// the next days, starting today
+func loadFreeBusyCmd(colleagues []ColleagueConfig, config *RadicaleConfig, days int, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		to := from.AddDate(0, 0, days)
+
+		client := newCalDAVClient(config)
+		busy := make(map[string][]ical.Period)
+		var failed []string
+		for _, colleague := range colleagues {
+			doc, err := client.FreeBusy(colleague.URL, from, to)
+			if err == nil {
+				var periods []ical.Period
+				if periods, err = ical.ParseFreeBusy(strings.NewReader(doc)); err == nil {
+					busy[colleague.Name] = periods
+					continue
+				}
+			}
+			failed = append(failed, fmt.Sprintf("%s: %v", colleague.Name, err))
+		}
+		return freeBusyLoadedMsg{busy: busy, failed: failed}
+	}
+}
+
+// refreshFreeBusy reloads colleagues' busy times for the next days if any
+// are configured
+func (m model) refreshFreeBusy(days int) tea.Cmd {
+	if m.config == nil || len(m.config.Colleagues) == 0 {
+		return nil
+	}
+	return loadFreeBusyCmd(m.config.Colleagues, m.radicaleConfig, days, m.clock.Now())
+}
+
+func (m model) applyFreeBusy(msg freeBusyLoadedMsg) model {
+	m.colleagueBusy = msg.busy
+	if len(msg.failed) > 0 {
+		m.message = tr("Free/busy lookup failed: ") + strings.Join(msg.failed, "; ")
+	}
+	return m
+}
+
+// colleagueBlocks turns colleagues' busy times into opaque events, so free
+// slot searches only offer times that suit everyone
+func (m model) colleagueBlocks() []Event {
+	var blocks []Event
+	for name, periods := range m.colleagueBusy {
+		for _, p := range periods {
+			blocks = append(blocks, Event{
+				Summary:      tr("Busy"),
+				Start:        p.Start.In(time.Local),
+				End:          p.End.In(time.Local),
+				CalendarName: name,
+				Transp:       "OPAQUE",
+			})
+		}
+	}
+	return blocks
+}
+
+// renderColleagueBusy lists who is busy when on a day, e.g.
+// "Busy: Alice 10:00–11:00, Bob 14:00–15:30", or "" if nobody is
+func (m model) renderColleagueBusy(date time.Time) string {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	var names []string
+	for name := range m.colleagueBusy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		for _, p := range m.colleagueBusy[name] {
+			start, end := p.Start.In(time.Local), p.End.In(time.Local)
+			if end.After(dayStart) && start.Before(dayEnd) {
+				parts = append(parts, fmt.Sprintf("%s %s–%s", name, start.Format("15:04"), end.Format("15:04")))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return tr("Busy: ") + strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/google"
+	"mytuiapp/internal/oauth"
+)
+
+const googleTokenFile = "google_token.json"
+
+// googleOAuth returns the OAuth client configured under "google"
+func googleOAuth(config *GoogleConfig) (*oauth.Client, error) {
+	if config == nil || config.ClientID == "" {
+		return nil, fmt.Errorf(`set "google": {"client_id": ..., "client_secret": ...} in the config`)
+	}
+	return google.OAuth(httpClient, config.ClientID, config.ClientSecret), nil
+}
+
+// googleClient returns an API client with a valid access token
+func googleClient(config *GoogleConfig) (*google.Client, error) {
+	client, err := googleOAuth(config)
+	if err != nil {
+		return nil, err
+	}
+	token, err := validToken(client, googleTokenFile)
+	if err != nil {
+		return nil, err
+	}
+	return &google.Client{HTTP: httpClient, Token: token}, nil
+}
+
+// googleProvider reads a Google calendar. Google calendars are read-only
+// for now.
+type googleProvider struct {
+	singleCalendar
+	readOnly
+	config *GoogleConfig
+}
+
+func newGoogleProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &googleProvider{singleCalendar: singleCalendar{entry}, config: config.Google}
+}
+
+func (p *googleProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	client, err := googleClient(p.config)
+	if err != nil {
+		return nil, err
+	}
+	calendarID := cal.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	items, err := client.Events(calendarID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, item := range items {
+		if item.Status == "cancelled" {
+			continue
+		}
+		start, err := item.Start.Time(time.Local)
+		if err != nil {
+			continue
+		}
+		end, err := item.End.Time(time.Local)
+		if err != nil {
+			end = start.Add(time.Hour)
+		}
+
+		summary := item.Summary
+		if summary == "" {
+			summary = "(No title)"
+		}
+		var attendees []string
+		for _, attendee := range item.Attendees {
+			attendees = append(attendees, strings.ToLower(attendee.Email))
+		}
+		events = append(events, Event{
+			Summary:      summary,
+			Start:        start,
+			End:          end,
+			Description:  item.Description,
+			Location:     item.Location,
+			CalendarName: cal.Name,
+			UID:          item.ICalUID,
+			LastModified: item.Updated,
+			Transp:       strings.ToUpper(item.Transparency),
+			Organizer:    strings.ToLower(item.Organizer.Email),
+			Attendees:    attendees,
+		})
+	}
+	return events, nil
+}
+
+// runGoogleLoginCommand authorizes zebracal for the user's Google calendars
+// with the device flow and lists them, for adding to the config
+func runGoogleLoginCommand() {
+	config, _ := loadConfig()
+	var googleConfig *GoogleConfig
+	if config != nil {
+		if err := setupHTTPClient(config.HTTP); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		googleConfig = config.Google
+	}
+
+	client, err := googleOAuth(googleConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	token, err := deviceLogin(client, googleTokenFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	calendars, err := (&google.Client{HTTP: httpClient, Token: token}).Calendars()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Logged in. Add calendars to the config like")
+	fmt.Println(`  {"name": "Work", "type": "google", "calendar_id": "primary"}`)
+	fmt.Println()
+	for _, cal := range calendars {
+		id := cal.ID
+		if cal.Primary {
+			id = "primary"
+		}
+		fmt.Printf("  %-40s %s\n", id, cal.Summary)
+	}
+}
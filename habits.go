@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	habitStateFile = "habits.json"
+	habitDays      = 14 // Days shown in a habit's heatmap
+)
+
+// habitLog records the days each habit was done. Habits are daily
+// recurring events, keyed by seriesID; days are "2006-01-02".
+type habitLog struct {
+	Done map[string]map[string]bool `json:"done"`
+}
+
+func loadHabitLog() (*habitLog, error) {
+	log := &habitLog{Done: make(map[string]map[string]bool)}
+
+	statePath, err := getStatePath(habitStateFile)
+	if err != nil {
+		return log, err
+	}
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return log, nil
+		}
+		return log, err
+	}
+	if err := json.Unmarshal(data, log); err != nil {
+		return log, err
+	}
+	if log.Done == nil {
+		log.Done = make(map[string]map[string]bool)
+	}
+	return log, nil
+}
+
+func (l *habitLog) save() error {
+	statePath, err := getStatePath(habitStateFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath, data, 0644)
+}
+
+// isHabit reports whether an event can be tracked as a habit
+func isHabit(event Event) bool {
+	return strings.Contains(strings.ToUpper(event.RRule), "FREQ=DAILY")
+}
+
+func (l *habitLog) done(id string, day time.Time) bool {
+	return l != nil && l.Done[id][day.Format("2006-01-02")]
+}
+
+// toggle marks a day of a habit done, or not done if it already was, and
+// returns the new state
+func (l *habitLog) toggle(id string, day time.Time) bool {
+	key := day.Format("2006-01-02")
+	if l.Done[id] == nil {
+		l.Done[id] = make(map[string]bool)
+	}
+	if l.Done[id][key] {
+		delete(l.Done[id], key)
+		if len(l.Done[id]) == 0 {
+			delete(l.Done, id)
+		}
+		return false
+	}
+	l.Done[id][key] = true
+	return true
+}
+
+// streak counts the consecutive days a habit was done up to day. A day
+// that isn't done yet doesn't break the streak until it is over.
+func (l *habitLog) streak(id string, day time.Time) int {
+	if !l.done(id, day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	count := 0
+	for l.done(id, day) {
+		count++
+		day = day.AddDate(0, 0, -1)
+	}
+	return count
+}
+
+// toggleHabitDone marks the focused occurrence of a habit done (x)
+func (m model) toggleHabitDone() model {
+	if m.viewMode != DailyView || m.habits == nil {
+		return m
+	}
+	dayEvents := m.dailyEvents()
+	if m.cursor >= len(dayEvents) {
+		return m
+	}
+	event := dayEvents[m.cursor]
+	if !isHabit(event) {
+		m.message = tr("Only daily events can be tracked")
+		return m
+	}
+
+	id := seriesID(event)
+	done := m.habits.toggle(id, event.Start)
+	if err := m.habits.save(); err != nil {
+		m.message = fmt.Sprintf("Error: %v", err)
+		return m
+	}
+	if done {
+		m.message = fmt.Sprintf(tr("%s done, %d day streak"), m.eventTitle(event), m.habits.streak(id, event.Start))
+	} else {
+		m.message = fmt.Sprintf(tr("%s not done"), m.eventTitle(event))
+	}
+	return m
+}
+
+// renderHabit shows a habit's streak and which of the last days it was done,
+// e.g. "🔥 3  ■■□■■■■■□□■■■■", or "" for events without history
+func (m model) renderHabit(event Event) string {
+	if !isHabit(event) || m.habits == nil {
+		return ""
+	}
+	id := seriesID(event)
+	if len(m.habits.Done[id]) == 0 {
+		return ""
+	}
+
+	doneStyle := lipgloss.NewStyle().Foreground(eventColor(event))
+	missedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	var heatmap strings.Builder
+	for i := habitDays - 1; i >= 0; i-- {
+		if m.habits.done(id, event.Start.AddDate(0, 0, -i)) {
+			heatmap.WriteString(doneStyle.Render("■"))
+		} else {
+			heatmap.WriteString(missedStyle.Render("□"))
+		}
+	}
+	return fmt.Sprintf("🔥 %d  ", m.habits.streak(id, event.Start)) + heatmap.String()
+}
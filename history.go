@@ -0,0 +1,75 @@
+package main
+
+import "time"
+
+// maxJumps bounds the jump history in each direction
+const maxJumps = 50
+
+// jumpPosition is a place in the jump history
+type jumpPosition struct {
+	date time.Time
+	view ViewMode
+}
+
+func (m model) position() jumpPosition {
+	return jumpPosition{date: m.currentDate, view: m.viewMode}
+}
+
+// jumpTo moves to date in view, remembering where we came from so ctrl+o
+// can return there. Stepping with ←/→ is not a jump.
+func (m model) jumpTo(date time.Time, view ViewMode) model {
+	from := m.position()
+	if !sameDay(from.date, date) || from.view != view {
+		m.jumpBack = pushJump(m.jumpBack, from)
+		m.jumpForward = nil
+	}
+	m.currentDate = date
+	m.viewMode = view
+	m.dayInput = ""
+	m.cursor = 0
+	return m
+}
+
+// jumpBackward returns to the previous position (ctrl+o)
+func (m model) jumpBackward() model {
+	if len(m.jumpBack) == 0 {
+		return m
+	}
+	m.jumpForward = pushJump(m.jumpForward, m.position())
+	m = m.restoreJump(m.jumpBack[len(m.jumpBack)-1])
+	m.jumpBack = m.jumpBack[:len(m.jumpBack)-1]
+	return m
+}
+
+// jumpAhead redoes a jump undone by jumpBackward (ctrl+i, which terminals
+// send as tab)
+func (m model) jumpAhead() model {
+	if len(m.jumpForward) == 0 {
+		return m
+	}
+	m.jumpBack = pushJump(m.jumpBack, m.position())
+	m = m.restoreJump(m.jumpForward[len(m.jumpForward)-1])
+	m.jumpForward = m.jumpForward[:len(m.jumpForward)-1]
+	return m
+}
+
+func (m model) restoreJump(pos jumpPosition) model {
+	m.currentDate = pos.date
+	m.viewMode = pos.view
+	m.dayInput = ""
+	m.cursor = 0
+	return m
+}
+
+// pushJump appends pos to a copy of jumps, dropping the oldest entry past
+// maxJumps. Copying keeps earlier models from sharing the slice.
+func pushJump(jumps []jumpPosition, pos jumpPosition) []jumpPosition {
+	if len(jumps) >= maxJumps {
+		jumps = jumps[len(jumps)-maxJumps+1:]
+	}
+	return append(append([]jumpPosition(nil), jumps...), pos)
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
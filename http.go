@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"mytuiapp/internal/caldav"
+)
+
+const (
+	defaultHTTPTimeout = 10 * time.Second
+	defaultMaxAttempts = 3
+)
+
+// maxAttempts is how often a request is tried before giving up
+var maxAttempts = defaultMaxAttempts
+
+// httpClient is shared by all calendar requests so connections are pooled.
+// It is rebuilt from the config by setupHTTPClient.
+var httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+
+// setupHTTPClient configures the shared client's timeout, proxy and TLS
+// settings. A nil config keeps the defaults, with the proxies of the
+// environment.
+func setupHTTPClient(config *HTTPConfig) error {
+	if config == nil {
+		config = &HTTPConfig{}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy, err := proxyFunc(config)
+	if err != nil {
+		return err
+	}
+	transport.Proxy = proxy
+
+	if len(config.CACerts) > 0 || config.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+		if len(config.CACerts) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			for _, certPath := range config.CACerts {
+				pem, err := os.ReadFile(certPath)
+				if err != nil {
+					return fmt.Errorf("failed to read CA certificate: %v", err)
+				}
+				if !pool.AppendCertsFromPEM(pem) {
+					return fmt.Errorf("no certificates found in %s", certPath)
+				}
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if config.MaxAttempts > 0 {
+		maxAttempts = config.MaxAttempts
+	}
+
+	timeout := defaultHTTPTimeout
+	if config.TimeoutSeconds > 0 {
+		timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if config.UserAgent != "" {
+		roundTripper = userAgentTransport{userAgent: config.UserAgent, next: transport}
+	}
+
+	httpClient = &http.Client{Timeout: timeout, Transport: roundTripper}
+	return nil
+}
+
+// proxyFunc picks the proxy of a request: that of its server in proxies,
+// else the configured proxy, else HTTP_PROXY, HTTPS_PROXY or ALL_PROXY
+// from the environment unless NO_PROXY exempts the server
+func proxyFunc(config *HTTPConfig) (func(*http.Request) (*url.URL, error), error) {
+	servers := make(map[string]*url.URL)
+	for host, value := range config.Proxies {
+		proxyURL, err := parseProxy(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy for %s: %v", host, err)
+		}
+		servers[strings.ToLower(strings.TrimPrefix(host, "."))] = proxyURL
+	}
+
+	var fallback func(*url.URL) (*url.URL, error)
+	if config.Proxy != "" {
+		proxyURL, err := parseProxy(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		fallback = func(*url.URL) (*url.URL, error) { return proxyURL, nil }
+	} else {
+		// Go only reads HTTP_PROXY and HTTPS_PROXY, curl's ALL_PROXY
+		// (often a SOCKS proxy) covers both
+		environment := httpproxy.FromEnvironment()
+		all := cmp.Or(os.Getenv("ALL_PROXY"), os.Getenv("all_proxy"))
+		environment.HTTPProxy = cmp.Or(environment.HTTPProxy, all)
+		environment.HTTPSProxy = cmp.Or(environment.HTTPSProxy, all)
+		fallback = environment.ProxyFunc()
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		// The server's own proxy, or that of the closest parent domain
+		for host := strings.ToLower(req.URL.Hostname()); host != ""; {
+			if proxyURL, ok := servers[host]; ok {
+				return proxyURL, nil
+			}
+			_, parent, found := strings.Cut(host, ".")
+			if !found {
+				break
+			}
+			host = parent
+		}
+		return fallback(req.URL)
+	}, nil
+}
+
+// parseProxy parses a proxy URL of a scheme the transport supports
+func parseProxy(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return proxyURL, nil
+	}
+	return nil, fmt.Errorf("unsupported scheme in %s, use http, https or socks5", value)
+}
+
+// userAgentTransport sets the configured User-Agent on requests that don't
+// have one, such as those of a calendar with its own in its headers
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// newCalDAVClient returns a client using the shared HTTP client, authenticated
+// for the Radicale server if config is not nil
+func newCalDAVClient(config *RadicaleConfig) *caldav.Client {
+	client := &caldav.Client{HTTP: httpClient, MaxAttempts: maxAttempts}
+	if config != nil {
+		client.Username = config.Username
+		client.Password = config.Password
+	}
+	return client
+}
+
+// calendarClient is newCalDAVClient for a calendar, sending the headers
+// configured for it
+func calendarClient(config *RadicaleConfig, cal CalendarConfig) *caldav.Client {
+	client := newCalDAVClient(config)
+	client.Header = cal.Headers
+	return client
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// spanFlag is a duration flag that also takes days and weeks, e.g. "7d",
+// "2w" or "36h". A bare number counts days.
+type spanFlag time.Duration
+
+func (f *spanFlag) String() string {
+	if f == nil || *f == 0 {
+		return ""
+	}
+	return time.Duration(*f).String()
+}
+
+func (f *spanFlag) Set(value string) error {
+	span, err := parseSpan(value)
+	if err != nil {
+		return err
+	}
+	*f = spanFlag(span)
+	return nil
+}
+
+func parseSpan(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "": 24 * time.Hour}
+	number := strings.TrimRight(value, "dw")
+	if unit, ok := units[value[len(number):]]; ok {
+		if n, err := strconv.Atoi(number); err == nil && n > 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	span, err := time.ParseDuration(value)
+	if err != nil || span <= 0 {
+		return 0, fmt.Errorf("expected a span like 7d, 2w or 12h, got %q", value)
+	}
+	return span, nil
+}
+
+// humanizeDay names a day relative to today: "Today", "Tomorrow",
+// "Friday (in 3 days)" within the week and "Mon Oct 26 (in 10 days)" after
+func humanizeDay(day, now time.Time) string {
+	days := dayIndex(now, day)
+	switch {
+	case days == 0:
+		return tr("Today")
+	case days == 1:
+		return tr("Tomorrow")
+	case days < 7:
+		return formatDate(day, "Monday") + " " + fmt.Sprintf(tr("(in %d days)"), days)
+	}
+	return formatDate(day, "Mon Jan 2") + " " + fmt.Sprintf(tr("(in %d days)"), days)
+}
+
+// humanizeUntil phrases the time until a start later today: "in 5 minutes",
+// "in 1 hour", "in 3 hours"
+func humanizeUntil(start, now time.Time) string {
+	until := start.Sub(now)
+	switch {
+	case until < time.Minute:
+		return tr("now")
+	case until < 2*time.Minute:
+		return tr("in 1 minute")
+	case until < time.Hour:
+		return fmt.Sprintf(tr("in %d minutes"), int(until.Minutes()))
+	case until < 2*time.Hour:
+		return tr("in 1 hour")
+	}
+	return fmt.Sprintf(tr("in %d hours"), int(until.Hours()))
+}
+
+// renderHumanized lists events grouped by day with relative phrasing, for a
+// morning mail or motd from cron (--upcoming 7d --humanize)
+func renderHumanized(events []Event, now time.Time) string {
+	if len(events) == 0 {
+		return tr("No upcoming events")
+	}
+
+	dayStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("117"))
+	untilStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	// Pinned events first within each day
+	events = slices.Clone(events)
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !dayStart(a.Start).Equal(dayStart(b.Start)) {
+			return a.Start.Before(b.Start)
+		}
+		return isPinned(a) && !isPinned(b)
+	})
+
+	whenWidth := max(5, lipgloss.Width(tr("all day")))
+	var b strings.Builder
+	var current time.Time
+	for _, event := range events {
+		if day := dayStart(event.Start); !day.Equal(current) {
+			if !current.IsZero() {
+				b.WriteString("\n")
+			}
+			current = day
+			b.WriteString(dayStyle.Render(humanizeDay(day, now)) + "\n")
+		}
+
+		when := event.Start.Format("15:04")
+		if isAllDay(event) {
+			when = tr("all day")
+		}
+		title := event.Summary
+		if isPinned(event) {
+			title = "★ " + title
+		}
+		if location := strings.TrimSpace(event.Location); location != "" {
+			title += " · " + location
+		}
+		b.WriteString(timeStyle.Render(fmt.Sprintf("  %-*s ", whenWidth, when)) + lipgloss.NewStyle().Foreground(eventColor(event)).Render(title))
+		if dayIndex(now, event.Start) == 0 && !isAllDay(event) {
+			b.WriteString(untilStyle.Render(" (" + humanizeUntil(event.Start, now) + ")"))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
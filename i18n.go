@@ -0,0 +1,435 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// locale holds the translations for one language. Weekday arrays are indexed
+// by time.Weekday (Sunday first).
+type locale struct {
+	weekdays      [7]string
+	shortWeekdays [7]string
+	months        [12]string
+	shortMonths   [12]string
+	strings       map[string]string // UI strings keyed by their English text
+	nl            nlWords
+}
+
+// nlWords are the words understood by the natural language parser in
+// addition to the English ones
+type nlWords struct {
+	today       []string
+	tomorrow    []string
+	nextWeek    []string
+	timeWords   map[string]int    // e.g. "afternoon" -> 14
+	dayParts    map[string]string // e.g. "lunch" -> "12:00-13:00", or "all day"
+	hourUnits   []string
+	minuteUnits []string
+}
+
+var englishLocale = &locale{
+	weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	nl: nlWords{
+		today:    []string{"today"},
+		tomorrow: []string{"tomorrow"},
+		nextWeek: []string{"next week"},
+		timeWords: map[string]int{
+			"morning":   9,
+			"afternoon": 14,
+			"evening":   18,
+			"noon":      12,
+			"midnight":  0,
+		},
+		dayParts: map[string]string{
+			"all day":       "all day",
+			"all afternoon": "13:00-17:00",
+			"lunch":         "12:00-13:00",
+		},
+		hourUnits:   []string{"hours", "hour", "h"},
+		minuteUnits: []string{"minutes", "minute", "min"},
+	},
+}
+
+var germanLocale = &locale{
+	weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	shortWeekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+		"Juli", "August", "September", "Oktober", "November", "Dezember"},
+	shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+	strings: map[string]string{
+		"📅 Daily View":                                 "📅 Tagesansicht",
+		"📅 Weekly View":                                "📅 Wochenansicht",
+		"📅 Monthly View":                               "📅 Monatsansicht",
+		"📅 Rolling View":                               "📅 Nächste Wochen",
+		"%s to %s":                                     "%s bis %s",
+		"📅 Next Event":                                 "📅 Nächster Termin",
+		"📅 Upcoming Events":                            "📅 Anstehende Termine",
+		"%s, %s (Week %d)":                             "%s, %s (KW %d)",
+		"Week %d - %s to %s":                           "KW %d - %s bis %s",
+		"No events scheduled for this day":             "Keine Termine an diesem Tag",
+		"  No events":                                  "  Keine Termine",
+		"No upcoming events":                           "Keine anstehenden Termine",
+		"Calendars:":                                   "Kalender:",
+		"Loading Calendars...":                         "Kalender werden geladen...",
+		"Jump to day: %s (press Enter)":                "Springe zu Tag: %s (Enter drücken)",
+		"Note: Using sample data (no calendars found)": "Hinweis: Beispieldaten (keine Kalender gefunden)",
+		"%d selected":                                  "%d ausgewählt",
+		"1 pending change":                             "1 ausstehende Änderung",
+		"%d pending changes":                           "%d ausstehende Änderungen",
+		"REDACTED":                                     "ANONYMISIERT",
+		"Creating events %d/%d":                        "Erstelle Termine %d/%d",
+		"esc: cancel":                                  "Esc: abbrechen",
+		"📅 Changes Since Last Run":                     "📅 Änderungen seit dem letzten Start",
+		"No changes since last run":                    "Keine Änderungen seit dem letzten Start",
+		"Since last run: %s":                           "Seit dem letzten Start: %s",
+		"%d calendar changes since last run":           "%d Kalenderänderungen seit dem letzten Start",
+		"− %s %s cancelled":                            "− %s %s abgesagt",
+		"~ %s %s updated":                              "~ %s %s geändert",
+		"and %d more":                                  "und %d weitere",
+		"until %s":                                     "bis %s",
+		"%s not done":                                  "%s nicht erledigt",
+		"%s finished":                                  "%s beendet",
+		"%s stopped":                                   "%s gestoppt",
+		"⏰ %s starts in %dm (%s)":                      "⏰ %s beginnt in %d Min. (%s)",
+		"↑ %d more":                                    "↑ %d weitere",
+		"↓ %d more":                                    "↓ %d weitere",
+		"  … %d more":                                  "  … %d weitere",
+		"Event":                                        "Termin",
+		"Details hidden":                               "Details ausgeblendet",
+		"Blocked %s %s–%s":                             "%s eingetragen: %s–%s",
+		"No free slot in the next %d days":             "Kein freier Zeitraum in den nächsten %d Tagen",
+		"Block focus time on %s":                       "Fokuszeit am %s eintragen",
+		"No free time in working hours":                "Keine freie Zeit in der Arbeitszeit",
+		"Free: ":                                       "Frei: ",
+		"Free for everyone: ":                          "Frei für alle: ",
+		"%s done, %d day streak":                       "%s erledigt, %d Tage in Folge",
+		"Only daily events can be tracked":             "Nur tägliche Termine können verfolgt werden",
+		"Busy: ":                                       "Belegt: ",
+		"Busy":                                         "Belegt",
+		"Free/busy lookup failed: ":                    "Frei/Belegt-Abfrage fehlgeschlagen: ",
+		"Note for %s":                                  "Notiz für %s",
+		"Leave empty to delete the note":               "Leer lassen, um die Notiz zu löschen",
+		"Note saved":                                   "Notiz gespeichert",
+		"Note deleted":                                 "Notiz gelöscht",
+		"Failed to save note: %v":                      "Notiz konnte nicht gespeichert werden: %v",
+		"Daily notes need a Radicale calendar":         "Tagesnotizen brauchen einen Radicale-Kalender",
+		"d: daily":                                     "d: Tag",
+		"w: weekly":                                    "w: Woche",
+		"m: monthly":                                   "m: Monat",
+		"g: rolling":                                   "g: Wochen",
+		"Shared calendars: only my events":             "Geteilte Kalender: nur meine Termine",
+		"Shared calendars: all events":                 "Geteilte Kalender: alle Termine",
+		"Set emails in the config first":               "Zuerst emails in der Konfiguration setzen",
+		"← →: navigate":                                "← →: blättern",
+		"t: today":                                     "t: heute",
+		"^o/^i: jump back/forward":                     "^o/^i: Sprung zurück/vor",
+		"r: refresh":                                   "r: aktualisieren",
+		"Z: redact":                                    "Z: anonymisieren",
+		"u: mine":                                      "u: meine",
+		"j/k: move":                                    "j/k: bewegen",
+		"space/V: select":                              "Leertaste/V: auswählen",
+		"D/C/</>/E: bulk":                              "D/C/</>/E: Mehrfachaktion",
+		"x: done":                                      "x: erledigt",
+		":day + Enter: jump":                           ":Tag + Enter: springen",
+		"n: new event":                                 "n: neuer Termin",
+		"n/o: new event":                               "n/o: neuer Termin",
+		"o: new after focused":                         "o: neu nach markiertem",
+		"J: note":                                      "J: Notiz",
+		"p: timer":                                     "p: Timer",
+		"esc/p: stop":                                  "esc/p: stoppen",
+		"b/B: focus":                                   "b/B: Fokuszeit",
+		"F: free time":                                 "F: freie Zeit",
+		"q: quit":                                      "q: beenden",
+
+		// --upcoming --humanize
+		"Today":         "Heute",
+		"Tomorrow":      "Morgen",
+		"(in %d days)":  "(in %d Tagen)",
+		"now":           "jetzt",
+		"in 1 minute":   "in 1 Minute",
+		"in %d minutes": "in %d Minuten",
+		"in 1 hour":     "in 1 Stunde",
+		"in %d hours":   "in %d Stunden",
+		"all day":       "ganztägig",
+
+		// --motd
+		"nothing left today":   "heute nichts mehr",
+		"1 event left today":   "heute noch 1 Termin",
+		"%d events left today": "heute noch %d Termine",
+		"Next:":                "Als Nächstes:",
+		"(as of %s)":           "(Stand %s)",
+		"📅 Loading calendars, check back in a moment": "📅 Kalender werden geladen, gleich nochmal versuchen",
+
+		// The quit prompt
+		"1 event not yet synced — quit anyway? (y/n)":   "1 Termin noch nicht synchronisiert — trotzdem beenden? (y/n)",
+		"%d events not yet synced — quit anyway? (y/n)": "%d Termine noch nicht synchronisiert — trotzdem beenden? (y/n)",
+
+		// Small terminals
+		"d/w/m/g: views":                         "d/w/m/g: Ansichten",
+		"Terminal too small (%dx%d, need %dx%d)": "Terminal zu klein (%dx%d, mindestens %dx%d)",
+
+		// The event detail panel
+		"enter: details": "Enter: Details",
+		"j/k: scroll":    "j/k: blättern",
+		"esc: close":     "Esc: schließen",
+		"When":           "Wann",
+		"Where":          "Wo",
+		"Calendar":       "Kalender",
+		"Organizer":      "Organisator",
+		"Attendees":      "Teilnehmer",
+		"Join":           "Beitreten",
+		"URL":            "URL",
+		"o: open link":   "o: Link öffnen",
+
+		// Weekly view focus
+		"h/l: focus day":         "h/l: Tag wählen",
+		"enter: expand/open day": "Enter: Tag aufklappen/öffnen",
+
+		// Calendar groups
+		"G: groups":    "G: Gruppen",
+		"%s (hidden):": "%s (ausgeblendet):",
+		"Set a group on calendars in the config first": "Zuerst in der Konfiguration Gruppen für Kalender festlegen",
+		"Show/hide group:": "Gruppe ein-/ausblenden:",
+		"%d %s (hidden)":   "%d %s (ausgeblendet)",
+		"esc: done":        "Esc: fertig",
+
+		// Recurrence rules
+		"Repeats":          "Wiederholung",
+		"every minute":     "jede Minute",
+		"every %d minutes": "alle %d Minuten",
+		"every hour":       "jede Stunde",
+		"every %d hours":   "alle %d Stunden",
+		"every day":        "jeden Tag",
+		"every %d days":    "alle %d Tage",
+		"every week":       "jede Woche",
+		"every %d weeks":   "alle %d Wochen",
+		"every month":      "jeden Monat",
+		"every %d months":  "alle %d Monate",
+		"every year":       "jedes Jahr",
+		"every %d years":   "alle %d Jahre",
+		"on %s":            "am %s",
+		"on day %s":        "am %s.",
+		"in %s":            "im %s",
+		"once":             "einmal",
+		"%d times":         "%d-mal",
+		"the last %s":      "letzten %s",
+		"the %s %s":        "%s %s",
+		"first":            "ersten",
+		"second":           "zweiten",
+		"third":            "dritten",
+		"fourth":           "vierten",
+		"fifth":            "fünften",
+
+		// Pinned events
+		"Pinned %s":   "%s angeheftet",
+		"Unpinned %s": "%s nicht mehr angeheftet",
+		"*: pin":      "*: anheften",
+
+		// Planning tasks
+		"T: plan tasks":                 "T: Aufgaben planen",
+		"📋 Plan the day":                "📋 Tag planen",
+		"Free:":                         "Frei:",
+		"none":                          "nichts",
+		"Loading tasks...":              "Aufgaben werden geladen...",
+		"Failed to load tasks: %v":      "Aufgaben konnten nicht geladen werden: %v",
+		"No open tasks to plan":         "Keine offenen Aufgaben zu planen",
+		"due %s":                        "fällig %s",
+		"enter: plan in next free slot": "enter: in nächste freie Zeit planen",
+		"No free slot of %s left on %s": "Keine freie Zeit von %s mehr am %s",
+		"Planned %s %s–%s":              "%s geplant %s–%s",
+
+		// Meeting costs
+		"Cost":                   "Kosten",
+		"Meetings this week: %s": "Meetings diese Woche: %s",
+
+		// Zen screen
+		"f: zen":         "f: Zen",
+		"Free":           "Frei",
+		"Next: %s at %s": "Als Nächstes: %s um %s",
+		"Nothing scheduled for the rest of the day": "Für den Rest des Tages nichts geplant",
+
+		// Invitations read with --stdin
+		"Invitation":           "Einladung",
+		"📨 Invitation":         "📨 Einladung",
+		"Invitation dismissed": "Einladung verworfen",
+		"No writable calendar to accept the invitation into": "Kein beschreibbarer Kalender für die Einladung",
+		"On %s":             "Am %s",
+		"a: accept into %s": "a: in %s annehmen",
+		"tab: calendar":     "Tab: Kalender",
+		"esc: dismiss":      "Esc: verwerfen",
+
+		// Birthdays and anniversaries, "turns 42" and "10th anniversary"
+		"turns %d":       "wird %d",
+		"%s anniversary": "%s Jahrestag",
+		"st":             ".",
+		"nd":             ".",
+		"rd":             ".",
+		"th":             ".",
+
+		// Toggling calendars by number
+		"1-9: calendars": "1-9: Kalender",
+		"Showing %s":     "%s eingeblendet",
+		"Hiding %s":      "%s ausgeblendet",
+
+		// Exports with --export-md and --export-html
+		"Day":                 "Tag",
+		"Time":                "Zeit",
+		"Location":            "Ort",
+		"All day":             "Ganztägig",
+		"No events scheduled": "Keine Termine",
+
+		// The static site of --publish
+		"Coming up":  "Demnächst",
+		"Updated %s": "Aktualisiert %s",
+	},
+	nl: nlWords{
+		today:    []string{"heute"},
+		tomorrow: []string{"morgen"},
+		nextWeek: []string{"nächste woche"},
+		timeWords: map[string]int{
+			"morgens":     9,
+			"vormittags":  10,
+			"mittags":     12,
+			"nachmittags": 14,
+			"abends":      18,
+			"mitternacht": 0,
+		},
+		dayParts: map[string]string{
+			"den ganzen tag":        "all day",
+			"den ganzen nachmittag": "13:00-17:00",
+			"mittagessen":           "12:00-13:00",
+		},
+		hourUnits:   []string{"stunden", "stunde", "std"},
+		minuteUnits: []string{"minuten", "minute"},
+	},
+}
+
+// locales maps a language code to its translations
+var locales = map[string]*locale{
+	"en": englishLocale,
+	"de": germanLocale,
+}
+
+var currentLocale = englishLocale
+
+// setLocale selects the locale from a config value like "de_CH" or "de".
+// Unknown locales fall back to English.
+func setLocale(name string) {
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	if l, ok := locales[lang]; ok {
+		currentLocale = l
+	} else {
+		currentLocale = englishLocale
+	}
+}
+
+// tr translates a UI string, returning it unchanged if there is no translation
+func tr(s string) string {
+	if translated, ok := currentLocale.strings[s]; ok {
+		return translated
+	}
+	return s
+}
+
+var (
+	shortWeekdayPattern = regexp.MustCompile(`\b(Sun|Mon|Tue|Wed|Thu|Fri|Sat)\b`)
+	shortMonthPattern   = regexp.MustCompile(`\b(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\b`)
+)
+
+// formatDate formats t like time.Format and translates weekday and month names
+func formatDate(t time.Time, layout string) string {
+	out := t.Format(layout)
+	if currentLocale == englishLocale {
+		return out
+	}
+
+	// Full names first, so their abbreviations don't match inside them
+	out = strings.ReplaceAll(out, englishLocale.weekdays[t.Weekday()], currentLocale.weekdays[t.Weekday()])
+	out = strings.ReplaceAll(out, englishLocale.months[t.Month()-1], currentLocale.months[t.Month()-1])
+	out = shortWeekdayPattern.ReplaceAllStringFunc(out, func(s string) string {
+		return currentLocale.shortWeekdays[t.Weekday()]
+	})
+	out = shortMonthPattern.ReplaceAllStringFunc(out, func(s string) string {
+		return currentLocale.shortMonths[t.Month()-1]
+	})
+	return out
+}
+
+// weekdayHeaders returns abbreviated weekday names starting on Monday
+func weekdayHeaders() []string {
+	headers := make([]string, 0, 7)
+	for i := 1; i <= 7; i++ {
+		headers = append(headers, currentLocale.shortWeekdays[i%7])
+	}
+	return headers
+}
+
+// renderHelp renders a help bar from sections of key hints, translating each hint
+func renderHelp(sections ...[]string) string {
+	parts := make([]string, 0, len(sections))
+	for _, section := range sections {
+		items := make([]string, 0, len(section))
+		for _, item := range section {
+			items = append(items, tr(item))
+		}
+		parts = append(parts, strings.Join(items, "  "))
+	}
+	return helpStyle.Render(strings.Join(parts, "  |  "))
+}
+
+// nlAlternatives joins English and localized words into a regexp alternation
+func nlAlternatives(pick func(nlWords) []string) string {
+	words := pick(englishLocale.nl)
+	if currentLocale != englishLocale {
+		words = append(append([]string{}, words...), pick(currentLocale.nl)...)
+	}
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// nlWeekdays maps lowercase weekday names (English and localized) to weekdays
+func nlWeekdays() map[string]time.Weekday {
+	weekdays := make(map[string]time.Weekday)
+	for i := 0; i < 7; i++ {
+		weekdays[strings.ToLower(englishLocale.weekdays[i])] = time.Weekday(i)
+		weekdays[strings.ToLower(currentLocale.weekdays[i])] = time.Weekday(i)
+	}
+	return weekdays
+}
+
+// nlDayPartWords maps phrases for a part of the day (English and localized)
+// to their hours as written in the day_parts config
+func nlDayPartWords() map[string]string {
+	words := make(map[string]string)
+	for w, part := range englishLocale.nl.dayParts {
+		words[w] = part
+	}
+	for w, part := range currentLocale.nl.dayParts {
+		words[w] = part
+	}
+	return words
+}
+
+// nlTimeWords maps time-of-day words (English and localized) to an hour
+func nlTimeWords() map[string]int {
+	words := make(map[string]int)
+	for w, h := range englishLocale.nl.timeWords {
+		words[w] = h
+	}
+	for w, h := range currentLocale.nl.timeWords {
+		words[w] = h
+	}
+	return words
+}
@@ -0,0 +1,53 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// FetchAddressBook sends an addressbook-query REPORT (RFC 6352 8.6) for
+// all contacts of a CardDAV address book and returns their vCards
+func (c *Client) FetchAddressBook(addressBookURL string, onRetry RetryFunc) ([]string, error) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<C:addressbook-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:carddav">
+  <D:prop>
+    <C:address-data/>
+  </D:prop>
+</C:addressbook-query>`
+
+	req, err := http.NewRequest("REPORT", addressBookURL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.Do(req, onRetry)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("addressbook-query on %s failed: %s - %s", addressBookURL, resp.Status, respBody[:min(200, len(respBody))])
+	}
+
+	var ms multistatus
+	if err := xml.Unmarshal(respBody, &ms); err != nil {
+		return nil, fmt.Errorf("invalid multistatus response: %v", err)
+	}
+	var cards []string
+	for _, r := range ms.Response {
+		if p := okProp(r); p != nil && strings.TrimSpace(p.AddressData) != "" {
+			cards = append(cards, p.AddressData)
+		}
+	}
+	return cards, nil
+}
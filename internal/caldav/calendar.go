@@ -0,0 +1,130 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// FetchCalendar downloads a calendar collection and returns its iCalendar
+// documents: one for a whole-collection export, or one per resource when
+// the server answers with a multistatus
+func (c *Client) FetchCalendar(calendarURL string, onRetry RetryFunc) ([]string, error) {
+	// Radicale calendars can be accessed via .ics extension
+	// Try multiple URL formats
+	baseURL := strings.TrimSuffix(calendarURL, "/")
+	urlsToTry := []string{
+		baseURL + ".ics",     // Standard Radicale format
+		calendarURL + ".ics", // With trailing slash
+		baseURL,              // Without .ics
+		calendarURL,          // Original URL
+	}
+
+	var lastErr error
+	var lastStatus int
+
+	for _, url := range urlsToTry {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		req.Header.Set("Accept", "text/calendar")
+
+		resp, err := c.Do(req, onRetry)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		lastStatus = resp.StatusCode
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			// Check if it's actually calendar data (starts with BEGIN:VCALENDAR)
+			if strings.HasPrefix(strings.TrimSpace(string(body)), "BEGIN:VCALENDAR") {
+				return []string{string(body)}, nil
+			}
+			lastErr = fmt.Errorf("response is not calendar data (status: %d)", resp.StatusCode)
+		case resp.StatusCode == http.StatusMultiStatus:
+			return calendarData(body)
+		default:
+			// Try next URL
+			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, body[:min(200, len(body))])
+		}
+	}
+
+	return nil, fmt.Errorf("failed to load calendar from %s (tried %d URLs, last: %d - %v)",
+		calendarURL, len(urlsToTry), lastStatus, lastErr)
+}
+
+// calendarData extracts the calendar-data of each resource in a multistatus
+func calendarData(body []byte) ([]string, error) {
+	var ms multistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return nil, fmt.Errorf("invalid multistatus response: %v", err)
+	}
+
+	var docs []string
+	for _, r := range ms.Response {
+		if p := okProp(r); p != nil && strings.TrimSpace(p.CalendarData) != "" {
+			docs = append(docs, p.CalendarData)
+		}
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no calendar-data found in multistatus response")
+	}
+	return docs, nil
+}
+
+// Put creates or replaces the event resource <uid>.ics in a calendar
+func (c *Client) Put(calendarURL string, uid string, icsContent string) error {
+	eventURL := calendarURL + "/" + uid + ".ics"
+
+	req, err := http.NewRequest("PUT", eventURL, strings.NewReader(icsContent))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+
+	resp, err := c.Do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create event: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// Delete removes the event resource <uid>.ics from a calendar
+func (c *Client) Delete(calendarURL string, uid string) error {
+	eventURL := calendarURL + "/" + uid + ".ics"
+
+	req, err := http.NewRequest("DELETE", eventURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 404 means the event is already gone, which is what we wanted
+	if resp.StatusCode != 200 && resp.StatusCode != 204 && resp.StatusCode != 404 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete event: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
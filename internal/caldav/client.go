@@ -0,0 +1,96 @@
+// Package caldav is a small CalDAV client: calendar discovery, fetching
+// calendar data and writing single events, with retries for transient
+// server failures. It also reads CardDAV address books.
+package caldav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+const retryBaseDelay = 500 * time.Millisecond
+
+// RetryFunc is told about each retry: the attempt about to be made and the
+// error that caused it
+type RetryFunc func(attempt int, err error)
+
+// Client talks to a CalDAV server. Username may be empty for servers (or
+// public ICS feeds) that need no authentication.
+type Client struct {
+	HTTP        *http.Client
+	Username    string
+	Password    string
+	MaxAttempts int               // Tries per request on server errors and timeouts
+	Header      map[string]string // Set on every request, e.g. User-Agent or Cookie
+}
+
+// Do sends req, retrying server errors (5xx, 429) and timeouts with
+// exponential backoff and jitter. Other failures, such as a refused
+// connection, are returned immediately. onRetry may be nil.
+func (c *Client) Do(req *http.Request, onRetry RetryFunc) (*http.Response, error) {
+	return c.do(c.HTTP, req, onRetry)
+}
+
+func (c *Client) do(client *http.Client, req *http.Request, onRetry RetryFunc) (*http.Response, error) {
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	for name, value := range c.Header {
+		req.Header.Set(name, value)
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := client.Do(attemptReq)
+
+		var retryErr error
+		switch {
+		case err != nil && isTimeout(err):
+			retryErr = err
+		case err != nil:
+			return nil, err
+		case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+			retryErr = fmt.Errorf("server error: %s", resp.Status)
+		default:
+			return resp, nil
+		}
+
+		if attempt >= c.MaxAttempts {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if onRetry != nil {
+			onRetry(attempt+1, retryErr)
+		}
+		time.Sleep(retryDelay(attempt))
+	}
+}
+
+// retryDelay doubles the delay for each attempt and adds up to 50% jitter
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,208 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// CalDAV service discovery (RFC 6764, RFC 4791): find the user's principal,
+// then its calendar-home-set, then the calendar collections inside it.
+
+const (
+	propfindPrincipalBody = `<?xml version="1.0" encoding="UTF-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:current-user-principal/></d:prop></d:propfind>`
+
+	propfindHomeSetBody = `<?xml version="1.0" encoding="UTF-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><c:calendar-home-set/></d:prop></d:propfind>`
+
+	propfindCalendarsBody = `<?xml version="1.0" encoding="UTF-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:a="http://apple.com/ns/ical/" xmlns:cs="http://calendarserver.org/ns/"><d:prop><d:displayname/><d:resourcetype/><a:calendar-color/><cs:getctag/></d:prop></d:propfind>`
+
+	propfindCTagBody = `<?xml version="1.0" encoding="UTF-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/"><d:prop><cs:getctag/></d:prop></d:propfind>`
+
+	maxDiscoveryRedirects = 5
+)
+
+// Calendar is a calendar collection found on the server
+type Calendar struct {
+	DisplayName string
+	URL         string
+	Color       string // calendar-color like "#FF2968FF", if set
+	CTag        string // getctag, which changes with any change to the calendar, if supported
+}
+
+// Discover finds the user's calendars on the server
+func (c *Client) Discover(server string, onRetry RetryFunc) ([]Calendar, error) {
+	serverURL, err := url.Parse(strings.TrimSuffix(server, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %v", err)
+	}
+
+	principalURL, err := c.findPrincipal(serverURL, onRetry)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, base, err := c.propfind(principalURL, "0", propfindHomeSetBody, onRetry)
+	if err != nil {
+		return nil, err
+	}
+	var homeURL *url.URL
+	for _, r := range responses {
+		if p := okProp(r); p != nil && p.CalendarHomeSet.Href != "" {
+			homeURL, err = base.Parse(strings.TrimSpace(p.CalendarHomeSet.Href))
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if homeURL == nil {
+		return nil, fmt.Errorf("no calendar-home-set for principal %s", principalURL)
+	}
+
+	responses, base, err = c.propfind(homeURL, "1", propfindCalendarsBody, onRetry)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []Calendar
+	for _, r := range responses {
+		p := okProp(r)
+		if p == nil || p.ResourceType.Calendar == nil {
+			continue
+		}
+
+		calURL, err := base.Parse(strings.TrimSpace(r.Href))
+		if err != nil {
+			continue
+		}
+
+		calName := p.DisplayName
+		if calName == "" {
+			calName = path.Base(strings.TrimSuffix(calURL.Path, "/"))
+		}
+
+		calendars = append(calendars, Calendar{
+			DisplayName: calName,
+			URL:         strings.TrimSuffix(calURL.String(), "/"),
+			Color:       strings.TrimSpace(p.CalendarColor),
+			CTag:        strings.TrimSpace(p.CTag),
+		})
+	}
+
+	if len(calendars) == 0 {
+		return nil, fmt.Errorf("no calendars found in %s", homeURL)
+	}
+	return calendars, nil
+}
+
+// CTag returns the calendar's getctag, which changes whenever anything in
+// it changes, or "" if the server doesn't support it
+func (c *Client) CTag(calendarURL string, onRetry RetryFunc) (string, error) {
+	target, err := url.Parse(strings.TrimSuffix(calendarURL, "/") + "/")
+	if err != nil {
+		return "", err
+	}
+	responses, _, err := c.propfind(target, "0", propfindCTagBody, onRetry)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range responses {
+		if p := okProp(r); p != nil && p.CTag != "" {
+			return strings.TrimSpace(p.CTag), nil
+		}
+	}
+	return "", nil
+}
+
+// findPrincipal asks for the current-user-principal at the configured server
+// URL, falling back to the /.well-known/caldav bootstrap URL
+func (c *Client) findPrincipal(serverURL *url.URL, onRetry RetryFunc) (*url.URL, error) {
+	wellKnown, _ := serverURL.Parse("/.well-known/caldav")
+
+	var lastErr error
+	for _, target := range []*url.URL{serverURL, wellKnown} {
+		responses, base, err := c.propfind(target, "0", propfindPrincipalBody, onRetry)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, r := range responses {
+			if p := okProp(r); p != nil && p.CurrentUserPrincipal.Href != "" {
+				return base.Parse(strings.TrimSpace(p.CurrentUserPrincipal.Href))
+			}
+		}
+		lastErr = fmt.Errorf("no current-user-principal at %s", target)
+	}
+	return nil, lastErr
+}
+
+// propfind sends a PROPFIND and returns the multistatus responses along with
+// the URL that answered, which hrefs are relative to. Redirects are followed
+// by hand since http.Client would turn them into GETs.
+func (c *Client) propfind(target *url.URL, depth string, body string, onRetry RetryFunc) ([]response, *url.URL, error) {
+	client := *c.HTTP
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	for i := 0; i <= maxDiscoveryRedirects; i++ {
+		req, err := http.NewRequest("PROPFIND", target.String(), strings.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+		req.Header.Set("Depth", depth)
+
+		resp, err := c.do(&client, req, onRetry)
+		if err != nil {
+			return nil, nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+			next, err := target.Parse(resp.Header.Get("Location"))
+			if err != nil {
+				return nil, nil, err
+			}
+			target = next
+			continue
+		case http.StatusMultiStatus:
+			var ms multistatus
+			if err := xml.Unmarshal(respBody, &ms); err != nil {
+				return nil, nil, fmt.Errorf("invalid PROPFIND response from %s: %v", target, err)
+			}
+			return ms.Response, target, nil
+		}
+
+		bodyStr := string(respBody)
+		if len(bodyStr) > 500 {
+			bodyStr = bodyStr[:500] + "..."
+		}
+		return nil, nil, fmt.Errorf("PROPFIND %s failed (status %d): %s", target, resp.StatusCode, bodyStr)
+	}
+	return nil, nil, fmt.Errorf("too many redirects discovering calendars at %s", target)
+}
+
+// okProp returns the properties of the successful propstat, if any
+func okProp(r response) *prop {
+	for i := range r.Propstat {
+		if strings.Contains(r.Propstat[i].Status, "200") {
+			return &r.Propstat[i].Prop
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package caldav
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FreeBusy sends a free-busy-query REPORT (RFC 4791 7.10) for [from, to)
+// to a calendar and returns the VFREEBUSY document the server answers with.
+// It needs the CALDAV:read-free-busy privilege on the calendar, not read
+// access to the events themselves.
+func (c *Client) FreeBusy(calendarURL string, from, to time.Time) (string, error) {
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
+<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:time-range start="%s" end="%s"/>
+</C:free-busy-query>`, from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z"))
+
+	req, err := http.NewRequest("REPORT", calendarURL, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.Do(req, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("free-busy-query on %s failed: %s - %s", calendarURL, resp.Status, respBody[:min(200, len(respBody))])
+	}
+	if !strings.HasPrefix(strings.TrimSpace(string(respBody)), "BEGIN:VCALENDAR") {
+		return "", fmt.Errorf("free-busy-query on %s: response is not calendar data", calendarURL)
+	}
+	return string(respBody), nil
+}
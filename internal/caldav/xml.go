@@ -0,0 +1,41 @@
+package caldav
+
+import "encoding/xml"
+
+// WebDAV/CalDAV/CardDAV XML structures
+type prop struct {
+	DisplayName          string       `xml:"DAV: displayname"`
+	CalendarDescription  string       `xml:"urn:ietf:params:xml:ns:caldav calendar-description"`
+	CalendarColor        string       `xml:"http://apple.com/ns/ical/ calendar-color"`
+	CTag                 string       `xml:"http://calendarserver.org/ns/ getctag"`
+	CurrentUserPrincipal hrefProp     `xml:"DAV: current-user-principal"`
+	CalendarHomeSet      hrefProp     `xml:"urn:ietf:params:xml:ns:caldav calendar-home-set"`
+	ResourceType         resourceType `xml:"DAV: resourcetype"`
+	CalendarData         string       `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+	AddressData          string       `xml:"urn:ietf:params:xml:ns:carddav address-data"`
+}
+
+// hrefProp is a property whose value is a single DAV:href
+type hrefProp struct {
+	Href string `xml:"DAV: href"`
+}
+
+type resourceType struct {
+	Collection *struct{} `xml:"DAV: collection"`
+	Calendar   *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+}
+
+type multistatus struct {
+	XMLName  xml.Name   `xml:"DAV: multistatus"`
+	Response []response `xml:"DAV: response"`
+}
+
+type response struct {
+	Href     string     `xml:"DAV: href"`
+	Propstat []propstat `xml:"DAV: propstat"`
+}
+
+type propstat struct {
+	Status string `xml:"DAV: status"`
+	Prop   prop   `xml:"DAV: prop"`
+}
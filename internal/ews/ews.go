@@ -0,0 +1,254 @@
+// Package ews reads calendars of on-premises Exchange servers through
+// Exchange Web Services (SOAP), for servers without the Graph API.
+package ews
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxItems is the most occurrences requested in one calendar view
+const maxItems = 1000
+
+// getItemBatch is how many items are fetched per GetItem request
+const getItemBatch = 100
+
+// Client calls an EWS endpoint like https://mail.example.com/EWS/Exchange.asmx.
+// Username is "user", "DOMAIN\user" or "user@domain".
+type Client struct {
+	HTTP     *http.Client
+	URL      string
+	Username string
+	Password string
+	NTLM     bool // Authenticate with NTLM instead of basic auth
+}
+
+// Mailbox is an organizer or attendee
+type Mailbox struct {
+	EmailAddress string `xml:"Mailbox>EmailAddress"`
+}
+
+// CalendarItem is an occurrence of a calendar item. FindItem with a
+// calendar view expands recurring items, so each has its own start and end.
+type CalendarItem struct {
+	ItemID struct {
+		ID string `xml:"Id,attr"`
+	} `xml:"ItemId"`
+	UID                  string    `xml:"UID"`
+	Subject              string    `xml:"Subject"`
+	Body                 string    `xml:"Body"`
+	Location             string    `xml:"Location"`
+	Start                time.Time `xml:"Start"`
+	End                  time.Time `xml:"End"`
+	IsAllDayEvent        bool      `xml:"IsAllDayEvent"`
+	IsCancelled          bool      `xml:"IsCancelled"`
+	LegacyFreeBusyStatus string    `xml:"LegacyFreeBusyStatus"` // "Free", "Tentative", "Busy", "OOF", ...
+	Organizer            Mailbox   `xml:"Organizer"`
+	RequiredAttendees    []Mailbox `xml:"RequiredAttendees>Attendee"`
+	OptionalAttendees    []Mailbox `xml:"OptionalAttendees>Attendee"`
+	LastModifiedTime     time.Time `xml:"LastModifiedTime"`
+}
+
+// responseMessage is the status part of every EWS response message
+type responseMessage struct {
+	ResponseClass string `xml:"ResponseClass,attr"` // "Success", "Warning" or "Error"
+	ResponseCode  string `xml:"ResponseCode"`
+	MessageText   string `xml:"MessageText"`
+}
+
+func (r responseMessage) err() error {
+	if r.ResponseClass == "Error" {
+		return fmt.Errorf("EWS: %s: %s", r.ResponseCode, r.MessageText)
+	}
+	return nil
+}
+
+// Events returns the occurrences in the user's calendar that overlap
+// [from, to), with body and attendees
+func (c *Client) Events(from, to time.Time) ([]CalendarItem, error) {
+	ids, err := c.findItems(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []CalendarItem
+	for len(ids) > 0 {
+		batch := ids[:min(getItemBatch, len(ids))]
+		ids = ids[len(batch):]
+		fetched, err := c.getItems(batch)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, fetched...)
+	}
+	return items, nil
+}
+
+// findItems lists the item IDs of the occurrences in [from, to). FindItem
+// can only return summary properties, the rest is read with GetItem.
+func (c *Client) findItems(from, to time.Time) ([]string, error) {
+	body := fmt.Sprintf(`<m:FindItem Traversal="Shallow">
+      <m:ItemShape><t:BaseShape>IdOnly</t:BaseShape></m:ItemShape>
+      <m:CalendarView MaxEntriesReturned="%d" StartDate="%s" EndDate="%s"/>
+      <m:ParentFolderIds><t:DistinguishedFolderId Id="calendar"/></m:ParentFolderIds>
+    </m:FindItem>`, maxItems, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+
+	var resp struct {
+		Message struct {
+			responseMessage
+			Items []CalendarItem `xml:"RootFolder>Items>CalendarItem"`
+		} `xml:"Body>FindItemResponse>ResponseMessages>FindItemResponseMessage"`
+	}
+	if err := c.call(body, &resp); err != nil {
+		return nil, err
+	}
+	// ErrorCalendarViewRangeTooBig and the like come back as errors here
+	if err := resp.Message.err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(resp.Message.Items))
+	for _, item := range resp.Message.Items {
+		ids = append(ids, item.ItemID.ID)
+	}
+	return ids, nil
+}
+
+// getItems reads the calendar items with the given IDs
+func (c *Client) getItems(ids []string) ([]CalendarItem, error) {
+	var itemIDs strings.Builder
+	for _, id := range ids {
+		itemIDs.WriteString(`<t:ItemId Id="`)
+		xml.EscapeText(&itemIDs, []byte(id))
+		itemIDs.WriteString(`"/>`)
+	}
+	body := `<m:GetItem>
+      <m:ItemShape>
+        <t:BaseShape>Default</t:BaseShape>
+        <t:BodyType>Text</t:BodyType>
+        <t:AdditionalProperties>
+          <t:FieldURI FieldURI="item:Body"/>
+          <t:FieldURI FieldURI="item:LastModifiedTime"/>
+          <t:FieldURI FieldURI="calendar:UID"/>
+          <t:FieldURI FieldURI="calendar:IsAllDayEvent"/>
+          <t:FieldURI FieldURI="calendar:IsCancelled"/>
+          <t:FieldURI FieldURI="calendar:LegacyFreeBusyStatus"/>
+          <t:FieldURI FieldURI="calendar:RequiredAttendees"/>
+          <t:FieldURI FieldURI="calendar:OptionalAttendees"/>
+        </t:AdditionalProperties>
+      </m:ItemShape>
+      <m:ItemIds>` + itemIDs.String() + `</m:ItemIds>
+    </m:GetItem>`
+
+	var resp struct {
+		Messages []struct {
+			responseMessage
+			Item CalendarItem `xml:"Items>CalendarItem"`
+		} `xml:"Body>GetItemResponse>ResponseMessages>GetItemResponseMessage"`
+	}
+	if err := c.call(body, &resp); err != nil {
+		return nil, err
+	}
+
+	var items []CalendarItem
+	for _, message := range resp.Messages {
+		// An item deleted since FindItem is skipped, not an error
+		if message.err() != nil {
+			continue
+		}
+		items = append(items, message.Item)
+	}
+	return items, nil
+}
+
+// call wraps body in a SOAP envelope, posts it and decodes the answer
+func (c *Client) call(body string, v any) error {
+	envelope := `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+    xmlns:t="http://schemas.microsoft.com/exchange/services/2006/types"
+    xmlns:m="http://schemas.microsoft.com/exchange/services/2006/messages">
+  <soap:Header><t:RequestServerVersion Version="Exchange2010_SP2"/></soap:Header>
+  <soap:Body>
+    ` + body + `
+  </soap:Body>
+</soap:Envelope>`
+
+	resp, err := c.post([]byte(envelope))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		var fault struct {
+			Message string `xml:"Body>Fault>faultstring"`
+		}
+		if xml.Unmarshal(data, &fault) == nil && fault.Message != "" {
+			return fmt.Errorf("EWS: %s - %s", resp.Status, fault.Message)
+		}
+		return fmt.Errorf("EWS: %s", resp.Status)
+	}
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// post sends a SOAP request, authenticating with basic auth or an NTLM
+// handshake
+func (c *Client) post(envelope []byte) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", c.URL, bytes.NewReader(envelope))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	if !c.NTLM {
+		req.SetBasicAuth(c.Username, c.Password)
+		return c.HTTP.Do(req)
+	}
+
+	// NTLM authenticates the connection: the challenge has to be answered
+	// on the connection it came from, which the transport reuses once the
+	// first response is read to the end
+	req.Header.Set("Authorization", "NTLM "+negotiateMessage())
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	challenge := ""
+	for _, header := range resp.Header.Values("WWW-Authenticate") {
+		if strings.HasPrefix(header, "NTLM ") {
+			challenge = strings.TrimPrefix(header, "NTLM ")
+		}
+	}
+	if challenge == "" {
+		return nil, fmt.Errorf("EWS: the server doesn't offer NTLM authentication")
+	}
+	authenticate, err := authenticateMessage(challenge, c.Username, c.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if req, err = newRequest(); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "NTLM "+authenticate)
+	return c.HTTP.Do(req)
+}
@@ -0,0 +1,183 @@
+package ews
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// NTLM flags (MS-NLMP 2.2.2.5) this client negotiates
+const (
+	ntlmUnicode          = 0x00000001
+	ntlmRequestTarget    = 0x00000004
+	ntlmNTLM             = 0x00000200
+	ntlmAlwaysSign       = 0x00008000
+	ntlmExtendedSecurity = 0x00080000
+	ntlmTargetInfo       = 0x00800000
+	ntlm128              = 0x20000000
+	ntlm56               = 0x80000000
+
+	ntlmFlags = ntlmUnicode | ntlmRequestTarget | ntlmNTLM | ntlmAlwaysSign |
+		ntlmExtendedSecurity | ntlmTargetInfo | ntlm128 | ntlm56
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// negotiateMessage is the first message of the handshake, base64 encoded
+func negotiateMessage() string {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmFlags)
+	// Empty domain and workstation fields
+	return base64.StdEncoding.EncodeToString(msg)
+}
+
+// authenticateMessage answers the server's base64 challenge message with an
+// NTLMv2 response (MS-NLMP 3.3.2)
+func authenticateMessage(challenge, username, password string) (string, error) {
+	msg, err := base64.StdEncoding.DecodeString(challenge)
+	if err != nil || len(msg) < 48 || string(msg[:8]) != string(ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return "", fmt.Errorf("EWS: invalid NTLM challenge")
+	}
+	flags := binary.LittleEndian.Uint32(msg[20:]) & ntlmFlags
+	serverChallenge := msg[24:32]
+	targetInfo, ok := securityBuffer(msg, 40)
+	if !ok {
+		return "", fmt.Errorf("EWS: invalid NTLM challenge")
+	}
+
+	domain, user := "", username
+	if i := strings.Index(username, `\`); i >= 0 {
+		domain, user = username[:i], username[i+1:]
+	}
+
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return "", err
+	}
+	// Windows FILETIME: 100ns intervals since 1601
+	timestamp := uint64(time.Now().UnixNano()/100) + 116444736000000000
+
+	lmResponse, ntResponse := ntlmv2Responses(ntowfv2(user, domain, password), serverChallenge, clientChallenge, timestamp, targetInfo)
+
+	fields := [][]byte{lmResponse, ntResponse, utf16le(domain), utf16le(user), nil, nil}
+	out := make([]byte, 64)
+	copy(out, ntlmSignature)
+	binary.LittleEndian.PutUint32(out[8:], 3)
+	for i, field := range fields {
+		header := out[12+8*i:]
+		binary.LittleEndian.PutUint16(header, uint16(len(field)))
+		binary.LittleEndian.PutUint16(header[2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(header[4:], uint32(len(out)))
+		out = append(out, field...)
+	}
+	binary.LittleEndian.PutUint32(out[60:], flags)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// ntowfv2 is the NTLMv2 response key of a user (MS-NLMP 3.3.2)
+func ntowfv2(user, domain, password string) []byte {
+	hash := md4Sum(utf16le(password))
+	return hmacMD5(hash[:], utf16le(strings.ToUpper(user)+domain))
+}
+
+// ntlmv2Responses computes the LMv2 and NTLMv2 responses to a server
+// challenge (MS-NLMP 3.3.2)
+func ntlmv2Responses(ntowf, serverChallenge, clientChallenge []byte, timestamp uint64, targetInfo []byte) (lmResponse, ntResponse []byte) {
+	temp := make([]byte, 0, 32+len(targetInfo))
+	temp = append(temp, 1, 1, 0, 0, 0, 0, 0, 0)
+	temp = binary.LittleEndian.AppendUint64(temp, timestamp)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+
+	ntProof := hmacMD5(ntowf, append(append([]byte{}, serverChallenge...), temp...))
+	ntResponse = append(ntProof, temp...)
+	lmResponse = append(hmacMD5(ntowf, append(append([]byte{}, serverChallenge...), clientChallenge...)), clientChallenge...)
+	return lmResponse, ntResponse
+}
+
+// securityBuffer returns the payload a length/offset field at pos refers to
+func securityBuffer(msg []byte, pos int) ([]byte, bool) {
+	length := int(binary.LittleEndian.Uint16(msg[pos:]))
+	offset := int(binary.LittleEndian.Uint32(msg[pos+4:]))
+	if offset+length > len(msg) {
+		return nil, false
+	}
+	return msg[offset : offset+length], true
+}
+
+func utf16le(s string) []byte {
+	var b []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, r)
+	}
+	return b
+}
+
+func hmacMD5(key, data []byte) []byte {
+	mac := hmac.New(md5.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// md4Sum is MD4 (RFC 1320), which NTLM uses to hash the password and which
+// the standard library doesn't have
+func md4Sum(data []byte) [16]byte {
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+
+	msg := append([]byte{}, data...)
+	msg = append(msg, 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, uint64(len(data))*8)
+
+	f := func(x, y, z uint32) uint32 { return x&y | ^x&z }
+	g := func(x, y, z uint32) uint32 { return x&y | x&z | y&z }
+	h := func(x, y, z uint32) uint32 { return x ^ y ^ z }
+
+	for chunk := 0; chunk < len(msg); chunk += 64 {
+		var x [16]uint32
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[chunk+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		shifts1 := [4]int{3, 7, 11, 19}
+		for i := 0; i < 16; i++ {
+			v := a + f(b, c, d) + x[i]
+			a, b, c, d = d, bits.RotateLeft32(v, shifts1[i%4]), b, c
+		}
+		shifts2 := [4]int{3, 5, 9, 13}
+		order2 := [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+		for i := 0; i < 16; i++ {
+			v := a + g(b, c, d) + x[order2[i]] + 0x5a827999
+			a, b, c, d = d, bits.RotateLeft32(v, shifts2[i%4]), b, c
+		}
+		shifts3 := [4]int{3, 9, 11, 15}
+		order3 := [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+		for i := 0; i < 16; i++ {
+			v := a + h(b, c, d) + x[order3[i]] + 0x6ed9eba1
+			a, b, c, d = d, bits.RotateLeft32(v, shifts3[i%4]), b, c
+		}
+
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], b)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
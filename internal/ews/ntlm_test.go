@@ -0,0 +1,115 @@
+package ews
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The test suite of RFC 1320, appendix A.5
+func TestMD4(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "043f8582f241db351ce627e153e7f0e4"},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "e33b4ddc9c38f2199c3e7b164fcc0536"},
+	}
+	for _, test := range tests {
+		sum := md4Sum([]byte(test.input))
+		if got := hex.EncodeToString(sum[:]); got != test.want {
+			t.Errorf("md4Sum(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+// The NTLMv2 example of MS-NLMP 4.2.4, with its common values from 4.2.1
+func TestNTLMv2Example(t *testing.T) {
+	// NTOWFv1 of 4.2.2.1.2, the MD4 of the password
+	hash := md4Sum(utf16le("Password"))
+	if want := unhex(t, "a4 f4 9c 40 65 10 bd ca b6 82 4e e7 c3 0f d8 52"); !bytes.Equal(hash[:], want) {
+		t.Errorf("NTOWFv1 = % x, want % x", hash, want)
+	}
+
+	ntowf := ntowfv2("User", "Domain", "Password")
+	if want := unhex(t, "0c 86 8a 40 3b fd 7a 93 a3 00 1e f2 2e f0 2e 3f"); !bytes.Equal(ntowf, want) {
+		t.Errorf("NTOWFv2 = % x, want % x", ntowf, want)
+	}
+
+	serverChallenge := unhex(t, "01 23 45 67 89 ab cd ef")
+	clientChallenge := unhex(t, "aa aa aa aa aa aa aa aa")
+	// MsvAvNbDomainName "Domain", MsvAvNbComputerName "Server", MsvAvEOL
+	targetInfo := unhex(t, "02 00 0c 00 44 00 6f 00 6d 00 61 00 69 00 6e 00"+
+		"01 00 0c 00 53 00 65 00 72 00 76 00 65 00 72 00"+
+		"00 00 00 00")
+	lmResponse, ntResponse := ntlmv2Responses(ntowf, serverChallenge, clientChallenge, 0, targetInfo)
+
+	if want := unhex(t, "86 c3 50 97 ac 9c ec 10 25 54 76 4a 57 cc cc 19 aa aa aa aa aa aa aa aa"); !bytes.Equal(lmResponse, want) {
+		t.Errorf("LMv2 response = % x, want % x", lmResponse, want)
+	}
+	if want := unhex(t, "68 cd 0a b8 51 e5 1c 96 aa bc 92 7b eb ef 6a 1c"); !bytes.Equal(ntResponse[:16], want) {
+		t.Errorf("NTProofStr = % x, want % x", ntResponse[:16], want)
+	}
+	temp := unhex(t, "01 01 00 00 00 00 00 00 00 00 00 00 00 00 00 00 aa aa aa aa aa aa aa aa 00 00 00 00")
+	temp = append(append(temp, targetInfo...), 0, 0, 0, 0)
+	if !bytes.Equal(ntResponse[16:], temp) {
+		t.Errorf("NTLMv2 response temp = % x, want % x", ntResponse[16:], temp)
+	}
+}
+
+func TestAuthenticateMessage(t *testing.T) {
+	targetInfo := []byte{0, 0, 0, 0}
+	challenge := make([]byte, 48)
+	copy(challenge, ntlmSignature)
+	binary.LittleEndian.PutUint32(challenge[8:], 2)
+	binary.LittleEndian.PutUint32(challenge[20:], ntlmFlags)
+	copy(challenge[24:], "\x01\x23\x45\x67\x89\xab\xcd\xef")
+	binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(challenge[44:], uint32(len(challenge)))
+	challenge = append(challenge, targetInfo...)
+
+	encoded, err := authenticateMessage(base64.StdEncoding.EncodeToString(challenge), `Domain\User`, "Password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(msg[:8]) != string(ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 3 {
+		t.Fatalf("not an AUTHENTICATE message: % x", msg[:12])
+	}
+	for i, want := range [][]byte{nil, nil, utf16le("Domain"), utf16le("User")} {
+		field, ok := securityBuffer(msg, 12+8*i)
+		if !ok {
+			t.Fatalf("field %d out of bounds", i)
+		}
+		if want != nil && !bytes.Equal(field, want) {
+			t.Errorf("field %d = % x, want % x", i, field, want)
+		}
+	}
+	if lm, _ := securityBuffer(msg, 12); len(lm) != 24 {
+		t.Errorf("LMv2 response of %d bytes, want 24", len(lm))
+	}
+
+	if _, err := authenticateMessage("bm90IG50bG0=", "User", "Password"); err == nil {
+		t.Error("accepted a challenge that isn't NTLM")
+	}
+}
@@ -0,0 +1,158 @@
+// Package google reads Google Calendar through its REST API, authorizing
+// with the OAuth device flow.
+package google
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"mytuiapp/internal/oauth"
+)
+
+const apiURL = "https://www.googleapis.com/calendar/v3"
+
+// OAuth returns the device flow client for read-only calendar access. The
+// client must be of type "TVs and Limited Input devices".
+func OAuth(client *http.Client, clientID, clientSecret string) *oauth.Client {
+	return &oauth.Client{
+		HTTP:         client,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        "https://www.googleapis.com/auth/calendar.readonly",
+		DeviceURL:    "https://oauth2.googleapis.com/device/code",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+	}
+}
+
+// Client calls the Calendar API with an access token
+type Client struct {
+	HTTP  *http.Client
+	Token *oauth.Token
+}
+
+// Calendar is an entry of the user's calendar list
+type Calendar struct {
+	ID              string `json:"id"`
+	Summary         string `json:"summary"`
+	BackgroundColor string `json:"backgroundColor"`
+	Primary         bool   `json:"primary"`
+}
+
+// EventTime is a start or end: DateTime for timed events, Date
+// ("2006-01-02") for all-day events
+type EventTime struct {
+	DateTime string `json:"dateTime"`
+	Date     string `json:"date"`
+	TimeZone string `json:"timeZone"`
+}
+
+// Time returns the time in loc; all-day dates start at midnight in loc
+func (t EventTime) Time(loc *time.Location) (time.Time, error) {
+	if t.DateTime != "" {
+		parsed, err := time.Parse(time.RFC3339, t.DateTime)
+		return parsed.In(loc), err
+	}
+	return time.ParseInLocation("2006-01-02", t.Date, loc)
+}
+
+// Person is an organizer or attendee
+type Person struct {
+	Email string `json:"email"`
+}
+
+// Event is a Calendar API event. Recurring events are requested as single
+// occurrences, so every Event has its own start and end.
+type Event struct {
+	ID           string    `json:"id"`
+	ICalUID      string    `json:"iCalUID"`
+	Status       string    `json:"status"` // "confirmed", "tentative" or "cancelled"
+	Summary      string    `json:"summary"`
+	Description  string    `json:"description"`
+	Location     string    `json:"location"`
+	Start        EventTime `json:"start"`
+	End          EventTime `json:"end"`
+	Transparency string    `json:"transparency"` // "opaque" (default) or "transparent"
+	Organizer    Person    `json:"organizer"`
+	Attendees    []Person  `json:"attendees"`
+	Updated      time.Time `json:"updated"`
+}
+
+// Calendars lists the calendars of the signed-in user
+func (c *Client) Calendars() ([]Calendar, error) {
+	var calendars []Calendar
+	pageToken := ""
+	for {
+		query := url.Values{}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Items         []Calendar `json:"items"`
+			NextPageToken string     `json:"nextPageToken"`
+		}
+		if err := c.get(apiURL+"/users/me/calendarList?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, page.Items...)
+		if page.NextPageToken == "" {
+			return calendars, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// Events returns the occurrences of a calendar's events that overlap
+// [from, to), with recurring events expanded by the server
+func (c *Client) Events(calendarID string, from, to time.Time) ([]Event, error) {
+	var events []Event
+	pageToken := ""
+	for {
+		query := url.Values{
+			"singleEvents": {"true"},
+			"orderBy":      {"startTime"},
+			"timeMin":      {from.UTC().Format(time.RFC3339)},
+			"timeMax":      {to.UTC().Format(time.RFC3339)},
+			"maxResults":   {"2500"},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Items         []Event `json:"items"`
+			NextPageToken string  `json:"nextPageToken"`
+		}
+		endpoint := fmt.Sprintf("%s/calendars/%s/events?%s", apiURL, url.PathEscape(calendarID), query.Encode())
+		if err := c.get(endpoint, &page); err != nil {
+			return nil, err
+		}
+		events = append(events, page.Items...)
+		if page.NextPageToken == "" {
+			return events, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (c *Client) get(endpoint string, v any) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token.AccessToken)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Google Calendar API: %s - %s", resp.Status, body[:min(200, len(body))])
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
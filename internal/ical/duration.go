@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is an iCalendar DURATION value. Weeks and days are nominal, so
+// "P1D" across a DST change still ends at the same wall-clock time.
+type Duration struct {
+	Negative bool
+	Days     int // Weeks are stored as 7 days
+	Time     time.Duration
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseDuration parses an RFC 5545 duration such as "PT1H30M", "P1W" or
+// "-P2D"
+func ParseDuration(s string) (Duration, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	match := durationPattern.FindStringSubmatch(s)
+	if match == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return Duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	number := func(i int) int {
+		n, _ := strconv.Atoi(match[i])
+		return n
+	}
+	return Duration{
+		Negative: match[1] == "-",
+		Days:     number(2)*7 + number(3),
+		Time: time.Duration(number(4))*time.Hour +
+			time.Duration(number(5))*time.Minute +
+			time.Duration(number(6))*time.Second,
+	}, nil
+}
+
+// AddTo returns t moved by the duration
+func (d Duration) AddTo(t time.Time) time.Time {
+	if d.Negative {
+		return t.AddDate(0, 0, -d.Days).Add(-d.Time)
+	}
+	return t.AddDate(0, 0, d.Days).Add(d.Time)
+}
+
+// FormatDuration formats the span between start and end as a duration,
+// using days (or weeks) when the wall-clock time is the same
+func FormatDuration(start, end time.Time) string {
+	sign := ""
+	if end.Before(start) {
+		sign = "-"
+		start, end = end, start
+	}
+
+	days := 0
+	for !start.AddDate(0, 0, days+1).After(end) {
+		days++
+	}
+	rest := end.Sub(start.AddDate(0, 0, days))
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days > 0 && days%7 == 0 && rest == 0 {
+		fmt.Fprintf(&b, "%dW", days/7)
+		return b.String()
+	}
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if rest > 0 || days == 0 {
+		b.WriteString("T")
+		hours, minutes, seconds := int(rest.Hours()), int(rest.Minutes())%60, int(rest.Seconds())%60
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 || (hours == 0 && minutes == 0) {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+	return b.String()
+}
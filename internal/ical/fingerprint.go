@@ -0,0 +1,60 @@
+package ical
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Fingerprint identifies the content of an event, to tell whether two
+// copies of it differ
+type Fingerprint struct {
+	Summary string
+	Hash    string // Of the series and its overrides
+}
+
+// Fingerprints returns the fingerprint of each event of an iCalendar
+// document by UID. DTSTAMP is left out, which servers may rewrite on every
+// download, and so is the order of properties and parameters.
+func Fingerprints(reader io.Reader) (map[string]Fingerprint, error) {
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make(map[string][]string)
+	summaries := make(map[string]string)
+	for _, event := range cal.Events() {
+		uid := propertyValue(&event.ComponentBase, ics.ComponentPropertyUniqueId)
+		var lines []string
+		for _, prop := range event.Properties {
+			if prop.IANAToken == string(ics.ComponentPropertyDtstamp) {
+				continue
+			}
+			var params []string
+			for name, values := range prop.ICalParameters {
+				params = append(params, name+"="+strings.Join(values, ","))
+			}
+			sort.Strings(params)
+			lines = append(lines, prop.IANAToken+";"+strings.Join(params, ";")+":"+prop.Value)
+		}
+		sort.Strings(lines)
+		components[uid] = append(components[uid], strings.Join(lines, "\n"))
+		// The series' summary rather than an override's
+		if summaries[uid] == "" || event.GetProperty(ics.ComponentPropertyRecurrenceId) == nil {
+			summaries[uid] = propertyValue(&event.ComponentBase, ics.ComponentPropertySummary)
+		}
+	}
+
+	fingerprints := make(map[string]Fingerprint, len(components))
+	for uid, parts := range components {
+		sort.Strings(parts)
+		sum := sha1.Sum([]byte(strings.Join(parts, "\n\n")))
+		fingerprints[uid] = Fingerprint{Summary: summaries[uid], Hash: hex.EncodeToString(sum[:])}
+	}
+	return fingerprints, nil
+}
@@ -0,0 +1,125 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Period is a span of busy time
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MergePeriods sorts periods and joins those that overlap or touch
+func MergePeriods(periods []Period) []Period {
+	sorted := append([]Period(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []Period
+	for _, p := range sorted {
+		if !p.End.After(p.Start) {
+			continue
+		}
+		if n := len(merged); n > 0 && !p.Start.After(merged[n-1].End) {
+			if p.End.After(merged[n-1].End) {
+				merged[n-1].End = p.End
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// BuildFreeBusy creates a VCALENDAR with one VFREEBUSY (RFC 5545 3.6.4)
+// publishing the busy periods within [from, to). Only the times are
+// published, never what the time is booked for.
+func BuildFreeBusy(busy []Period, from, to time.Time, organizer string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//MyTuiCalendar//EN\nMETHOD:PUBLISH\n")
+	b.WriteString("BEGIN:VFREEBUSY\n")
+	fmt.Fprintf(&b, "UID:%s\n", NewUID())
+	fmt.Fprintf(&b, "DTSTAMP:%s\n", time.Now().UTC().Format("20060102T150405Z"))
+	fmt.Fprintf(&b, "DTSTART:%s\n", from.UTC().Format("20060102T150405Z"))
+	fmt.Fprintf(&b, "DTEND:%s\n", to.UTC().Format("20060102T150405Z"))
+	if organizer != "" {
+		fmt.Fprintf(&b, "ORGANIZER:mailto:%s\n", organizer)
+	}
+	for _, p := range MergePeriods(busy) {
+		if !p.End.After(from) || !p.Start.Before(to) {
+			continue
+		}
+		start, end := p.Start, p.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		fmt.Fprintf(&b, "FREEBUSY;FBTYPE=BUSY:%s/%s\n",
+			start.UTC().Format("20060102T150405Z"), end.UTC().Format("20060102T150405Z"))
+	}
+	b.WriteString("END:VFREEBUSY\nEND:VCALENDAR\n")
+	return b.String()
+}
+
+// ParseFreeBusy returns the busy periods of the VFREEBUSY components in an
+// iCalendar document. Periods marked FBTYPE=FREE are skipped.
+func ParseFreeBusy(reader io.Reader) ([]Period, error) {
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []Period
+	for _, component := range cal.Components {
+		busy, ok := component.(*ics.VBusy)
+		if !ok {
+			continue
+		}
+		for _, prop := range busy.Properties {
+			if !strings.EqualFold(prop.IANAToken, string(ics.ComponentPropertyFreebusy)) {
+				continue
+			}
+			if fbType := prop.ICalParameters[string(ics.ParameterFbtype)]; len(fbType) > 0 && strings.EqualFold(fbType[0], "FREE") {
+				continue
+			}
+			for _, value := range strings.Split(prop.Value, ",") {
+				if p, err := parsePeriod(strings.TrimSpace(value)); err == nil {
+					periods = append(periods, p)
+				}
+			}
+		}
+	}
+	return MergePeriods(periods), nil
+}
+
+// parsePeriod reads an RFC 5545 PERIOD in UTC: start/end or start/duration
+func parsePeriod(value string) (Period, error) {
+	startStr, endStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return Period{}, fmt.Errorf("invalid period %q", value)
+	}
+	start, err := time.Parse("20060102T150405Z", startStr)
+	if err != nil {
+		return Period{}, err
+	}
+	if strings.HasPrefix(endStr, "P") || strings.HasPrefix(endStr, "+P") {
+		duration, err := ParseDuration(endStr)
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{Start: start, End: duration.AddTo(start)}, nil
+	}
+	end, err := time.Parse("20060102T150405Z", endStr)
+	if err != nil {
+		return Period{}, err
+	}
+	return Period{Start: start, End: end}, nil
+}
@@ -0,0 +1,395 @@
+package ical
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// FloatingLocation is where floating times (no "Z" and no TZID) are read,
+// the system's local time zone unless configured otherwise
+var FloatingLocation = time.Local
+
+// noTitle stands in for a missing SUMMARY
+const noTitle = "(No title)"
+
+// Event is a single calendar event. Recurring events are expanded into one
+// Event per occurrence, all sharing the UID and RRule.
+type Event struct {
+	Summary      string
+	Start        time.Time
+	End          time.Time
+	Description  string
+	HTML         string // X-ALT-DESC in HTML, the description as Outlook formats it
+	Location     string
+	URL          string // URL property, a page about the event
+	CalendarName string
+	UID          string    // For Radicale sync
+	RRule        string    // Raw recurrence rule, empty for single events
+	Sequence     int       // SEQUENCE revision counter
+	LastModified time.Time // LAST-MODIFIED, zero if unknown
+	Transp       string    // TRANSP: "OPAQUE", "TRANSPARENT" or empty (opaque)
+	Class        string    // CLASS: "PUBLIC", "PRIVATE", "CONFIDENTIAL" or empty (public)
+	Floating     bool      // Start has no time zone and was read in FloatingLocation
+	Organizer    string    // ORGANIZER email address, lowercase
+	Attendees    []string  // ATTENDEE email addresses, lowercase
+	RelatedTo    string    // RELATED-TO, e.g. the UID of the task the event was planned for
+
+	// Since is the year a yearly event counts from, for the age of
+	// birthdays and anniversaries; 0 for other events or an unknown year.
+	// Anniversary is "birthday" or "anniversary" if the calendar marks the
+	// event as one.
+	Since       int    `json:",omitempty"`
+	Anniversary string `json:",omitempty"`
+
+	// AttendeeNames are the display names (CN) of attendees by address,
+	// where known
+	AttendeeNames map[string]string `json:",omitempty"`
+
+	// AlsoIn names the other calendars holding the same event when
+	// duplicates across calendars are merged into this one
+	AlsoIn []string `json:",omitempty"`
+
+	// Raw is the original VEVENT (with the VTIMEZONEs it uses) as fetched,
+	// so writing the event back keeps properties this app doesn't model
+	Raw string `json:",omitempty"`
+}
+
+// Options limit what Parse keeps, for calendars too large to hold whole
+type Options struct {
+	// From and To keep only the events overlapping [From, To). Recurring
+	// events are expanded up to To, or a year ahead if it is zero.
+	From, To time.Time
+	// Stream parses one VEVENT at a time instead of the whole document
+	Stream bool
+	// NoRaw leaves Raw empty, for calendars that are never written to
+	NoRaw bool
+	// Strings, if not nil, shares equal texts between events
+	Strings Strings
+	// Now is when recurring events are expanded from, time.Now if zero
+	Now time.Time
+	// Timing, if not nil, adds up where the parse spent its time
+	Timing *Timing
+}
+
+// Timing is the time spent in ParseWith, for profiling startup
+type Timing struct {
+	Parse  time.Duration // All of it, including Read and Expand
+	Read   time.Duration // Waiting for the reader, e.g. on the network
+	Expand time.Duration // Expanding recurring events
+}
+
+// timedReader adds the time spent reading to a Timing
+type timedReader struct {
+	reader io.Reader
+	timing *Timing
+}
+
+func (r timedReader) Read(p []byte) (int, error) {
+	started := time.Now()
+	n, err := r.reader.Read(p)
+	r.timing.Read += time.Since(started)
+	return n, err
+}
+
+// Strings interns texts, so that events repeating a summary or location
+// across documents and calendars hold one copy of it
+type Strings map[string]string
+
+// Intern returns the held copy of value, holding value if there is none
+func (s Strings) Intern(value string) string {
+	if s == nil {
+		return value
+	}
+	if held, ok := s[value]; ok {
+		return held
+	}
+	s[value] = value
+	return value
+}
+
+// Parse reads the VEVENTs of an iCalendar document. Recurring events are
+// expanded into one Event per occurrence, up to a year ahead.
+func Parse(reader io.Reader, calendarName string) ([]Event, error) {
+	return ParseWith(reader, calendarName, Options{})
+}
+
+// ParseWith is Parse limited by opts
+func ParseWith(reader io.Reader, calendarName string, opts Options) ([]Event, error) {
+	if opts.Timing != nil {
+		started := time.Now()
+		defer func() { opts.Timing.Parse += time.Since(started) }()
+		reader = timedReader{reader, opts.Timing}
+	}
+	if opts.Stream {
+		return parseStream(reader, calendarName, opts)
+	}
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	timezones := timezoneMap(cal)
+	for _, event := range cal.Events() {
+		events = appendEvent(events, event, timezones, calendarName, opts)
+	}
+	return events, nil
+}
+
+// appendEvent appends the occurrences of a VEVENT within opts' window
+func appendEvent(events []Event, event *ics.VEvent, timezones map[string]*ics.VTimezone, calendarName string, opts Options) []Event {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	// Expand recurring events up to 1 year in the future
+	maxDate := now.AddDate(1, 0, 0)
+	if !opts.To.IsZero() {
+		maxDate = opts.To
+	}
+	inWindow := func(start, end time.Time) bool {
+		return (opts.From.IsZero() || !end.Before(opts.From)) && (opts.To.IsZero() || start.Before(opts.To))
+	}
+
+	start, floating, err := eventStart(event, timezones)
+	if err != nil {
+		return events
+	}
+	end := eventEnd(event, start, timezones)
+
+	summary := ""
+	if summaryProp := event.GetProperty(ics.ComponentPropertySummary); summaryProp != nil {
+		summary = summaryProp.Value
+	}
+
+	description := ""
+	if descProp := event.GetProperty(ics.ComponentPropertyDescription); descProp != nil {
+		description = descProp.Value
+	}
+	location := propertyValue(&event.ComponentBase, ics.ComponentPropertyLocation)
+	url := propertyValue(&event.ComponentBase, ics.ComponentPropertyUrl)
+	html := ""
+	if prop := event.GetProperty(ics.ComponentProperty("X-ALT-DESC")); prop != nil {
+		if fmtType := prop.ICalParameters[string(ics.ParameterFmttype)]; len(fmtType) > 0 && strings.EqualFold(fmtType[0], "text/html") {
+			html = prop.Value
+		}
+	}
+
+	uid := ""
+	if uidProp := event.GetProperty(ics.ComponentPropertyUniqueId); uidProp != nil {
+		uid = uidProp.Value
+	}
+
+	sequence := 0
+	if seqProp := event.GetProperty(ics.ComponentPropertySequence); seqProp != nil {
+		sequence, _ = strconv.Atoi(seqProp.Value)
+	}
+
+	lastModified, _ := event.GetLastModifiedAt()
+	transp := propertyValue(&event.ComponentBase, ics.ComponentPropertyTransp)
+	class := strings.ToUpper(propertyValue(&event.ComponentBase, ics.ComponentPropertyClass))
+	raw := ""
+	if !opts.NoRaw {
+		raw = rawComponent(event, timezones)
+	}
+	organizer := MailAddress(propertyValue(&event.ComponentBase, ics.ComponentPropertyOrganizer))
+	attendees, attendeeNames := eventAttendees(event)
+	relatedTo := propertyValue(&event.ComponentBase, ics.ComponentPropertyRelatedTo)
+
+	if summary == "" {
+		summary = noTitle
+	}
+	summary = opts.Strings.Intern(summary)
+	description = opts.Strings.Intern(description)
+	location = opts.Strings.Intern(location)
+
+	// Check for RRULE (recurrence rule) - try multiple property access methods
+	var rruleValue string
+
+	// First, try accessing all properties to find RRULE (most reliable)
+	for _, prop := range event.Properties {
+		// IANAToken is a field, not a method
+		if strings.ToUpper(prop.IANAToken) == "RRULE" {
+			rruleValue = prop.Value
+			break
+		}
+	}
+
+	// If not found in Properties, try GetProperty with extended
+	if rruleValue == "" {
+		rruleProp := event.GetProperty(ics.ComponentPropertyExtended("RRULE"))
+		if rruleProp != nil {
+			rruleValue = rruleProp.Value
+		} else {
+			// Try with lowercase
+			rruleProp = event.GetProperty(ics.ComponentPropertyExtended("rrule"))
+			if rruleProp != nil {
+				rruleValue = rruleProp.Value
+			}
+		}
+	}
+
+	since, anniversary := anniversaryOf(event, start, rruleValue)
+
+	// Occurrences are expanded in the event's own time zone so they keep
+	// their wall-clock time across DST changes, then shown in local time
+	if rruleValue != "" {
+		// Parse RRULE and expand occurrences
+		expanding := time.Now()
+		occurrences := Expand(start, end, rruleValue, maxDate, now)
+		if opts.Timing != nil {
+			opts.Timing.Expand += time.Since(expanding)
+		}
+		for _, occ := range occurrences {
+			if !inWindow(occ.Start, occ.End) {
+				continue
+			}
+			events = append(events, Event{
+				Summary:       summary,
+				Start:         occ.Start.In(time.Local),
+				End:           occ.End.In(time.Local),
+				Description:   description,
+				HTML:          html,
+				Location:      location,
+				URL:           url,
+				CalendarName:  calendarName,
+				UID:           uid,
+				RRule:         rruleValue,
+				Sequence:      sequence,
+				LastModified:  lastModified,
+				Transp:        transp,
+				Class:         class,
+				Floating:      floating,
+				Organizer:     organizer,
+				Attendees:     attendees,
+				AttendeeNames: attendeeNames,
+				RelatedTo:     relatedTo,
+				Since:         since,
+				Anniversary:   anniversary,
+				Raw:           raw,
+			})
+		}
+	} else if inWindow(start, end) {
+		// Single event (non-recurring) - include even if in the past (for today's view)
+		events = append(events, Event{
+			Summary:       summary,
+			Start:         start.In(time.Local),
+			End:           end.In(time.Local),
+			Description:   description,
+			HTML:          html,
+			Location:      location,
+			URL:           url,
+			CalendarName:  calendarName,
+			UID:           uid,
+			Sequence:      sequence,
+			LastModified:  lastModified,
+			Transp:        transp,
+			Class:         class,
+			Floating:      floating,
+			Organizer:     organizer,
+			Attendees:     attendees,
+			AttendeeNames: attendeeNames,
+			RelatedTo:     relatedTo,
+			Raw:           raw,
+		})
+	}
+	return events
+}
+
+// anniversaryOf returns the year a yearly event counts from, and whether
+// its calendar marks it as a "birthday" or "anniversary". Nextcloud's
+// birthday calendar gives years before 1970 apart from DTSTART.
+func anniversaryOf(event *ics.VEvent, start time.Time, rrule string) (int, string) {
+	if !strings.Contains(strings.ToUpper(rrule), "FREQ=YEARLY") {
+		return 0, ""
+	}
+	kind := ""
+	switch strings.ToUpper(propertyValue(&event.ComponentBase, ics.ComponentProperty("X-NEXTCLOUD-BC-FIELD-TYPE"))) {
+	case "BDAY":
+		kind = "birthday"
+	case "ANNIVERSARY":
+		kind = "anniversary"
+	}
+	if propertyValue(&event.ComponentBase, ics.ComponentProperty("X-NEXTCLOUD-BC-UNKNOWN-YEAR")) == "1" {
+		return 0, kind
+	}
+	if year, err := strconv.Atoi(propertyValue(&event.ComponentBase, ics.ComponentProperty("X-NEXTCLOUD-BC-YEAR"))); err == nil {
+		return year, kind
+	}
+	return start.Year(), kind
+}
+
+// eventEnd returns DTEND, or DTSTART plus DURATION. Events with neither
+// last an hour.
+func eventEnd(event *ics.VEvent, start time.Time, timezones map[string]*ics.VTimezone) time.Time {
+	if end, _, ok := propertyTime(event.GetProperty(ics.ComponentPropertyDtEnd), timezones); ok {
+		return end
+	}
+	if end, err := event.GetEndAt(); err == nil {
+		return end
+	}
+	if prop := event.GetProperty(ics.ComponentPropertyDuration); prop != nil {
+		if duration, err := ParseDuration(prop.Value); err == nil {
+			// A negative duration is invalid for events, so read it as empty
+			if end := duration.AddTo(start); !end.Before(start) {
+				return end
+			}
+			return start
+		}
+	}
+	return start.Add(time.Hour)
+}
+
+// rawComponent wraps a VEVENT and the timezones its properties refer to in
+// a VCALENDAR of its own
+func rawComponent(event *ics.VEvent, timezones map[string]*ics.VTimezone) string {
+	cal := ics.NewCalendarFor("MyTuiCalendar")
+	added := make(map[string]bool)
+	for _, prop := range event.Properties {
+		for _, tzid := range prop.ICalParameters[string(ics.ParameterTzid)] {
+			if tz, ok := timezones[tzid]; ok && !added[tzid] {
+				cal.Components = append(cal.Components, tz)
+				added[tzid] = true
+			}
+		}
+	}
+	cal.AddVEvent(event)
+	return cal.Serialize()
+}
+
+// eventAttendees returns the email addresses of an event's attendees and
+// the names of those that have a CN
+func eventAttendees(event *ics.VEvent) ([]string, map[string]string) {
+	var attendees []string
+	var names map[string]string
+	for _, prop := range event.Properties {
+		if strings.EqualFold(prop.IANAToken, string(ics.ComponentPropertyAttendee)) {
+			address := MailAddress(prop.Value)
+			if address == "" {
+				continue
+			}
+			attendees = append(attendees, address)
+			if cn := prop.ICalParameters[string(ics.ParameterCn)]; len(cn) > 0 && cn[0] != "" {
+				if names == nil {
+					names = make(map[string]string)
+				}
+				names[address] = cn[0]
+			}
+		}
+	}
+	return attendees, names
+}
+
+// MailAddress turns a CAL-ADDRESS like "mailto:Jane@Example.com", or a
+// vCard EMAIL, into a lowercase email address
+func MailAddress(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+	return strings.ToLower(value)
+}
@@ -0,0 +1,156 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar wraps VEVENTs in a VCALENDAR
+func calendar(events ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+// vevent builds a VEVENT from property lines
+func vevent(lines ...string) string {
+	return "BEGIN:VEVENT\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\n"
+}
+
+func TestParseSingleEvent(t *testing.T) {
+	doc := calendar(vevent(
+		"UID:single@test",
+		"DTSTAMP:20250101T000000Z",
+		"DTSTART;TZID=Europe/Berlin:20250310T090000",
+		"DTEND;TZID=Europe/Berlin:20250310T100000",
+		"SUMMARY:Standup",
+		"LOCATION:Room 1",
+		"SEQUENCE:3",
+		"TRANSP:TRANSPARENT",
+		"ORGANIZER:mailto:Boss@Example.com",
+	))
+
+	events, err := Parse(strings.NewReader(doc), "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	event := events[0]
+	if want := time.Date(2025, 3, 10, 9, 0, 0, 0, berlin); !event.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", event.Start, want)
+	}
+	if want := time.Date(2025, 3, 10, 10, 0, 0, 0, berlin); !event.End.Equal(want) {
+		t.Errorf("End = %v, want %v", event.End, want)
+	}
+	if event.Summary != "Standup" || event.Location != "Room 1" || event.CalendarName != "Work" {
+		t.Errorf("got %q at %q in %q", event.Summary, event.Location, event.CalendarName)
+	}
+	if event.UID != "single@test" || event.Sequence != 3 || event.Transp != "TRANSPARENT" {
+		t.Errorf("got UID %q, SEQUENCE %d, TRANSP %q", event.UID, event.Sequence, event.Transp)
+	}
+	if event.Organizer != "boss@example.com" {
+		t.Errorf("Organizer = %q", event.Organizer)
+	}
+	if event.Floating {
+		t.Error("event with TZID read as floating")
+	}
+}
+
+func TestParseMissingSummary(t *testing.T) {
+	doc := calendar(vevent(
+		"UID:untitled@test",
+		"DTSTART:20250310T090000Z",
+		"DTEND:20250310T100000Z",
+	))
+	events, err := Parse(strings.NewReader(doc), "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Summary != noTitle {
+		t.Fatalf("got %+v, want one event titled %q", events, noTitle)
+	}
+}
+
+func TestParseWithWindow(t *testing.T) {
+	doc := calendar(
+		vevent("UID:before@test", "DTSTART:20250301T090000Z", "DTEND:20250301T100000Z", "SUMMARY:Before"),
+		vevent("UID:inside@test", "DTSTART:20250310T090000Z", "DTEND:20250310T100000Z", "SUMMARY:Inside"),
+		vevent("UID:after@test", "DTSTART:20250320T090000Z", "DTEND:20250320T100000Z", "SUMMARY:After"),
+	)
+	opts := Options{
+		From: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, stream := range []bool{false, true} {
+		opts.Stream = stream
+		events, err := ParseWith(strings.NewReader(doc), "Work", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].Summary != "Inside" {
+			t.Errorf("stream %v: got %d events, want only Inside", stream, len(events))
+		}
+	}
+}
+
+func TestParseRecurringFromNow(t *testing.T) {
+	doc := calendar(vevent(
+		"UID:weekly@test",
+		"DTSTART:20250106T090000Z",
+		"DTEND:20250106T093000Z",
+		"RRULE:FREQ=WEEKLY;COUNT=10",
+		"SUMMARY:Weekly",
+	))
+	opts := Options{
+		Now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		To:  time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := ParseWith(strings.NewReader(doc), "Work", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var starts []string
+	for _, event := range events {
+		starts = append(starts, event.Start.UTC().Format("2006-01-02"))
+		if event.RRule == "" || event.UID != "weekly@test" {
+			t.Errorf("occurrence %v lost its UID or RRULE", event.Start)
+		}
+	}
+	want := "2025-01-06 2025-01-13 2025-01-20 2025-01-27"
+	if got := strings.Join(starts, " "); got != want {
+		t.Errorf("occurrences = %s, want %s", got, want)
+	}
+}
+
+func TestBuildRoundTrip(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)
+	event := Event{
+		UID:         "roundtrip@test",
+		Summary:     "Review, part 2; final",
+		Description: "Line one\nLine two",
+		Location:    "Room 4",
+		Start:       start,
+		End:         start.Add(45 * time.Minute),
+	}
+
+	events, err := Parse(strings.NewReader(Build([]Event{event})), "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.UID != event.UID || got.Summary != event.Summary || got.Description != event.Description || got.Location != event.Location {
+		t.Errorf("got %+v, want %+v", got, event)
+	}
+	if !got.Start.Equal(event.Start) || !got.End.Equal(event.End) {
+		t.Errorf("got %v-%v, want %v-%v", got.Start, got.End, event.Start, event.End)
+	}
+}
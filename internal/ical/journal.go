@@ -0,0 +1,95 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Journal is a VJOURNAL entry. Journals dated to a day are shown as that
+// day's note.
+type Journal struct {
+	UID          string
+	Date         time.Time // Day the note belongs to, in local time
+	Summary      string
+	Description  string
+	CalendarName string
+	Raw          string `json:",omitempty"` // Original VJOURNAL, see Event.Raw
+}
+
+// ParseJournals reads the VJOURNALs of an iCalendar document. Journals
+// without a DTSTART are skipped.
+func ParseJournals(reader io.Reader, calendarName string) ([]Journal, error) {
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var journals []Journal
+	for _, component := range cal.Components {
+		journal, ok := component.(*ics.VJournal)
+		if !ok {
+			continue
+		}
+
+		date, err := journal.GetAllDayStartAt()
+		if err != nil {
+			if date, err = journal.GetStartAt(); err != nil {
+				continue
+			}
+		}
+
+		journals = append(journals, Journal{
+			UID:          propertyValue(&journal.ComponentBase, ics.ComponentPropertyUniqueId),
+			Date:         time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local),
+			Summary:      propertyValue(&journal.ComponentBase, ics.ComponentPropertySummary),
+			Description:  propertyValue(&journal.ComponentBase, ics.ComponentPropertyDescription),
+			CalendarName: calendarName,
+			Raw:          journal.Serialize(serialConfig),
+		})
+	}
+	return journals, nil
+}
+
+// BuildJournal serializes a journal into a VCALENDAR document. A journal
+// read from a server keeps its other properties.
+func BuildJournal(journal Journal) string {
+	now := time.Now().UTC().Format("20060102T150405Z")
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//MyTuiCalendar//EN\n")
+
+	if journal.Raw != "" {
+		cal, err := ics.ParseCalendar(strings.NewReader("BEGIN:VCALENDAR\n" + journal.Raw + "END:VCALENDAR\n"))
+		if err == nil && len(cal.Components) == 1 {
+			if original, ok := cal.Components[0].(*ics.VJournal); ok {
+				if journal.Summary != propertyValue(&original.ComponentBase, ics.ComponentPropertySummary) {
+					original.SetProperty(ics.ComponentPropertySummary, journal.Summary)
+				}
+				if journal.Description != propertyValue(&original.ComponentBase, ics.ComponentPropertyDescription) {
+					original.SetProperty(ics.ComponentPropertyDescription, journal.Description)
+				}
+				original.SetProperty(ics.ComponentPropertyLastModified, now)
+				original.SerializeTo(&b, serialConfig)
+				b.WriteString("END:VCALENDAR\n")
+				return b.String()
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, `BEGIN:VJOURNAL
+UID:%s
+DTSTAMP:%s
+DTSTART;VALUE=DATE:%s
+SUMMARY:%s
+DESCRIPTION:%s
+END:VJOURNAL
+`, journal.UID, now, journal.Date.Format("20060102"),
+		escapeValue(journal.Summary),
+		escapeValue(journal.Description))
+	b.WriteString("END:VCALENDAR\n")
+	return b.String()
+}
@@ -0,0 +1,463 @@
+package ical
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// subDailyHorizon limits how far ahead HOURLY and MINUTELY rules are expanded
+const subDailyHorizon = 31 * 24 * time.Hour
+
+// Occurrence is a single instance of a recurring event
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Expand expands a recurring event based on its RRULE
+func Expand(start, end time.Time, rrule string, maxDate time.Time, now time.Time) []Occurrence {
+	var occurrences []Occurrence
+	duration := end.Sub(start)
+
+	// Parse RRULE - basic support for common patterns
+	// Format: FREQ=MINUTELY|HOURLY|DAILY|WEEKLY|MONTHLY|YEARLY[;INTERVAL=n][;COUNT=n][;UNTIL=YYYYMMDDTHHMMSSZ][;BYDAY=MO,1MO,-1FR,..][;BYMONTH=m,..][;BYHOUR=h,..][;BYMINUTE=m,..]
+	rrule = strings.ToUpper(rrule)
+
+	var freq string
+	interval := 1
+	var until time.Time
+	count := -1
+	var byHour, byMinute, byMonth []int
+	var byDay []weekdayNum
+
+	parts := strings.Split(rrule, ";")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "FREQ=") {
+			freq = strings.TrimPrefix(part, "FREQ=")
+		} else if strings.HasPrefix(part, "INTERVAL=") {
+			if val, err := strconv.Atoi(strings.TrimPrefix(part, "INTERVAL=")); err == nil {
+				interval = val
+			}
+		} else if strings.HasPrefix(part, "UNTIL=") {
+			untilStr := strings.TrimPrefix(part, "UNTIL=")
+			// Try parsing different date formats
+			if t, err := time.Parse("20060102T150405Z", untilStr); err == nil {
+				until = t
+			} else if t, err := time.ParseInLocation("20060102T150405", untilStr, start.Location()); err == nil {
+				// A floating UNTIL is in the same time zone as DTSTART
+				until = t
+			} else if t, err := time.ParseInLocation("20060102", untilStr, start.Location()); err == nil {
+				until = t
+			}
+		} else if strings.HasPrefix(part, "COUNT=") {
+			if val, err := strconv.Atoi(strings.TrimPrefix(part, "COUNT=")); err == nil {
+				count = val
+			}
+		} else if strings.HasPrefix(part, "BYDAY=") {
+			byDay = parseWeekdays(strings.TrimPrefix(part, "BYDAY="))
+		} else if strings.HasPrefix(part, "BYMONTH=") {
+			byMonth = parseIntList(strings.TrimPrefix(part, "BYMONTH="), 1, 12)
+		} else if strings.HasPrefix(part, "BYHOUR=") {
+			byHour = parseIntList(strings.TrimPrefix(part, "BYHOUR="), 0, 23)
+		} else if strings.HasPrefix(part, "BYMINUTE=") {
+			byMinute = parseIntList(strings.TrimPrefix(part, "BYMINUTE="), 0, 59)
+		}
+	}
+
+	// Determine end date
+	endDate := maxDate
+	subDaily := freq == "HOURLY" || freq == "MINUTELY"
+	if subDaily && endDate.After(now.Add(subDailyHorizon)) {
+		// Hourly events would otherwise add thousands of occurrences
+		endDate = now.Add(subDailyHorizon)
+	}
+	if !until.IsZero() && until.Before(endDate) {
+		endDate = until
+	}
+
+	// Start from the original start date
+	currentStart := start
+	iteration := 0
+	generated := 0        // Occurrences so far, for COUNT
+	maxIterations := 1000 // Safety limit
+	if subDaily {
+		maxIterations = 100000
+	}
+
+	// Check if we need to fast-forward past occurrences
+	// Only fast-forward if the event is more than 1 day in the past
+	// We want to include events from yesterday (they're still relevant)
+	originalIsToday := currentStart.Format("2006-01-02") == now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1)
+	originalIsYesterday := currentStart.Format("2006-01-02") == yesterday.Format("2006-01-02")
+	// Only fast-forward if it's before yesterday (more than 1 day old).
+	// COUNT rules are walked from the start, as skipped occurrences count.
+	needsFastForward := currentStart.Before(yesterday) && !originalIsToday && !originalIsYesterday && count < 0
+
+	// Rules on weekdays of a month step from the month's first day, so that
+	// stepping from a 31st doesn't skip shorter months. Fast-forwarding
+	// would overshoot the rest of the current month and isn't needed at one
+	// step per month.
+	if byMonthDays(freq, byDay, byMonth) {
+		if freq == "MONTHLY" {
+			currentStart = time.Date(start.Year(), start.Month(), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		} else {
+			currentStart = time.Date(start.Year(), time.January, 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		}
+		needsFastForward = false
+	}
+
+	// Monthly and yearly rules on a day of the month count their steps from
+	// DTSTART, as stepping from the last occurrence would carry a 31st over
+	// into the next month after a shorter one. Steps on a day the month
+	// doesn't have (Apr 31, Feb 29 in common years) are skipped, as RFC 5545
+	// requires.
+	onMonthDay := (freq == "MONTHLY" || freq == "YEARLY") && !byMonthDays(freq, byDay, byMonth)
+
+	// Weekly rules with BYDAY expand each step into its week, so they step
+	// from the week's Monday: stepping on DTSTART's weekday would lose the
+	// days before it in the week of now and in the last week. Days before
+	// DTSTART are dropped below.
+	weeklyByDay := freq == "WEEKLY" && len(byDay) > 0
+	monthsPerStep := interval
+	if freq == "YEARLY" {
+		monthsPerStep = 12 * interval
+	}
+	steps := 0
+	validDay := true
+
+	// If the original event is today or in the future, we'll include it in the loop
+	// If it's in the past (not today), we need to fast-forward to today or the next occurrence
+	if needsFastForward {
+		// For past events, fast-forward to today's occurrence (if it exists) or the next occurrence after now
+		// We want to include today's occurrence even if the event started in the past
+		todayDate := now.Format("2006-01-02")
+		switch freq {
+		case "HOURLY", "MINUTELY":
+			// Jump straight to the first step on or after yesterday's midnight
+			step := time.Duration(interval) * time.Hour
+			if freq == "MINUTELY" {
+				step = time.Duration(interval) * time.Minute
+			}
+			yesterdayStart := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, yesterday.Location())
+			steps := yesterdayStart.Sub(currentStart) / step
+			currentStart = currentStart.Add(steps * step)
+			if currentStart.Before(yesterdayStart) {
+				currentStart = currentStart.Add(step)
+			}
+		case "DAILY":
+			// Fast-forward until we reach today (date-wise) or the future
+			for {
+				nextStart := currentStart.AddDate(0, 0, interval)
+				nextDate := nextStart.Format("2006-01-02")
+
+				// Stop if we've reached today (same date) - regardless of time
+				// OR if we've reached the future
+				if nextDate == todayDate {
+					currentStart = nextStart
+					break
+				}
+
+				// If we've reached the future (after today), stop
+				if nextStart.After(now) {
+					currentStart = nextStart
+					break
+				}
+
+				// If still in the past (before today), continue
+				currentStart = nextStart
+			}
+		case "WEEKLY":
+			// Jump to the last step in or before yesterday's week. Days
+			// before yesterday are dropped below.
+			weeks := daysBetween(weekStart(start), weekStart(yesterday)) / 7
+			currentStart = start.AddDate(0, 0, 7*interval*(weeks/interval))
+			if weeklyByDay {
+				currentStart = weekStart(currentStart)
+			}
+		case "MONTHLY", "YEARLY":
+			// Fast-forward until we reach today (date-wise) or the future
+			for {
+				steps++
+				currentStart, validDay = addMonths(start, steps*monthsPerStep)
+				if currentStart.Format("2006-01-02") == todayDate || currentStart.After(now) {
+					break
+				}
+			}
+		default:
+			// Unknown frequency, return empty
+			return occurrences
+		}
+		// Make sure we don't skip too far
+		if currentStart.After(endDate) {
+			return occurrences
+		}
+	} else if !byMonthDays(freq, byDay, byMonth) {
+		// Original event is today or in the future - start from the original start
+		// This ensures we include the first occurrence
+		currentStart = start
+		if weeklyByDay {
+			currentStart = weekStart(start)
+		}
+	}
+
+	// Generate occurrences starting from currentStart
+	// Always include the first occurrence if it's today or in the future
+	for currentStart.Before(endDate) && iteration < maxIterations {
+		if count > 0 && generated >= count {
+			break
+		}
+
+		var stepStarts []time.Time
+		days := expandByDay(currentStart, freq, byDay, byMonth)
+		if onMonthDay && !validDay {
+			days = nil
+		}
+		for _, day := range days {
+			stepStarts = append(stepStarts, expandByTime(day, freq, byHour, byMinute)...)
+		}
+		for _, occStart := range stepStarts {
+			if occStart.Before(start) || !occStart.Before(endDate) || (count > 0 && generated >= count) {
+				continue
+			}
+			generated++
+
+			// Include occurrences that are yesterday, today, or in the future
+			// We include yesterday's events because they're still relevant (just happened)
+			occIsToday := occStart.Format("2006-01-02") == now.Format("2006-01-02")
+			occIsYesterday := occStart.Format("2006-01-02") == yesterday.Format("2006-01-02")
+			occIsFuture := occStart.After(now)
+
+			// Always include if it's yesterday, today, or in the future
+			if occIsYesterday || occIsToday || occIsFuture {
+				occurrences = append(occurrences, Occurrence{
+					Start: occStart,
+					End:   occStart.Add(duration),
+				})
+			}
+		}
+
+		// Move to next occurrence based on frequency
+		switch freq {
+		case "MINUTELY":
+			currentStart = currentStart.Add(time.Duration(interval) * time.Minute)
+		case "HOURLY":
+			currentStart = currentStart.Add(time.Duration(interval) * time.Hour)
+		case "DAILY":
+			currentStart = currentStart.AddDate(0, 0, interval)
+		case "WEEKLY":
+			currentStart = currentStart.AddDate(0, 0, 7*interval)
+		case "MONTHLY", "YEARLY":
+			if onMonthDay {
+				steps++
+				currentStart, validDay = addMonths(start, steps*monthsPerStep)
+			} else if freq == "MONTHLY" {
+				currentStart = currentStart.AddDate(0, interval, 0)
+			} else {
+				currentStart = currentStart.AddDate(interval, 0, 0)
+			}
+		default:
+			// Unknown frequency, stop expansion
+			return occurrences
+		}
+
+		iteration++
+	}
+
+	return occurrences
+}
+
+// addMonths returns t moved by months, on the same day and time of day,
+// and false if the month is too short for the day. The date is then
+// normalized past the month's end.
+func addMonths(t time.Time, months int) (time.Time, bool) {
+	moved := time.Date(t.Year(), t.Month()+time.Month(months), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	return moved, moved.Day() == t.Day()
+}
+
+// weekStart returns the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -mondayOffset(t.Weekday()))
+}
+
+// daysBetween counts the calendar days from a to b, ignoring DST changes
+func daysBetween(a, b time.Time) int {
+	dateA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dateB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dateB.Sub(dateA).Hours() / 24)
+}
+
+// weekdayCodes are the BYDAY codes, indexed by time.Weekday
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// weekdayNum is a BYDAY entry: a weekday, and for monthly and yearly rules
+// which of its occurrences in the month, counted from the end if negative
+// ("-1FR" is the last Friday). Ordinal 0 means every one.
+type weekdayNum struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// byMonthDays reports whether a rule picks weekdays within months: monthly
+// rules with BYDAY, and yearly ones with BYDAY and BYMONTH
+func byMonthDays(freq string, byDay []weekdayNum, byMonth []int) bool {
+	return len(byDay) > 0 && (freq == "MONTHLY" || (freq == "YEARLY" && len(byMonth) > 0))
+}
+
+// expandByDay applies BYDAY (RFC 5545 3.3.10): it limits the days of DAILY
+// and finer rules, expands each week of a WEEKLY rule into the listed
+// weekdays, weeks starting on Monday, and each month of a MONTHLY rule (or
+// each BYMONTH month of a YEARLY one) into the listed weekdays of the month
+func expandByDay(t time.Time, freq string, byDay []weekdayNum, byMonth []int) []time.Time {
+	if len(byDay) == 0 {
+		return []time.Time{t}
+	}
+	switch freq {
+	case "WEEKLY":
+		monday := weekStart(t)
+		days := make([]time.Time, 0, len(byDay))
+		for _, day := range byDay {
+			days = append(days, monday.AddDate(0, 0, mondayOffset(day.weekday)))
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		return days
+	case "DAILY", "HOURLY", "MINUTELY":
+		for _, day := range byDay {
+			if t.Weekday() == day.weekday {
+				return []time.Time{t}
+			}
+		}
+		return nil
+	case "MONTHLY":
+		return monthWeekdays(t, t.Month(), byDay)
+	case "YEARLY":
+		if len(byMonth) > 0 {
+			var days []time.Time
+			for _, month := range byMonth {
+				days = append(days, monthWeekdays(t, time.Month(month), byDay)...)
+			}
+			sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+			return days
+		}
+	}
+	// Weekdays of the whole year ("20MO") aren't supported
+	return []time.Time{t}
+}
+
+// monthWeekdays returns the days of month in t's year matching byDay, at
+// t's time of day
+func monthWeekdays(t time.Time, month time.Month, byDay []weekdayNum) []time.Time {
+	first := time.Date(t.Year(), month, 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+	length := first.AddDate(0, 1, -1).Day()
+
+	var days []time.Time
+	for day := 1; day <= length; day++ {
+		date := first.AddDate(0, 0, day-1)
+		for _, entry := range byDay {
+			if date.Weekday() != entry.weekday {
+				continue
+			}
+			nth := (day-1)/7 + 1             // Counted from the start
+			nthLast := -((length-day)/7 + 1) // Counted from the end
+			if entry.ordinal == 0 || entry.ordinal == nth || entry.ordinal == nthLast {
+				days = append(days, date)
+				break
+			}
+		}
+	}
+	return days
+}
+
+// mondayOffset is the number of days from Monday to weekday
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// parseWeekdays parses a BYDAY list like "MO,WE,FR" or "1MO,-1FR". Invalid
+// entries and duplicates are skipped.
+func parseWeekdays(s string) []weekdayNum {
+	var weekdays []weekdayNum
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) < 2 {
+			continue
+		}
+		ordinal := 0
+		if prefix := field[:len(field)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				continue
+			}
+			ordinal = n
+		}
+		for i, code := range weekdayCodes {
+			entry := weekdayNum{ordinal: ordinal, weekday: time.Weekday(i)}
+			if field[len(field)-2:] == code && !slices.Contains(weekdays, entry) {
+				weekdays = append(weekdays, entry)
+			}
+		}
+	}
+	return weekdays
+}
+
+// expandByTime applies BYHOUR and BYMINUTE to one step of a rule (RFC 5545
+// 3.3.10): they limit the steps of a rule at least as fine as themselves and
+// expand a step into several times otherwise
+func expandByTime(t time.Time, freq string, byHour, byMinute []int) []time.Time {
+	if len(byHour) > 0 && (freq == "HOURLY" || freq == "MINUTELY") && !containsInt(byHour, t.Hour()) {
+		return nil
+	}
+	if len(byMinute) > 0 && freq == "MINUTELY" && !containsInt(byMinute, t.Minute()) {
+		return nil
+	}
+
+	hours, minutes := byHour, byMinute
+	if freq == "HOURLY" || freq == "MINUTELY" {
+		hours = nil
+	}
+	if freq == "MINUTELY" {
+		minutes = nil
+	}
+	if len(hours) == 0 && len(minutes) == 0 {
+		return []time.Time{t}
+	}
+	if len(hours) == 0 {
+		hours = []int{t.Hour()}
+	}
+	if len(minutes) == 0 {
+		minutes = []int{t.Minute()}
+	}
+	var times []time.Time
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			times = append(times, time.Date(t.Year(), t.Month(), t.Day(), hour, minute, t.Second(), 0, t.Location()))
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// parseIntList parses a comma-separated list like "8,12,20", dropping
+// values outside [min, max]
+func parseIntList(s string, min, max int) []int {
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		if v, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && v >= min && v <= max {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
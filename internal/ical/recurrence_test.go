@@ -0,0 +1,118 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 10, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		rrule string
+		now   time.Time // The start if zero
+		until time.Time // maxDate
+		want  []string  // Dates of the occurrences
+	}{
+		// Days the month doesn't have are skipped, not moved
+		{"31st monthly", date(2025, 1, 31), "FREQ=MONTHLY;COUNT=4", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-31", "2025-03-31", "2025-05-31", "2025-07-31"}},
+		{"31st monthly until", date(2025, 1, 31), "FREQ=MONTHLY;UNTIL=20250601T000000Z", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-31", "2025-03-31", "2025-05-31"}},
+		{"30th monthly", date(2025, 1, 30), "FREQ=MONTHLY;COUNT=4", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-30", "2025-03-30", "2025-04-30", "2025-05-30"}},
+		{"29th monthly in a leap year", date(2024, 1, 29), "FREQ=MONTHLY;COUNT=3", time.Time{}, date(2025, 1, 1),
+			[]string{"2024-01-29", "2024-02-29", "2024-03-29"}},
+		{"29th monthly in a common year", date(2025, 1, 29), "FREQ=MONTHLY;COUNT=3", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-29", "2025-03-29", "2025-04-29"}},
+		{"31st every other month", date(2025, 8, 31), "FREQ=MONTHLY;INTERVAL=2;COUNT=3", time.Time{}, date(2027, 1, 1),
+			[]string{"2025-08-31", "2025-10-31", "2025-12-31"}},
+		{"31st every other month into short ones", date(2024, 12, 31), "FREQ=MONTHLY;INTERVAL=2;COUNT=2", time.Time{}, date(2026, 1, 1),
+			[]string{"2024-12-31", "2025-08-31"}},
+		{"15th quarterly", date(2025, 1, 15), "FREQ=MONTHLY;INTERVAL=3;COUNT=4", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-15", "2025-04-15", "2025-07-15", "2025-10-15"}},
+		// Fast-forwarded from DTSTART, past the months without a 31st
+		{"31st monthly from now", date(2025, 1, 31), "FREQ=MONTHLY", date(2025, 6, 15), date(2025, 11, 1),
+			[]string{"2025-07-31", "2025-08-31", "2025-10-31"}},
+
+		{"leap day yearly", date(2024, 2, 29), "FREQ=YEARLY", time.Time{}, date(2033, 1, 1),
+			[]string{"2024-02-29", "2028-02-29", "2032-02-29"}},
+		{"leap day yearly count", date(2024, 2, 29), "FREQ=YEARLY;COUNT=2", time.Time{}, date(2040, 1, 1),
+			[]string{"2024-02-29", "2028-02-29"}},
+		{"leap day every other year", date(2024, 2, 29), "FREQ=YEARLY;INTERVAL=2;COUNT=3", time.Time{}, date(2040, 1, 1),
+			[]string{"2024-02-29", "2028-02-29", "2032-02-29"}},
+		{"leap day yearly from now", date(2024, 2, 29), "FREQ=YEARLY", date(2026, 6, 1), date(2033, 1, 1),
+			[]string{"2028-02-29", "2032-02-29"}},
+
+		// Weekly rules fast-forwarded into the week of now keep its days
+		// from yesterday on, before and after DTSTART's weekday
+		{"weekly on three days", date(2026, 1, 5), "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(2026, 10, 14), date(2026, 10, 20),
+			[]string{"2026-10-14", "2026-10-16", "2026-10-19"}},
+		{"weekly from yesterday", date(2026, 1, 5), "FREQ=WEEKLY;BYDAY=MO,TU,FR", date(2026, 10, 14), date(2026, 10, 20),
+			[]string{"2026-10-13", "2026-10-16", "2026-10-19"}},
+		{"weekly on Sunday, viewed on Monday", date(2026, 1, 4), "FREQ=WEEKLY;BYDAY=MO,SU", date(2026, 10, 19), date(2026, 10, 27),
+			[]string{"2026-10-18", "2026-10-19", "2026-10-25", "2026-10-26"}},
+		{"every other week", date(2026, 1, 5), "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, 10, 14), date(2026, 11, 1),
+			[]string{"2026-10-16", "2026-10-26", "2026-10-30"}},
+		{"every other week, off week", date(2026, 1, 5), "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, 10, 21), date(2026, 11, 1),
+			[]string{"2026-10-26", "2026-10-30"}},
+		{"weekly without BYDAY", date(2026, 1, 9), "FREQ=WEEKLY", date(2026, 10, 14), date(2026, 10, 24),
+			[]string{"2026-10-16", "2026-10-23"}},
+		{"weekly count", date(2026, 1, 5), "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3", time.Time{}, date(2027, 1, 1),
+			[]string{"2026-01-05", "2026-01-07", "2026-01-12"}},
+
+		// Ordinal weekdays of the month
+		{"first Monday", date(2025, 1, 6), "FREQ=MONTHLY;BYDAY=1MO;COUNT=3", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-06", "2025-02-03", "2025-03-03"}},
+		{"last Friday", date(2025, 1, 31), "FREQ=MONTHLY;BYDAY=-1FR;COUNT=4", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-31", "2025-02-28", "2025-03-28", "2025-04-25"}},
+		{"first Monday and last Friday", date(2025, 1, 6), "FREQ=MONTHLY;BYDAY=1MO,-1FR;COUNT=4", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-06", "2025-01-31", "2025-02-03", "2025-02-28"}},
+		{"last Friday every other month", date(2025, 1, 31), "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;COUNT=3", time.Time{}, date(2026, 1, 1),
+			[]string{"2025-01-31", "2025-03-28", "2025-05-30"}},
+		{"fourth Thursday of November", date(2024, 11, 28), "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=3", time.Time{}, date(2030, 1, 1),
+			[]string{"2024-11-28", "2025-11-27", "2026-11-26"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.now.After(test.until) {
+				t.Fatal("now after maxDate")
+			}
+			now := test.now
+			if now.IsZero() {
+				now = test.start
+			}
+			var got []string
+			for _, occ := range Expand(test.start, test.start.Add(time.Hour), test.rrule, test.until, now) {
+				if occ.Start.Hour() != 10 || occ.End.Sub(occ.Start) != time.Hour {
+					t.Errorf("occurrence %v - %v, want 10:00 for 1h", occ.Start, occ.End)
+				}
+				got = append(got, occ.Start.Format("2006-01-02"))
+			}
+			if strings.Join(got, " ") != strings.Join(test.want, " ") {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// Hourly rules start at the first step since yesterday's midnight
+func TestExpandHourly(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	var got []string
+	for _, occ := range Expand(start, start.Add(30*time.Minute), "FREQ=HOURLY;INTERVAL=6", now.AddDate(0, 0, 1), now) {
+		got = append(got, occ.Start.Format("01-02 15:04"))
+	}
+	want := []string{"10-13 03:00", "10-13 09:00", "10-13 15:00", "10-13 21:00", "10-14 03:00", "10-14 09:00",
+		"10-14 15:00", "10-14 21:00", "10-15 03:00", "10-15 09:00"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package ical
+
+import (
+	"strings"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Replace returns doc, an iCalendar document, with the event of uid (the
+// series and its overrides) replaced by events, or removed if there are
+// none, and whether doc held it. The timezones of events that doc lacks
+// are added. An empty doc is a new calendar.
+func Replace(doc, uid string, events []Event) (string, bool, error) {
+	if strings.TrimSpace(doc) == "" {
+		doc = Build(nil)
+	}
+	cal, err := ics.ParseCalendar(strings.NewReader(doc))
+	if err != nil {
+		return "", false, err
+	}
+	added, err := ics.ParseCalendar(strings.NewReader(Build(events)))
+	if err != nil {
+		return "", false, err
+	}
+
+	timezones := timezoneMap(cal)
+	found := false
+	var components []ics.Component
+	for _, component := range cal.Components {
+		if event, ok := component.(*ics.VEvent); ok && propertyValue(&event.ComponentBase, ics.ComponentPropertyUniqueId) == uid {
+			found = true
+			continue
+		}
+		components = append(components, component)
+	}
+	for _, component := range added.Components {
+		if tz, ok := component.(*ics.VTimezone); ok {
+			if _, ok := timezones[propertyValue(&tz.ComponentBase, ics.ComponentPropertyTzid)]; ok {
+				continue
+			}
+		}
+		components = append(components, component)
+	}
+	cal.Components = components
+	return cal.Serialize(serialConfig), found, nil
+}
@@ -0,0 +1,97 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Participation statuses of a reply
+const (
+	PartStatAccepted  = "ACCEPTED"
+	PartStatDeclined  = "DECLINED"
+	PartStatTentative = "TENTATIVE"
+)
+
+// replyProperties are copied from the invitation into the reply, which
+// must identify the event (and occurrence) and its organizer (RFC 5546)
+var replyProperties = []ics.ComponentProperty{
+	ics.ComponentPropertyRecurrenceId,
+	ics.ComponentPropertySequence,
+	ics.ComponentPropertyDtStart,
+	ics.ComponentPropertyDtEnd,
+	ics.ComponentPropertyDuration,
+	ics.ComponentPropertySummary,
+	ics.ComponentPropertyOrganizer,
+}
+
+// Reply builds the METHOD:REPLY an attendee sends the organizer of a
+// METHOD:REQUEST invitation, answering each event they're invited to with
+// partStat
+func Reply(reader io.Reader, attendee, partStat string, now time.Time) (string, error) {
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return "", err
+	}
+	for _, prop := range cal.CalendarProperties {
+		if prop.IANAToken == string(ics.PropertyMethod) && !strings.EqualFold(prop.Value, string(ics.MethodRequest)) {
+			return "", fmt.Errorf("not an invitation (METHOD:%s)", prop.Value)
+		}
+	}
+	attendee = MailAddress(attendee)
+
+	var events strings.Builder
+	for _, event := range cal.Events() {
+		var invited *ics.IANAProperty
+		for i := range event.Properties {
+			prop := &event.Properties[i]
+			if prop.IANAToken == string(ics.ComponentPropertyAttendee) && MailAddress(prop.Value) == attendee {
+				invited = prop
+			}
+		}
+		if invited == nil {
+			continue
+		}
+
+		reply := ics.NewEvent(propertyValue(&event.ComponentBase, ics.ComponentPropertyUniqueId))
+		reply.SetDtStampTime(now)
+		for _, property := range replyProperties {
+			if prop := event.GetProperty(property); prop != nil {
+				reply.Properties = append(reply.Properties, *prop)
+			}
+		}
+		// Their own attendee entry, with the answer instead of the request
+		// for one
+		answer := ics.IANAProperty{BaseProperty: ics.BaseProperty{
+			IANAToken:      invited.IANAToken,
+			ICalParameters: make(map[string][]string),
+			Value:          invited.Value,
+		}}
+		for param, values := range invited.ICalParameters {
+			if param != string(ics.ParameterRsvp) {
+				answer.ICalParameters[param] = values
+			}
+		}
+		answer.ICalParameters[string(ics.ParameterParticipationStatus)] = []string{partStat}
+		reply.Properties = append(reply.Properties, answer)
+		reply.SerializeTo(&events, serialConfig)
+	}
+	if events.Len() == 0 {
+		return "", fmt.Errorf("%s is not an attendee of the invitation", attendee)
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//MyTuiCalendar//EN\nMETHOD:REPLY\n")
+	// Times in the reply may refer to the invitation's time zones
+	for _, component := range cal.Components {
+		if tz, ok := component.(*ics.VTimezone); ok {
+			tz.SerializeTo(&b, serialConfig)
+		}
+	}
+	b.WriteString(events.String())
+	b.WriteString("END:VCALENDAR\n")
+	return b.String(), nil
+}
@@ -0,0 +1,78 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// maxLineLength bounds the unfolded lines parseStream reads, which may hold
+// inline attachments
+const maxLineLength = 16 << 20
+
+// parseStream reads a document one component at a time, so besides the
+// events only the VEVENT being read and the VTIMEZONEs are held, never the
+// whole parsed document
+func parseStream(reader io.Reader, calendarName string, opts Options) ([]Event, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxLineLength)
+
+	var events []Event
+	var block, zones strings.Builder
+	timezones := map[string]*ics.VTimezone{}
+	component := "" // The VEVENT or VTIMEZONE being read
+	calendar := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if component == "" {
+			switch strings.ToUpper(line) {
+			case "BEGIN:VCALENDAR":
+				calendar = true
+			case "BEGIN:VEVENT", "BEGIN:VTIMEZONE":
+				component = strings.ToUpper(line[len("BEGIN:"):])
+				block.Reset()
+			}
+			if component == "" {
+				continue
+			}
+		}
+		block.WriteString(line + "\r\n")
+		if !strings.EqualFold(line, "END:"+component) {
+			continue
+		}
+
+		if component == "VTIMEZONE" {
+			// Time zones come before the events using them, and are few
+			zones.WriteString(block.String())
+			cal, err := ics.ParseCalendar(strings.NewReader(wrapCalendar(zones.String())))
+			if err != nil {
+				return nil, err
+			}
+			timezones = timezoneMap(cal)
+		} else {
+			cal, err := ics.ParseCalendar(strings.NewReader(wrapCalendar(block.String())))
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range cal.Events() {
+				events = appendEvent(events, event, timezones, calendarName, opts)
+			}
+		}
+		component = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !calendar {
+		return nil, fmt.Errorf("not an iCalendar document")
+	}
+	return events, nil
+}
+
+// wrapCalendar puts components into a VCALENDAR of their own
+func wrapCalendar(components string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + components + "END:VCALENDAR\r\n"
+}
@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// windowsZones maps common Windows time zone names, as written by Outlook
+// and Exchange, to IANA zones
+var windowsZones = map[string]string{
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Central European Standard Time": "Europe/Warsaw",
+	"Romance Standard Time":          "Europe/Paris",
+	"GMT Standard Time":              "Europe/London",
+	"E. Europe Standard Time":        "Europe/Chisinau",
+	"FLE Standard Time":              "Europe/Kiev",
+	"Eastern Standard Time":          "America/New_York",
+	"Central Standard Time":          "America/Chicago",
+	"Mountain Standard Time":         "America/Denver",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"India Standard Time":            "Asia/Kolkata",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"UTC":                            "UTC",
+}
+
+// timezoneMap indexes the VTIMEZONEs of a calendar by TZID
+func timezoneMap(cal *ics.Calendar) map[string]*ics.VTimezone {
+	timezones := make(map[string]*ics.VTimezone)
+	for _, component := range cal.Components {
+		if tz, ok := component.(*ics.VTimezone); ok {
+			if tzid := tz.GetProperty(ics.ComponentPropertyTzid); tzid != nil {
+				timezones[tzid.Value] = tz
+			}
+		}
+	}
+	return timezones
+}
+
+// resolveTimezone finds the Go location for a TZID: an IANA name, a prefixed
+// one like "/mozilla.org/20050126_1/Europe/Berlin", a Windows name, or the
+// X-LIC-LOCATION of the calendar's VTIMEZONE
+func resolveTimezone(tzid string, timezones map[string]*ics.VTimezone) (*time.Location, bool) {
+	tzid = strings.Trim(tzid, `"`)
+	candidates := []string{tzid, windowsZones[tzid]}
+
+	// Try ever shorter suffixes of prefixed names
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		candidates = append(candidates, strings.Join(parts[i:], "/"))
+	}
+
+	if tz, ok := timezones[tzid]; ok {
+		for _, prop := range tz.Properties {
+			if strings.EqualFold(prop.IANAToken, "X-LIC-LOCATION") {
+				candidates = append(candidates, prop.Value)
+			}
+		}
+	}
+
+	for _, name := range candidates {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, true
+		}
+	}
+	return nil, false
+}
+
+// propertyTime reads a date-time property in its own time zone: the zone of
+// its TZID, or FloatingLocation for floating times. ok is false for dates
+// and UTC times, which ics parses fine itself.
+func propertyTime(prop *ics.IANAProperty, timezones map[string]*ics.VTimezone) (t time.Time, floating bool, ok bool) {
+	if prop == nil || strings.HasSuffix(prop.Value, "Z") || !strings.Contains(prop.Value, "T") {
+		return time.Time{}, false, false
+	}
+
+	loc, floating := FloatingLocation, true
+	if tzids := prop.ICalParameters[string(ics.ParameterTzid)]; len(tzids) > 0 {
+		if resolved, found := resolveTimezone(tzids[0], timezones); found {
+			loc, floating = resolved, false
+		}
+		// An unknown zone is read like a floating time rather than dropping
+		// the event
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", prop.Value, loc)
+	return t, floating && err == nil, err == nil
+}
+
+// eventStart returns DTSTART in the event's own time zone
+func eventStart(event *ics.VEvent, timezones map[string]*ics.VTimezone) (time.Time, bool, error) {
+	if t, floating, ok := propertyTime(event.GetProperty(ics.ComponentPropertyDtStart), timezones); ok {
+		return t, floating, nil
+	}
+	t, err := event.GetStartAt()
+	return t, false, err
+}
@@ -0,0 +1,196 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// withFloatingLocation reads floating times in the named zone for the test
+func withFloatingLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("no time zone data for %s: %v", name, err)
+	}
+	saved := FloatingLocation
+	FloatingLocation = loc
+	t.Cleanup(func() { FloatingLocation = saved })
+	return loc
+}
+
+func TestParseFloatingTime(t *testing.T) {
+	for _, name := range []string{"America/New_York", "Europe/Berlin", "Asia/Tokyo"} {
+		t.Run(name, func(t *testing.T) {
+			loc := withFloatingLocation(t, name)
+			doc := calendar(vevent(
+				"UID:floating@test",
+				"DTSTART:20250710T090000",
+				"DTEND:20250710T100000",
+				"SUMMARY:Floating",
+			))
+
+			events, err := Parse(strings.NewReader(doc), "Personal")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			if !events[0].Floating {
+				t.Error("event without TZID not marked floating")
+			}
+			if want := time.Date(2025, 7, 10, 9, 0, 0, 0, loc); !events[0].Start.Equal(want) {
+				t.Errorf("Start = %v, want %v", events[0].Start.In(loc), want)
+			}
+		})
+	}
+}
+
+func TestParseUTCIsNotFloating(t *testing.T) {
+	withFloatingLocation(t, "America/New_York")
+	doc := calendar(vevent("UID:utc@test", "DTSTART:20250710T090000Z", "DTEND:20250710T100000Z"))
+
+	events, err := Parse(strings.NewReader(doc), "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[0].Floating {
+		t.Error("UTC event marked floating")
+	}
+	if want := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC); !events[0].Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", events[0].Start.UTC(), want)
+	}
+}
+
+// Floating recurring events keep their wall-clock time on both sides of a
+// DST change, as the offset they are read at changes
+func TestFloatingRecurrenceAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone  string
+		start string // DTSTART, the days before a change
+		days  []int  // Days of the month of the occurrences
+		utc   []int  // Their hour in UTC
+	}{
+		// DST starts on March 9, 2025
+		{"America/New_York", "20250308T090000", []int{8, 9, 10}, []int{14, 13, 13}},
+		// DST ends on November 2, 2025
+		{"America/New_York", "20251101T090000", []int{1, 2, 3}, []int{13, 14, 14}},
+		// DST starts on March 30, 2025
+		{"Europe/Berlin", "20250329T090000", []int{29, 30, 31}, []int{8, 7, 7}},
+		// DST ends on October 26, 2025
+		{"Europe/Berlin", "20251025T090000", []int{25, 26, 27}, []int{7, 8, 8}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.zone+" "+test.start, func(t *testing.T) {
+			loc := withFloatingLocation(t, test.zone)
+			doc := calendar(vevent(
+				"UID:daily@test",
+				"DTSTART:"+test.start,
+				"DTEND:"+strings.Replace(test.start, "T09", "T10", 1),
+				"RRULE:FREQ=DAILY;COUNT=3",
+			))
+			first, _ := time.ParseInLocation("20060102T150405", test.start, loc)
+
+			events, err := ParseWith(strings.NewReader(doc), "Personal", Options{Now: first.AddDate(0, 0, -1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != len(test.days) {
+				t.Fatalf("got %d occurrences, want %d", len(events), len(test.days))
+			}
+			for i, event := range events {
+				local := event.Start.In(loc)
+				if local.Day() != test.days[i] || local.Hour() != 9 || local.Minute() != 0 {
+					t.Errorf("occurrence %d at %v, want 09:00 on day %d", i, local, test.days[i])
+				}
+				if hour := event.Start.UTC().Hour(); hour != test.utc[i] {
+					t.Errorf("occurrence %d at %02d:00 UTC, want %02d:00", i, hour, test.utc[i])
+				}
+				if event.End.Sub(event.Start) != time.Hour {
+					t.Errorf("occurrence %d lasts %v, want 1h", i, event.End.Sub(event.Start))
+				}
+			}
+		})
+	}
+}
+
+func TestFormatFloatingTime(t *testing.T) {
+	loc := withFloatingLocation(t, "Europe/Berlin")
+	tests := []struct {
+		t        time.Time
+		floating bool
+		want     string
+	}{
+		{time.Date(2025, 1, 15, 9, 0, 0, 0, loc), true, "20250115T090000"},
+		{time.Date(2025, 7, 15, 9, 0, 0, 0, loc), true, "20250715T090000"},
+		// Written in the floating zone whatever zone the time is in
+		{time.Date(2025, 7, 15, 7, 0, 0, 0, time.UTC), true, "20250715T090000"},
+		{time.Date(2025, 7, 15, 9, 0, 0, 0, loc), false, "20250715T070000Z"},
+	}
+	for _, test := range tests {
+		if got := formatEventTime(test.t, test.floating); got != test.want {
+			t.Errorf("formatEventTime(%v, %v) = %s, want %s", test.t, test.floating, got, test.want)
+		}
+	}
+}
+
+// A floating event written back is read at the same wall-clock time, on
+// either side of a DST change
+func TestFloatingRoundTrip(t *testing.T) {
+	loc := withFloatingLocation(t, "America/New_York")
+	for _, start := range []time.Time{
+		time.Date(2025, 3, 8, 9, 0, 0, 0, loc),
+		time.Date(2025, 3, 10, 9, 0, 0, 0, loc),
+	} {
+		event := Event{UID: "roundtrip@test", Summary: "Floating", Start: start, End: start.Add(time.Hour), Floating: true}
+		doc := Build([]Event{event})
+		if strings.Contains(doc, start.UTC().Format("20060102T150405Z")) {
+			t.Errorf("floating event written in UTC:\n%s", doc)
+		}
+
+		events, err := Parse(strings.NewReader(doc), "Personal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || !events[0].Floating || !events[0].Start.Equal(start) {
+			t.Errorf("read back %+v, want floating at %v", events, start)
+		}
+	}
+}
+
+// An event with a TZID that can't be resolved is read like a floating time,
+// and keeps the TZID when it is moved
+func TestUnknownTimezoneKeptOnRewrite(t *testing.T) {
+	loc := withFloatingLocation(t, "Europe/Berlin")
+	doc := calendar(vevent(
+		"UID:unknown-zone@test",
+		"DTSTART;TZID=Custom/Nowhere:20250710T090000",
+		"DTEND;TZID=Custom/Nowhere:20250710T100000",
+		"SUMMARY:Somewhere",
+		"X-CUSTOM:kept",
+	))
+
+	events, err := Parse(strings.NewReader(doc), "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || !events[0].Floating {
+		t.Fatalf("got %+v, want one event read as floating", events)
+	}
+
+	moved := events[0]
+	moved.Start = time.Date(2025, 7, 11, 14, 0, 0, 0, loc)
+	moved.End = moved.Start.Add(time.Hour)
+	written := Build([]Event{moved})
+	for _, want := range []string{
+		"DTSTART;TZID=Custom/Nowhere:20250711T140000",
+		"DTEND;TZID=Custom/Nowhere:20250711T150000",
+		"X-CUSTOM:kept",
+	} {
+		if !strings.Contains(written, want) {
+			t.Errorf("written event lacks %s:\n%s", want, written)
+		}
+	}
+}
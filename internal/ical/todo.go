@@ -0,0 +1,77 @@
+package ical
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// Todo is an open VTODO, a task that can be planned into the calendar
+type Todo struct {
+	UID          string
+	Summary      string
+	Description  string
+	Due          time.Time     // Zero if the task has no due date
+	Estimate     time.Duration // DURATION, how long the task takes; zero if unknown
+	Priority     int           // 1 (highest) to 9, 0 if undefined
+	CalendarName string
+}
+
+// ParseTodos reads the VTODOs of an iCalendar document that are still to
+// do. Completed and cancelled tasks are skipped.
+func ParseTodos(reader io.Reader, calendarName string) ([]Todo, error) {
+	cal, err := ics.ParseCalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+	timezones := timezoneMap(cal)
+
+	var todos []Todo
+	for _, component := range cal.Components {
+		todo, ok := component.(*ics.VTodo)
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(propertyValue(&todo.ComponentBase, ics.ComponentPropertyStatus)) {
+		case "COMPLETED", "CANCELLED":
+			continue
+		}
+		if todo.GetProperty(ics.ComponentPropertyCompleted) != nil {
+			continue
+		}
+
+		due, _, ok := propertyTime(todo.GetProperty(ics.ComponentPropertyDue), timezones)
+		if !ok {
+			if due, err = todo.GetDueAt(); err != nil {
+				due, _ = todo.GetAllDayDueAt()
+			}
+		}
+		if !due.IsZero() {
+			due = due.In(time.Local)
+		}
+
+		var estimate time.Duration
+		if duration, err := ParseDuration(propertyValue(&todo.ComponentBase, ics.ComponentPropertyDuration)); err == nil && !duration.Negative {
+			estimate = time.Duration(duration.Days)*24*time.Hour + duration.Time
+		}
+		priority, _ := strconv.Atoi(propertyValue(&todo.ComponentBase, ics.ComponentPropertyPriority))
+
+		summary := propertyValue(&todo.ComponentBase, ics.ComponentPropertySummary)
+		if summary == "" {
+			summary = noTitle
+		}
+		todos = append(todos, Todo{
+			UID:          propertyValue(&todo.ComponentBase, ics.ComponentPropertyUniqueId),
+			Summary:      summary,
+			Description:  propertyValue(&todo.ComponentBase, ics.ComponentPropertyDescription),
+			Due:          due,
+			Estimate:     estimate,
+			Priority:     priority,
+			CalendarName: calendarName,
+		})
+	}
+	return todos, nil
+}
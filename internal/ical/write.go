@@ -0,0 +1,216 @@
+package ical
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// serialConfig matches the plain newlines used by Build
+var serialConfig = &ics.SerializationConfiguration{MaxLength: 75, PropertyMaxLength: 75, NewLine: "\n"}
+
+// Build serializes events into a VCALENDAR document
+func Build(events []Event) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//MyTuiCalendar//EN\n")
+	writtenTimezones := make(map[string]bool)
+	for _, event := range events {
+		if event.Raw != "" {
+			if merged, ok := mergeRaw(event, writtenTimezones); ok {
+				b.WriteString(merged)
+				continue
+			}
+		}
+		fmt.Fprintf(&b, `BEGIN:VEVENT
+UID:%s
+DTSTART:%s
+DTEND:%s
+SUMMARY:%s
+DESCRIPTION:%s
+`, event.UID,
+			formatEventTime(event.Start, event.Floating),
+			formatEventTime(event.End, event.Floating),
+			escapeValue(event.Summary),
+			escapeValue(event.Description))
+		if event.Location != "" {
+			b.WriteString("LOCATION:" + escapeValue(event.Location) + "\n")
+		}
+		if event.URL != "" {
+			// A URI value, which isn't escaped like text
+			b.WriteString("URL:" + event.URL + "\n")
+		}
+		if event.RRule != "" {
+			b.WriteString("RRULE:" + event.RRule + "\n")
+		}
+		if event.Organizer != "" {
+			b.WriteString("ORGANIZER:mailto:" + event.Organizer + "\n")
+		}
+		for _, attendee := range event.Attendees {
+			b.WriteString("ATTENDEE")
+			if name := event.AttendeeNames[attendee]; name != "" {
+				b.WriteString(";CN=" + paramValue(name))
+			}
+			b.WriteString(";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + attendee + "\n")
+		}
+		if event.Transp != "" {
+			b.WriteString("TRANSP:" + event.Transp + "\n")
+		}
+		if event.RelatedTo != "" {
+			b.WriteString("RELATED-TO:" + escapeValue(event.RelatedTo) + "\n")
+		}
+		if event.Sequence > 0 {
+			fmt.Fprintf(&b, "SEQUENCE:%d\n", event.Sequence)
+		}
+		if !event.LastModified.IsZero() {
+			b.WriteString("LAST-MODIFIED:" + event.LastModified.UTC().Format("20060102T150405Z") + "\n")
+		}
+		b.WriteString("END:VEVENT\n")
+	}
+	b.WriteString("END:VCALENDAR\n")
+	return b.String()
+}
+
+// mergeRaw applies the event's fields to its original component, touching
+// only the properties that changed so that alarms, attachments, categories
+// and X- properties written by other clients survive. It returns the
+// timezones not in written yet and the VEVENT, or false if the original
+// can't be parsed.
+func mergeRaw(event Event, written map[string]bool) (string, bool) {
+	cal, err := ics.ParseCalendar(strings.NewReader(event.Raw))
+	if err != nil || len(cal.Events()) != 1 {
+		return "", false
+	}
+	original := cal.Events()[0]
+	timezones := timezoneMap(cal)
+
+	// Occurrences of a recurring event carry their own start, so only move
+	// DTSTART/DTEND of single events
+	if event.RRule == "" {
+		start, _, err := eventStart(original, timezones)
+		if err != nil || !event.Start.Equal(start) {
+			setTime(original, ics.ComponentPropertyDtStart, event.Start, event.Floating)
+		}
+		if !event.End.Equal(eventEnd(original, start, timezones)) {
+			// Keep the original's choice of DURATION or DTEND
+			if original.GetProperty(ics.ComponentPropertyDuration) != nil {
+				original.SetProperty(ics.ComponentPropertyDuration, FormatDuration(event.Start, event.End))
+			} else {
+				setTime(original, ics.ComponentPropertyDtEnd, event.End, event.Floating)
+			}
+		}
+	}
+	if summary := propertyValue(&original.ComponentBase, ics.ComponentPropertySummary); event.Summary != summary && !(summary == "" && event.Summary == noTitle) {
+		original.SetSummary(event.Summary)
+	}
+	if event.Description != propertyValue(&original.ComponentBase, ics.ComponentPropertyDescription) {
+		original.SetDescription(event.Description)
+	}
+	if event.Location != propertyValue(&original.ComponentBase, ics.ComponentPropertyLocation) {
+		original.SetLocation(event.Location)
+	}
+	if event.URL != propertyValue(&original.ComponentBase, ics.ComponentPropertyUrl) {
+		original.RemoveProperty(ics.ComponentPropertyUrl)
+		if event.URL != "" {
+			original.SetURL(event.URL)
+		}
+	}
+	if event.RRule != propertyValue(&original.ComponentBase, ics.ComponentPropertyRrule) {
+		original.RemoveProperty(ics.ComponentPropertyRrule)
+		if event.RRule != "" {
+			original.AddRrule(event.RRule)
+		}
+	}
+	if event.Transp != propertyValue(&original.ComponentBase, ics.ComponentPropertyTransp) && event.Transp != "" {
+		original.SetProperty(ics.ComponentPropertyTransp, event.Transp)
+	}
+	if event.RelatedTo != propertyValue(&original.ComponentBase, ics.ComponentPropertyRelatedTo) {
+		original.RemoveProperty(ics.ComponentPropertyRelatedTo)
+		if event.RelatedTo != "" {
+			original.SetProperty(ics.ComponentPropertyRelatedTo, event.RelatedTo)
+		}
+	}
+	if strconv.Itoa(event.Sequence) != propertyValue(&original.ComponentBase, ics.ComponentPropertySequence) && event.Sequence > 0 {
+		original.SetSequence(event.Sequence)
+	}
+	if modified, _ := original.GetLastModifiedAt(); !event.LastModified.IsZero() && !event.LastModified.Equal(modified) {
+		original.SetLastModifiedAt(event.LastModified)
+	}
+
+	var b strings.Builder
+	for _, component := range cal.Components {
+		if tz, ok := component.(*ics.VTimezone); ok {
+			tzid := propertyValue(&tz.ComponentBase, ics.ComponentPropertyTzid)
+			if written[tzid] {
+				continue
+			}
+			written[tzid] = true
+		}
+		component.SerializeTo(&b, serialConfig)
+	}
+	return b.String(), true
+}
+
+// setTime replaces a date-time property with a UTC (or floating) value,
+// keeping all-day values as dates. A TZID that couldn't be resolved, whose
+// time was read like a floating one, is kept with the wall-clock time so
+// other clients still read it in that zone.
+func setTime(event *ics.VEvent, property ics.ComponentProperty, t time.Time, floating bool) {
+	if prop := event.GetProperty(property); prop != nil {
+		if values := prop.ICalParameters[string(ics.ParameterValue)]; len(values) == 1 && values[0] == "DATE" {
+			event.SetProperty(property, t.Format("20060102"), ics.WithValue(string(ics.ValueDataTypeDate)))
+			return
+		}
+		if tzids := prop.ICalParameters[string(ics.ParameterTzid)]; floating && len(tzids) > 0 {
+			event.SetProperty(property, formatEventTime(t, true), ics.WithTZID(tzids[0]))
+			return
+		}
+	}
+	event.SetProperty(property, formatEventTime(t, floating))
+}
+
+// formatEventTime formats a DTSTART or DTEND value. Floating times are
+// written as wall-clock time in FloatingLocation so they stay floating.
+func formatEventTime(t time.Time, floating bool) string {
+	if floating {
+		return t.In(FloatingLocation).Format("20060102T150405")
+	}
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func propertyValue(component *ics.ComponentBase, property ics.ComponentProperty) string {
+	if prop := component.GetProperty(property); prop != nil {
+		return prop.Value
+	}
+	return ""
+}
+
+// NewUID generates a unique UID for a new event. The random suffix keeps
+// UIDs distinct when several events are created within the same second.
+func NewUID() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%x@mytuicalendar", time.Now().UTC().Format("20060102T150405Z"), suffix)
+}
+
+// paramValue quotes a parameter value if it contains separators. Quotes
+// can't be escaped and are dropped.
+func paramValue(value string) string {
+	value = strings.ReplaceAll(value, "\"", "")
+	if strings.ContainsAny(value, ":;,") {
+		return "\"" + value + "\""
+	}
+	return value
+}
+
+// escapeValue escapes a TEXT property value
+func escapeValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, ",", "\\,")
+	value = strings.ReplaceAll(value, ";", "\\;")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return value
+}
@@ -0,0 +1,178 @@
+// Package oauth implements the OAuth 2.0 device authorization grant (RFC
+// 8628), which lets a terminal app be authorized from a browser on any
+// device, and token refresh.
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Token is an OAuth access token with the refresh token to renew it
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Valid reports whether the access token can still be used for a minute
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && time.Now().Add(time.Minute).Before(t.Expiry)
+}
+
+// DeviceCode is what the user needs to authorize this device
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// Client is an OAuth client of a provider's device flow. ClientSecret is
+// empty for public clients.
+type Client struct {
+	HTTP         *http.Client
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	DeviceURL    string // Device authorization endpoint
+	TokenURL     string
+}
+
+// tokenResponse is the token endpoint's answer
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// StartDevice requests a code for the user to enter at the verification URI
+func (c *Client) StartDevice() (*DeviceCode, error) {
+	var code struct {
+		DeviceCode
+		VerificationURL string `json:"verification_url"` // Google's name for verification_uri
+	}
+	err := c.post(c.DeviceURL, url.Values{
+		"client_id": {c.ClientID},
+		"scope":     {c.Scope},
+	}, &code)
+	if err != nil {
+		return nil, err
+	}
+	if code.VerificationURI == "" {
+		code.VerificationURI = code.VerificationURL
+	}
+	if code.Interval <= 0 {
+		code.Interval = 5
+	}
+	return &code.DeviceCode, nil
+}
+
+// WaitForToken polls until the user has authorized the device code, or it
+// expires or is denied
+func (c *Client) WaitForToken(code *DeviceCode) (*Token, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		token, err := c.requestToken(url.Values{
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		})
+		var oauthErr *Error
+		switch {
+		case err == nil:
+			return token, nil
+		case errors.As(err, &oauthErr) && oauthErr.Code == "authorization_pending":
+			continue
+		case errors.As(err, &oauthErr) && oauthErr.Code == "slow_down":
+			interval += 5 * time.Second
+			continue
+		default:
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("the device code expired before it was authorized")
+}
+
+// Refresh renews an expired access token
+func (c *Client) Refresh(token *Token) (*Token, error) {
+	if token == nil || token.RefreshToken == "" {
+		return nil, fmt.Errorf("not logged in")
+	}
+	renewed, err := c.requestToken(url.Values{
+		"refresh_token": {token.RefreshToken},
+		"grant_type":    {"refresh_token"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Some providers only send a refresh token with the first authorization
+	if renewed.RefreshToken == "" {
+		renewed.RefreshToken = token.RefreshToken
+	}
+	return renewed, nil
+}
+
+// Error is an error answer of the OAuth server, e.g. "access_denied"
+type Error struct {
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+func (c *Client) requestToken(form url.Values) (*Token, error) {
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	var resp tokenResponse
+	if err := c.post(c.TokenURL, form, &resp); err != nil {
+		return nil, err
+	}
+	return &Token{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// post sends a form and decodes the JSON answer into v. OAuth errors come
+// back as 400s with an "error" field and are returned as *Error.
+func (c *Client) post(endpoint string, form url.Values, v any) error {
+	resp, err := c.HTTP.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("invalid response from %s (%s): %v", endpoint, resp.Status, err)
+	}
+	if err := json.Unmarshal(raw, &body); err == nil && body.Error != "" {
+		return &Error{Code: body.Error, Description: body.Description}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed: %s", endpoint, resp.Status)
+	}
+	return json.Unmarshal(raw, v)
+}
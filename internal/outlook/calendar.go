@@ -0,0 +1,185 @@
+// Package outlook reads Exchange Online calendars through the Microsoft
+// Graph API, authorizing with the OAuth device flow.
+package outlook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/oauth"
+)
+
+const graphURL = "https://graph.microsoft.com/v1.0"
+
+// OAuth returns the device flow client for read-only calendar access. The
+// app registration must allow public client flows. tenant is the directory
+// ID or domain, or "organizations" for any work account.
+func OAuth(client *http.Client, clientID, tenant string) *oauth.Client {
+	if tenant == "" {
+		tenant = "organizations"
+	}
+	authority := "https://login.microsoftonline.com/" + url.PathEscape(tenant) + "/oauth2/v2.0"
+	return &oauth.Client{
+		HTTP:      client,
+		ClientID:  clientID,
+		Scope:     "offline_access Calendars.Read",
+		DeviceURL: authority + "/devicecode",
+		TokenURL:  authority + "/token",
+	}
+}
+
+// Client calls the Graph API with an access token
+type Client struct {
+	HTTP  *http.Client
+	Token *oauth.Token
+}
+
+// Calendar is one of the mailbox's calendars
+type Calendar struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	IsDefaultCalendar bool   `json:"isDefaultCalendar"`
+}
+
+// DateTime is a Graph dateTimeTimeZone. Events are requested in UTC, so
+// DateTime is a UTC wall-clock time without offset.
+type DateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// Time returns the time in loc
+func (t DateTime) Time(loc *time.Location) (time.Time, error) {
+	parsed, err := time.ParseInLocation("2006-01-02T15:04:05.9999999", t.DateTime, time.UTC)
+	return parsed.In(loc), err
+}
+
+// Recipient is an organizer or attendee
+type Recipient struct {
+	EmailAddress struct {
+		Address string `json:"address"`
+	} `json:"emailAddress"`
+}
+
+// Event is a Graph event. Events come from calendarView, which expands
+// recurring events into occurrences.
+type Event struct {
+	ID          string `json:"id"`
+	ICalUID     string `json:"iCalUId"`
+	Subject     string `json:"subject"`
+	BodyPreview string `json:"bodyPreview"`
+	Location    struct {
+		DisplayName string `json:"displayName"`
+	} `json:"location"`
+	Start                DateTime    `json:"start"`
+	End                  DateTime    `json:"end"`
+	IsAllDay             bool        `json:"isAllDay"`
+	IsCancelled          bool        `json:"isCancelled"`
+	ShowAs               string      `json:"showAs"` // "free", "tentative", "busy", "oof", "workingElsewhere"
+	Organizer            Recipient   `json:"organizer"`
+	Attendees            []Recipient `json:"attendees"`
+	LastModifiedDateTime time.Time   `json:"lastModifiedDateTime"`
+}
+
+// Times returns the event's start and end in loc. All-day events are
+// dates, which start at midnight in loc rather than in UTC.
+func (e *Event) Times(loc *time.Location) (time.Time, time.Time, error) {
+	if e.IsAllDay && len(e.Start.DateTime) >= 10 && len(e.End.DateTime) >= 10 {
+		start, err := time.ParseInLocation("2006-01-02", e.Start.DateTime[:10], loc)
+		if err != nil {
+			return start, start, err
+		}
+		end, err := time.ParseInLocation("2006-01-02", e.End.DateTime[:10], loc)
+		return start, end, err
+	}
+	start, err := e.Start.Time(loc)
+	if err != nil {
+		return start, start, err
+	}
+	end, err := e.End.Time(loc)
+	return start, end, err
+}
+
+// Calendars lists the calendars of the signed-in user's mailbox
+func (c *Client) Calendars() ([]Calendar, error) {
+	var calendars []Calendar
+	err := c.pages(graphURL+"/me/calendars", func(raw json.RawMessage) error {
+		var page []Calendar
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		calendars = append(calendars, page...)
+		return nil
+	})
+	return calendars, err
+}
+
+// Events returns the occurrences of a calendar's events that overlap
+// [from, to). An empty calendarID reads the default calendar.
+func (c *Client) Events(calendarID string, from, to time.Time) ([]Event, error) {
+	path := "/me/calendarView"
+	if calendarID != "" {
+		path = "/me/calendars/" + url.PathEscape(calendarID) + "/calendarView"
+	}
+	query := url.Values{
+		"startDateTime": {from.UTC().Format(time.RFC3339)},
+		"endDateTime":   {to.UTC().Format(time.RFC3339)},
+		"$top":          {"500"},
+	}
+
+	var events []Event
+	err := c.pages(graphURL+path+"?"+query.Encode(), func(raw json.RawMessage) error {
+		var page []Event
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		events = append(events, page...)
+		return nil
+	})
+	return events, err
+}
+
+// pages requests a collection and follows its @odata.nextLink, passing each
+// page's value array to add
+func (c *Client) pages(endpoint string, add func(json.RawMessage) error) error {
+	for endpoint != "" {
+		var page struct {
+			Value    json.RawMessage `json:"value"`
+			NextLink string          `json:"@odata.nextLink"`
+		}
+		if err := c.get(endpoint, &page); err != nil {
+			return err
+		}
+		if err := add(page.Value); err != nil {
+			return err
+		}
+		endpoint = page.NextLink
+	}
+	return nil
+}
+
+func (c *Client) get(endpoint string, v any) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token.AccessToken)
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Microsoft Graph: %s - %s", resp.Status, strings.TrimSpace(string(body[:min(200, len(body))])))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
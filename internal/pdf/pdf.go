@@ -0,0 +1,181 @@
+// Package pdf writes simple PDF documents: pages of text in the standard
+// Helvetica fonts, lines and rectangles. Nothing is embedded, so the
+// documents stay small and print anywhere.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The size of a landscape A4 page in points
+const A4Width, A4Height = 842, 595
+
+// Font is one of the standard fonts every PDF reader has
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// Color is an RGB color, each component from 0 to 255
+type Color struct{ R, G, B uint8 }
+
+// Document is a PDF document built page by page
+type Document struct {
+	pages []*Page
+}
+
+// Page is a page of a Document. Coordinates are in points from the top
+// left corner, y growing downwards.
+type Page struct {
+	width, height float64
+	content       bytes.Buffer
+}
+
+// AddPage appends a page of the given size in points
+func (d *Document) AddPage(width, height float64) *Page {
+	page := &Page{width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Rect draws a rectangle, filled with fill if not nil and outlined with a
+// line of the given width if stroke is not nil
+func (p *Page) Rect(x, y, width, height float64, fill, stroke *Color, lineWidth float64) {
+	if fill == nil && stroke == nil {
+		return
+	}
+	op := "B"
+	switch {
+	case fill == nil:
+		op = "S"
+	case stroke == nil:
+		op = "f"
+	}
+	p.setColors(fill, stroke, lineWidth)
+	fmt.Fprintf(&p.content, "%s %s %s %s re %s\n", num(x), num(p.height-y-height), num(width), num(height), op)
+}
+
+// Line draws a line from (x1, y1) to (x2, y2)
+func (p *Page) Line(x1, y1, x2, y2 float64, color Color, lineWidth float64) {
+	p.setColors(nil, &color, lineWidth)
+	fmt.Fprintf(&p.content, "%s %s m %s %s l S\n", num(x1), num(p.height-y1), num(x2), num(p.height-y2))
+}
+
+// Text writes text with its baseline at y. Characters outside Windows-1252
+// are left out.
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, text string) {
+	fmt.Fprintf(&p.content, "%s rg\nBT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		rgb(color), int(font)+1, num(size), num(x), num(p.height-y), escape(encode(text)))
+}
+
+func (p *Page) setColors(fill, stroke *Color, lineWidth float64) {
+	if fill != nil {
+		fmt.Fprintf(&p.content, "%s rg\n", rgb(*fill))
+	}
+	if stroke != nil {
+		fmt.Fprintf(&p.content, "%s RG %s w\n", rgb(*stroke), num(lineWidth))
+	}
+}
+
+// TextWidth returns the width of text in points
+func TextWidth(font Font, size float64, text string) float64 {
+	width := 0
+	for _, c := range []byte(encode(text)) {
+		width += glyphWidth(font, c)
+	}
+	return float64(width) * size / 1000
+}
+
+// Truncate shortens text with "..." to fit width points
+func Truncate(font Font, size float64, text string, width float64) string {
+	if TextWidth(font, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		short := strings.TrimRight(string(runes), " ") + "..."
+		if TextWidth(font, size, short) <= width {
+			return short
+		}
+	}
+	return ""
+}
+
+// WriteTo writes the document as PDF
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are the catalog, the page tree and the fonts, then
+	// each page and its content
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(page.width), num(page.height), 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.WriteTo(w)
+}
+
+// num formats a coordinate without needless digits
+func num(f float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", f), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func rgb(c Color) string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// escape escapes the delimiters of a PDF string
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// winAnsi are the characters of Windows-1252 outside Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode converts text to Windows-1252, the encoding of the fonts,
+// dropping what it can't represent such as emoji
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		}
+	}
+	return b.String()
+}
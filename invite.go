@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/ical"
+)
+
+// invitePreview shows the events piped in with --stdin, e.g. an invitation
+// opened from a mail client, until they are accepted into a calendar or
+// dismissed
+type invitePreview struct {
+	events   []Event // One per UID, recurring events by their first occurrence
+	calendar string  // Calendar to accept them into
+}
+
+// readInvite parses the events of an iCalendar document, named as the
+// "Invitation" calendar until accepted
+func readInvite(reader io.Reader) ([]Event, error) {
+	setCalendarColors(map[string]lipgloss.Color{tr("Invitation"): lipgloss.Color("205")})
+	parsed, err := ical.Parse(reader, tr("Invitation"))
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var events []Event
+	for _, event := range parsed {
+		if event.UID != "" && seen[event.UID] {
+			continue
+		}
+		seen[event.UID] = true
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("no events in the input")
+	}
+	return events, nil
+}
+
+// inviteCalendars are the calendars an invitation can be accepted into
+func (m model) inviteCalendars() []string {
+	var names []string
+	for _, name := range m.sortedCalendarNames() {
+		if m.calendarURLs[name] != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// inviteEvents are the invitation's events as they would be accepted
+func (m model) inviteEvents() []Event {
+	events := make([]Event, len(m.invite.events))
+	for i, event := range m.invite.events {
+		if m.invite.calendar != "" {
+			event.CalendarName = m.invite.calendar
+		}
+		events[i] = event
+	}
+	return events
+}
+
+// handleInviteKey chooses the calendar with tab, accepts with a or enter
+// and dismisses with esc or q
+func (m model) handleInviteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	calendars := m.inviteCalendars()
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc", "q":
+		m.invite = nil
+		m.message = tr("Invitation dismissed")
+	case "tab", "shift+tab":
+		if len(calendars) == 0 {
+			return m, nil
+		}
+		i := 0
+		for j, name := range calendars {
+			if name == m.invite.calendar {
+				i = j
+			}
+		}
+		if msg.String() == "tab" {
+			i = (i + 1) % len(calendars)
+		} else {
+			i = (i + len(calendars) - 1) % len(calendars)
+		}
+		invite := *m.invite
+		invite.calendar = calendars[i]
+		m.invite = &invite
+	case "a", "enter":
+		if m.invite.calendar == "" {
+			m.message = tr("No writable calendar to accept the invitation into")
+			return m, nil
+		}
+		var events []*Event
+		for _, event := range m.inviteEvents() {
+			events = append(events, &event)
+		}
+		m.invite = nil
+		m.viewMode = DailyView
+		m.currentDate = dayStart(events[0].Start)
+		m.cursor = 0
+		return m.saveNewEvents(events)
+	}
+	return m, nil
+}
+
+// viewInvite renders the invitation's events and what else is on their day
+func (m model) viewInvite() string {
+	width := 80
+	if m.width > 0 {
+		width = min(100, m.width)
+	}
+	events := m.inviteEvents()
+
+	var b strings.Builder
+	b.WriteString(m.renderTitle(tr("📨 Invitation"), formatDate(events[0].Start, "Mon Jan 2, 2006")))
+	for _, event := range events {
+		b.WriteString(m.renderDetail(event, width) + "\n")
+	}
+
+	// The day it falls on, with what it overlaps
+	day := events[0].Start
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf(tr("On %s"), formatDate(day, "Mon Jan 2"))) + "\n")
+	dayEvents := m.getEventsForDay(day)
+	if len(dayEvents) == 0 {
+		b.WriteString(noEventsStyle.Render(tr("No events scheduled for this day")) + "\n")
+	}
+	overlapStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	for _, existing := range dayEvents {
+		line := m.fitLine(timeRange(existing)+"  "+eventMarker(existing)+m.eventTitle(existing), 2)
+		overlaps := false
+		for _, event := range events {
+			// Accepted before, it doesn't overlap itself
+			if existing.UID != event.UID && busy(existing) && busy(event) && existing.Start.Before(event.End) && event.Start.Before(existing.End) {
+				overlaps = true
+			}
+		}
+		if overlaps {
+			b.WriteString(" " + overlapStyle.Render(line+" ⚠") + "\n")
+		} else {
+			b.WriteString(" " + lipgloss.NewStyle().Foreground(eventColor(existing)).Render(line) + "\n")
+		}
+	}
+
+	if m.oneShot {
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	if m.message != "" {
+		b.WriteString("\n" + helpStyle.Render(m.message))
+	}
+	accept := tr("No writable calendar to accept the invitation into")
+	if m.invite.calendar != "" {
+		accept = fmt.Sprintf(tr("a: accept into %s"), m.invite.calendar)
+	}
+	b.WriteString("\n" + renderHelp([]string{accept, "tab: calendar", "esc: dismiss"}))
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// eventJSON is an event in --json output
+type eventJSON struct {
+	Summary     string    `json:"summary"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"all_day"`
+	Calendar    string    `json:"calendar"`
+	Location    string    `json:"location,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Description string    `json:"description,omitempty"`
+	UID         string    `json:"uid,omitempty"`
+	Organizer   string    `json:"organizer,omitempty"`
+	Attendees   []string  `json:"attendees,omitempty"`
+}
+
+// renderEventsJSON renders events for scripts (--json), as an array even
+// for a single event
+func renderEventsJSON(events []Event) (string, error) {
+	out := make([]eventJSON, 0, len(events))
+	for _, event := range events {
+		out = append(out, eventJSON{
+			Summary:     event.Summary,
+			Start:       event.Start,
+			End:         event.End,
+			AllDay:      isAllDay(event),
+			Calendar:    event.CalendarName,
+			Location:    event.Location,
+			URL:         event.URL,
+			Description: event.Description,
+			UID:         event.UID,
+			Organizer:   event.Organizer,
+			Attendees:   event.Attendees,
+		})
+	}
+	// URLs keep their "&" instead of "\u0026"
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
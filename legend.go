@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarGroup returns the group a calendar is in, "" if none
+func (c *Config) calendarGroup(name string) string {
+	if c == nil {
+		return ""
+	}
+	for _, cal := range c.Calendars {
+		if cal.Name == name {
+			return cal.Group
+		}
+	}
+	return ""
+}
+
+// sortedCalendarNames returns the calendars in the configured order: as
+// listed in the config, followed by the others (such as discovered Radicale
+// calendars) alphabetically, or all alphabetically. Calendars without a
+// group come first, and the calendars of a group stay together.
+func (m model) sortedCalendarNames() []string {
+	calNames := make([]string, 0, len(m.calendars))
+	for name := range m.calendars {
+		calNames = append(calNames, name)
+	}
+	sort.Strings(calNames)
+	if m.config == nil {
+		return calNames
+	}
+
+	if m.config.CalendarOrder != "alphabetical" {
+		position := make(map[string]int)
+		for i, cal := range m.config.Calendars {
+			if _, ok := position[cal.Name]; !ok {
+				position[cal.Name] = i
+			}
+		}
+		sort.SliceStable(calNames, func(i, j int) bool {
+			pi, iListed := position[calNames[i]]
+			pj, jListed := position[calNames[j]]
+			if iListed != jListed {
+				return iListed
+			}
+			return iListed && pi < pj
+		})
+	}
+
+	// Groups in the order of their first calendar
+	groupRank := map[string]int{"": 0}
+	for _, name := range calNames {
+		if group := m.config.calendarGroup(name); group != "" {
+			if _, ok := groupRank[group]; !ok {
+				groupRank[group] = len(groupRank)
+			}
+		}
+	}
+	sort.SliceStable(calNames, func(i, j int) bool {
+		return groupRank[m.config.calendarGroup(calNames[i])] < groupRank[m.config.calendarGroup(calNames[j])]
+	})
+	return calNames
+}
+
+// calendarGroups returns the groups of the loaded calendars in calendar order
+func (m model) calendarGroups() []string {
+	var groups []string
+	for _, name := range m.sortedCalendarNames() {
+		if group := m.config.calendarGroup(name); group != "" && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// groupHidden reports whether a calendar's group is hidden (G)
+func (m model) groupHidden(calendar string) bool {
+	return len(m.hiddenGroups) > 0 && m.hiddenGroups[m.config.calendarGroup(calendar)]
+}
+
+// calendarHidden reports whether a calendar is hidden, by its number (1-9)
+// or with its group
+func (m model) calendarHidden(calendar string) bool {
+	return m.hiddenCalendars[calendar] || m.groupHidden(calendar)
+}
+
+// toggleCalendar shows or hides the calendar numbered n in the legend (1-9)
+func (m model) toggleCalendar(n int) model {
+	names := m.sortedCalendarNames()
+	if n < 1 || n > len(names) {
+		return m
+	}
+	name := names[n-1]
+	if m.hiddenCalendars[name] {
+		delete(m.hiddenCalendars, name)
+		m.message = fmt.Sprintf(tr("Showing %s"), name)
+	} else {
+		if m.hiddenCalendars == nil {
+			m.hiddenCalendars = make(map[string]bool)
+		}
+		m.hiddenCalendars[name] = true
+		m.message = fmt.Sprintf(tr("Hiding %s"), name)
+	}
+	m.cursor = 0
+	return m
+}
+
+func (m model) renderCalendarLegend() string {
+	var b strings.Builder
+	b.WriteString(calendarLabelStyle.Render(tr("Calendars:")) + "\n")
+	group := ""
+	for i, name := range m.sortedCalendarNames() {
+		color := m.calendars[name]
+		// Each group is labelled once, before its first calendar
+		if g := m.config.calendarGroup(name); g != group {
+			group = g
+			label := group + ":"
+			if m.hiddenGroups[group] {
+				label = fmt.Sprintf(tr("%s (hidden):"), group)
+			}
+			b.WriteString(" " + noEventsStyle.Render(label))
+		}
+		if m.calendarHidden(name) {
+			color = lipgloss.Color("241")
+		}
+		legendStyle := lipgloss.NewStyle().
+			Foreground(color).
+			Padding(0, 1)
+		// Numbered for toggling with 1-9
+		label := fmt.Sprintf("● %s", name)
+		if i < 9 {
+			label = fmt.Sprintf("%d %s", i+1, label)
+		}
+		b.WriteString(legendStyle.Render(label))
+	}
+	return b.String()
+}
+
+// startGroupToggle asks which calendar group to show or hide (G)
+func (m model) startGroupToggle() model {
+	if len(m.calendarGroups()) == 0 {
+		m.message = tr("Set a group on calendars in the config first")
+		return m
+	}
+	m.groupPrompt = true
+	return m
+}
+
+// groupTogglePrompt numbers the groups for handleGroupKey
+func (m model) groupTogglePrompt() string {
+	parts := []string{tr("Show/hide group:")}
+	for i, group := range m.calendarGroups() {
+		if i == 9 {
+			break
+		}
+		entry := fmt.Sprintf("%d %s", i+1, group)
+		if m.hiddenGroups[group] {
+			entry = fmt.Sprintf(tr("%d %s (hidden)"), i+1, group)
+		}
+		parts = append(parts, entry)
+	}
+	return strings.Join(parts, "  ") + "  " + tr("esc: done")
+}
+
+// handleGroupKey toggles the group of the number pressed, until esc
+func (m model) handleGroupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc", "enter", "G":
+		m.groupPrompt = false
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		groups := m.calendarGroups()
+		if i := int(key[0] - '1'); i < len(groups) {
+			if m.hiddenGroups == nil {
+				m.hiddenGroups = make(map[string]bool)
+			}
+			if m.hiddenGroups[groups[i]] {
+				delete(m.hiddenGroups, groups[i])
+			} else {
+				m.hiddenGroups[groups[i]] = true
+			}
+			m.cursor = 0
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+const (
+	defaultLowMemoryDays = 90
+	// Days around the viewed date any view may show, e.g. the month grid
+	lowMemoryMargin = 42
+)
+
+// lowMemoryDays returns the days held either side of the viewed date in
+// low memory mode, 0 if the mode is off
+func (c *Config) lowMemoryDays() int {
+	if c == nil || c.LowMemory == nil {
+		return 0
+	}
+	// Smaller windows would reload on every step
+	return max(cmp.Or(c.LowMemory.WindowDays, defaultLowMemoryDays), lowMemoryMargin+7)
+}
+
+// parseOptions returns how a calendar's ICS documents are parsed: whole, or
+// in low memory mode one event at a time, limited to [from, to) and without
+// the source of events that are never written back. Calendars with a
+// window_days are limited to [from, to) too.
+func parseOptions(config *Config, cal CalendarConfig, from, to, now time.Time, writable bool) ical.Options {
+	opts := ical.Options{Now: now, Timing: startupProfile.parseTiming()}
+	if cal.WindowDays > 0 {
+		opts.From, opts.To = from, to
+	}
+	if config.lowMemoryDays() > 0 {
+		opts.From, opts.To = from, to
+		opts.Stream = true
+		opts.NoRaw = !writable
+		opts.Strings = ical.Strings{}
+	}
+	return opts
+}
+
+// followView reloads the calendars around the viewed date in low memory
+// mode, once the view could reach past the events held
+func (m model) followView() (model, tea.Cmd) {
+	days := m.config.lowMemoryDays()
+	if days == 0 || m.oneShot || m.isLoading {
+		return m, nil
+	}
+	center := m.loadCenter
+	if center.IsZero() {
+		center = m.clock.Now()
+	}
+	if offset := dayIndex(center, m.currentDate); offset > -(days-lowMemoryMargin) && offset < days-lowMemoryMargin {
+		return m, nil
+	}
+	m.loadCenter = m.currentDate
+	return m, loadCalendarsCmd(m.clock, m.radicaleConfig, m.loadCenter)
+}
+
+// memoryStats summarizes memory use for --debug
+func memoryStats(events int) string {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return fmt.Sprintf("heap %.1f MB, sys %.1f MB, %d GCs, %d events",
+		float64(stats.HeapAlloc)/(1<<20), float64(stats.Sys)/(1<<20), stats.NumGC, events)
+}
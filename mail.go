@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultMailSubject = `Reminder: {{.Summary}} at {{.Start.Format "15:04"}}`
+	defaultMailBody    = `{{.Summary}}
+{{.Start.Format "Mon Jan 2, 15:04"}} - {{.End.Format "15:04"}} ({{.Calendar}})
+{{- if .Location}}
+Location: {{.Location}}{{end}}
+{{- if .Description}}
+
+{{.Description}}{{end}}
+`
+)
+
+// reminderMail is the data of the subject and body templates
+type reminderMail struct {
+	Summary     string
+	Start       time.Time
+	End         time.Time
+	Calendar    string
+	Location    string
+	Description string
+	Minutes     int // Until the start, when the mail is sent
+}
+
+// reminderMailer mails reminders as configured in reminders.email
+type reminderMailer struct {
+	config  ReminderEmailConfig
+	to      []string
+	subject *template.Template
+	body    *template.Template
+}
+
+// newReminderMailer checks the email config and parses its templates. It
+// returns nil without error if no email is configured.
+func newReminderMailer(config *Config) (*reminderMailer, error) {
+	if config == nil || config.Reminders == nil || config.Reminders.Email == nil {
+		return nil, nil
+	}
+	email := *config.Reminders.Email
+	if email.Host == "" || email.From == "" {
+		return nil, fmt.Errorf("reminder email needs a host and a from address")
+	}
+
+	to := email.To
+	if len(to) == 0 && len(config.Emails) > 0 {
+		to = config.Emails[:1]
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("reminder email needs a to address (or emails)")
+	}
+
+	subject, err := template.New("subject").Parse(cmp.Or(email.Subject, defaultMailSubject))
+	if err != nil {
+		return nil, fmt.Errorf("reminder email subject: %v", err)
+	}
+	body, err := template.New("body").Parse(cmp.Or(email.Body, defaultMailBody))
+	if err != nil {
+		return nil, fmt.Errorf("reminder email body: %v", err)
+	}
+	return &reminderMailer{config: email, to: to, subject: subject, body: body}, nil
+}
+
+// wants reports whether reminders of the event's calendar are mailed
+func (m *reminderMailer) wants(event Event) bool {
+	return len(m.config.Calendars) == 0 || slices.Contains(m.config.Calendars, event.CalendarName)
+}
+
+// send mails the reminder for an event
+func (m *reminderMailer) send(event Event, now time.Time) error {
+	data := reminderMail{
+		Summary:     event.Summary,
+		Start:       event.Start,
+		End:         event.End,
+		Calendar:    event.CalendarName,
+		Location:    event.Location,
+		Description: event.Description,
+		Minutes:     int(event.Start.Sub(now).Minutes()),
+	}
+	var subject, body bytes.Buffer
+	if err := m.subject.Execute(&subject, data); err != nil {
+		return err
+	}
+	if err := m.body.Execute(&body, data); err != nil {
+		return err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.to, ", "))
+	// Newlines in a header would start a new one
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+
+	return m.deliver(msg.Bytes())
+}
+
+// deliver sends a message through the configured server
+func (m *reminderMailer) deliver(msg []byte) error {
+	port := m.config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	if port != 465 {
+		// Upgrades to TLS with STARTTLS when the server offers it
+		return smtp.SendMail(addr, auth, m.config.From, m.to, msg)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: m.config.Host})
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.config.From); err != nil {
+		return err
+	}
+	for _, to := range m.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -3,58 +3,511 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func main() {
+	started := time.Now()
+	if len(os.Args) > 1 {
+		if cmd := findSubcommand(os.Args[1]); cmd != nil {
+			cmd.run(os.Args[2:])
+			return
+		}
+	}
+
 	//TODO: Flag "--tomorrow" -> Show tomorrow at a glance
-	nextFlag := flag.Bool("next", false, "Show next upcoming event and quit")
-	dayFlag := flag.Bool("day", false, "Show daily view and quit")
-	weekFlag := flag.Bool("week", false, "Show weekly view and quit")
-	monthFlag := flag.Bool("month", false, "Show monthly view and quit")
+	var opts runOptions
+	flag.Var(&opts.next, "next", "Show the next N upcoming events and quit (default 1)")
+	flag.Var(&opts.upcoming, "upcoming", "List the events starting within this span (e.g. 7d, 2w or 12h) and quit")
+	flag.BoolVar(&opts.humanize, "humanize", false, "With --upcoming, group by day with relative dates (\"Tomorrow\", \"in 3 days\")")
+	flag.StringVar(&opts.writeAgenda, "write-agenda", "", "Write today's agenda to `FILE` for widgets, as text or with --json as JSON, and quit")
+	flag.BoolVar(&opts.watch, "watch", false, "With --write-agenda, keep running and rewrite the file on each refresh and at midnight")
+	flag.BoolVar(&opts.day, "day", false, "Show daily view and quit")
+	flag.BoolVar(&opts.week, "week", false, "Show weekly view and quit")
+	flag.BoolVar(&opts.month, "month", false, "Show monthly view and quit")
+	flag.BoolVar(&opts.rolling, "rolling", false, "Show the rolling weeks view and quit")
+	flag.IntVar(&opts.free, "free", 0, "List free slots within working hours for the next N days and quit")
+	flag.IntVar(&opts.freeBusy, "freebusy", 0, "Print a VFREEBUSY of the next N days for publishing and quit")
+	flag.BoolVar(&opts.motd, "motd", false, "Print today's events and the next one from the cache, for a shell greeting, and quit")
+	flag.BoolVar(&opts.changes, "changes", false, "Show events added, changed or cancelled since the last run and quit")
+	flag.Var(&opts.exportMD, "export-md", "Print the events of the week, or of a range (day, week, month or a span like 2w), as a Markdown table and quit")
+	flag.Var(&opts.exportHTML, "export-html", "Print the events of the week, or of a range like --export-md, as a standalone HTML page and quit")
+	flag.StringVar(&opts.printMonth, "print-month", "", "Write a printable calendar of `MONTH` (e.g. 2025-06) as PDF and quit")
+	flag.StringVar(&opts.publish, "publish", "", "Write a static site of the coming months, masked as set in the publish config, to `DIR` and quit")
+	stdinFlag := flag.Bool("stdin", false, "Preview the events of ICS data read from stdin, e.g. an invitation, to accept them into a calendar")
+	flag.StringVar(&opts.openUID, "open-uid", "", "Start on the day of the event with this UID, with the cursor on it")
+	nextFlags(flag.CommandLine, &opts)
+	outputFlag(flag.CommandLine, &opts)
+	calendarFilterFlags(flag.CommandLine, &opts)
+	debugFlags(flag.CommandLine, &opts)
+	// Hidden: the candidates of the shell completions
+	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+		runCompleteCommand(os.Args[2:])
+		return
+	}
+	flag.Usage = usage
 	flag.Parse()
+	parseNextCount(&opts.next)
+	parseExportRange(&opts.exportMD)
+	parseExportRange(&opts.exportHTML)
+
+	var input io.Reader
+	if *stdinFlag {
+		input = os.Stdin
+	}
+	run(&opts, input, started)
+}
+
+// runOptions are the flags of the calendar, set by the flags of the
+// calendar itself or by the commands that run it
+type runOptions struct {
+	next                        nextCountFlag
+	within                      time.Duration
+	upcoming                    spanFlag
+	humanize, json, pinned      bool
+	writeAgenda                 string
+	watch                       bool
+	maxWidth                    int
+	day, week, month, rolling   bool
+	free, freeBusy              int
+	motd, changes               bool
+	exportMD, exportHTML        exportFlag
+	printMonth, output, publish string
+	openUID                     string
+	debug, profile              bool
+	pprof                       string
+	calendar, excludeCalendar   stringListFlag
+}
+
+// nextFlags defines the flags that shape the output of --next
+func nextFlags(fs *flag.FlagSet, opts *runOptions) {
+	fs.DurationVar(&opts.within, "within", 0, "With --next, only show events starting within this window (e.g. 24h)")
+	fs.BoolVar(&opts.json, "json", false, "With --next or --upcoming, print the events as JSON")
+	fs.BoolVar(&opts.pinned, "pinned", false, "With --next or --upcoming, only show pinned events")
+	fs.IntVar(&opts.maxWidth, "max-width", 0, "With --next, print each event on one line of at most N cells, for a status bar")
+}
+
+// outputFlag defines the file --print-month writes to
+func outputFlag(fs *flag.FlagSet, opts *runOptions) {
+	fs.StringVar(&opts.output, "output", "", "With --print-month, the `FILE` to write (default MONTH.pdf)")
+}
+
+// calendarFilterFlags defines the flags that pick the calendars of one-shot output
+func calendarFilterFlags(fs *flag.FlagSet, opts *runOptions) {
+	fs.Var(&opts.calendar, "calendar", "Only show this calendar in one-shot output (repeatable)")
+	fs.Var(&opts.excludeCalendar, "exclude-calendar", "Hide this calendar from one-shot output (repeatable)")
+}
+
+// debugFlags defines the flags for looking into memory use and startup time
+func debugFlags(fs *flag.FlagSet, opts *runOptions) {
+	fs.BoolVar(&opts.debug, "debug", false, "Show memory use in the status line, or on stderr after one-shot output")
+	fs.BoolVar(&opts.profile, "profile", false, "Print how long each step of startup took on stderr, when quitting")
+	fs.StringVar(&opts.pprof, "pprof", "", "Write CPU and heap profiles to `FILE`.cpu and FILE.heap, for go tool pprof")
+}
+
+// run prints what opts ask for and quits, or starts the calendar. With an
+// input, the calendar starts with a preview of the ICS data read from it.
+func run(opts *runOptions, input io.Reader, started time.Time) {
+	if opts.pprof != "" {
+		stop, err := startPprof(opts.pprof)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			defer stop()
+		}
+	}
+	if opts.profile {
+		startupProfile = newProfile(started)
+		defer startupProfile.report()
+	}
 
 	config, _ := loadConfig()
 	var radicaleConfig *RadicaleConfig
 	if config != nil && config.Radicale != nil {
 		radicaleConfig = config.Radicale
 	}
+	if config != nil {
+		setLocale(config.Locale)
+		if err := setupHTTPClient(config.HTTP); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := setFloatingTimezone(config.FloatingTimezone); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := setDescriptionCleaners(config.Descriptions); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := setDayParts(config.DayParts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	palette := ""
+	if config != nil {
+		palette = config.Palette
+	}
+	if err := setPalette(palette); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		setPalette("")
+	}
+	startupProfile.step("config")
 
-	events, calendars, calendarURLs, _ := loadAllCalendars(radicaleConfig)
-
-	if *nextFlag {
-		nextEvent := getNextEvent(events)
-		fmt.Println(renderNextEvent(nextEvent))
+	if opts.motd {
+		runMotd(config, systemClock{}, started)
 		return
 	}
 
 	viewMode := DailyView
 	oneShot := false
 
-	if *dayFlag {
+	if opts.day {
 		viewMode = DailyView
 		oneShot = true
-	} else if *weekFlag {
+	} else if opts.week {
 		viewMode = WeeklyView
 		oneShot = true
-	} else if *monthFlag {
+	} else if opts.month {
 		viewMode = MonthlyView
 		oneShot = true
+	} else if opts.rolling {
+		viewMode = RollingView
+		oneShot = true
+	}
+
+	m := initialModel(viewMode, oneShot, config, systemClock{})
+	habits, err := loadHabitLog()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to read habits: %v\n", err)
+	}
+	m.habits = habits
+	if err := loadPins(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to read pins: %v\n", err)
+	}
+
+	if opts.writeAgenda != "" {
+		writer := agendaWriter{path: opts.writeAgenda, json: opts.json, config: config, clock: m.clock, include: opts.calendar, exclude: opts.excludeCalendar}
+		if err := writer.run(opts.watch); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	m.debug = opts.debug
+	if input != nil {
+		events, err := readInvite(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		m.invite = &invitePreview{events: events}
+		m.currentDate = dayStart(events[0].Start)
 	}
 
-	m := initialModel(viewMode, oneShot, radicaleConfig)
-	m.events = events
-	m.calendars = calendars
-	m.calendarURLs = calendarURLs
+	var printMonth time.Time
+	if opts.printMonth != "" {
+		if printMonth, err = parsePrintMonth(opts.printMonth); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		m.currentDate = printMonth
+	}
+
+	// The TUI loads calendars itself, showing progress
+	if oneShot || opts.next.set || opts.upcoming > 0 || opts.changes || opts.free > 0 || opts.freeBusy > 0 || opts.exportMD.set || opts.exportHTML.set || !printMonth.IsZero() || opts.publish != "" {
+		events, calendars, calendarURLs, loadErr := loadCalendarsAround(m.clock, m.currentDate, radicaleConfig, nil, nil)
+		if opts.debug {
+			defer func() {
+				fmt.Fprintln(os.Stderr, memoryStats(len(events)))
+			}()
+		}
+
+		if opts.changes {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			changes, err := checkCalendarChanges(events, m.clock.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(renderChanges(changes))
+			return
+		}
+
+		oneShotEvents := filterEventsByCalendar(events, opts.calendar, opts.excludeCalendar)
+
+		if opts.freeBusy > 0 {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			fmt.Print(exportFreeBusy(oneShotEvents, config, opts.freeBusy, m.clock.Now()))
+			return
+		}
+
+		if opts.publish != "" {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			var publish *PublishConfig
+			if config != nil {
+				publish = config.Publish
+			}
+			m.events = publishedEvents(oneShotEvents, publish)
+			m.calendars = calendars
+			if err := m.publishSite(opts.publish, publish); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if !printMonth.IsZero() {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			m.events = oneShotEvents
+			m.calendars = calendars
+			output := opts.output
+			if output == "" {
+				output = opts.printMonth + ".pdf"
+			}
+			if err := m.writeMonthPDF(printMonth, output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if opts.exportMD.set || opts.exportHTML.set {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			m.events = oneShotEvents
+			m.calendars = calendars
+			if opts.exportHTML.set {
+				fmt.Print(m.exportHTML(m.exportRange(opts.exportHTML.value)))
+			} else {
+				fmt.Print(m.exportMarkdown(m.exportRange(opts.exportMD.value)))
+			}
+			return
+		}
 
-	if oneShot {
+		if opts.free > 0 {
+			if loadErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", loadErr)
+				os.Exit(1)
+			}
+			m.events = oneShotEvents
+			if cmd := m.refreshFreeBusy(opts.free); cmd != nil {
+				msg := cmd().(freeBusyLoadedMsg)
+				for _, failure := range msg.failed {
+					fmt.Fprintf(os.Stderr, "Warning: %s\n", failure)
+				}
+				m.colleagueBusy = msg.busy
+			}
+			lines := m.availability(m.currentDate, opts.free)
+			if len(lines) == 0 {
+				fmt.Println(tr("No free time in working hours"))
+			}
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+			return
+		}
+
+		if opts.upcoming > 0 {
+			events := oneShotEvents
+			if len(opts.calendar) == 0 {
+				events = filterEventsByCalendar(events, nil, excludedFromNext(config))
+			}
+			if opts.pinned {
+				events = pinnedOnly(events)
+			}
+			upcoming := getUpcomingEvents(events, 0, time.Duration(opts.upcoming), m.clock.Now())
+			if opts.json {
+				printJSON(upcoming)
+			} else if opts.humanize {
+				fmt.Println(renderHumanized(upcoming, m.clock.Now()))
+			} else {
+				fmt.Println(renderUpcomingEvents(upcoming, m.clock.Now()))
+			}
+			return
+		}
+
+		if opts.next.set {
+			events := oneShotEvents
+			if len(opts.calendar) == 0 {
+				// An explicit --calendar overrides include_in_next
+				events = filterEventsByCalendar(events, nil, excludedFromNext(config))
+			}
+			if opts.pinned {
+				events = pinnedOnly(events)
+			}
+			count := opts.next.count
+			if count == 0 && opts.within == 0 {
+				count = 1
+			}
+			now := m.clock.Now()
+			if opts.json {
+				printJSON(getUpcomingEvents(events, count, opts.within, now))
+			} else if opts.maxWidth > 0 {
+				fmt.Println(renderCompactEvents(getUpcomingEvents(events, count, opts.within, now), opts.maxWidth, now))
+			} else if count == 1 {
+				fmt.Println(renderNextEvent(getNextEvent(getUpcomingEvents(events, 1, opts.within, now), now), now))
+			} else {
+				fmt.Println(renderUpcomingEvents(getUpcomingEvents(events, count, opts.within, now), now))
+			}
+			return
+		}
+
+		if loadErr != nil {
+			m.err = loadErr
+			oneShotEvents, calendars = sampleEvents(m.currentDate)
+		}
+		m.events = oneShotEvents
+		m.calendars = calendars
+		m.calendarURLs = calendarURLs
+		if len(opts.calendar) > 0 || len(opts.excludeCalendar) > 0 {
+			m.calendars = make(map[string]lipgloss.Color)
+			for _, event := range oneShotEvents {
+				m.calendars[event.CalendarName] = calendars[event.CalendarName]
+			}
+		}
 		fmt.Println(m.View())
 		return
 	}
 
-	p := tea.NewProgram(m)
+	m.openUID = opts.openUID
+	var options []tea.ProgramOption
+	if input != nil {
+		// Keys come from the terminal, stdin was the ICS data
+		options = append(options, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(m, options...)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
 }
+
+// printJSON prints events as JSON, exiting on failure
+func printJSON(events []Event) {
+	out, err := renderEventsJSON(events)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(out)
+}
+
+// nextCountFlag is a boolean-style flag that optionally takes a count, so
+// both "--next" and "--next=5" work ("--next 5" is handled by parseNextCount)
+type nextCountFlag struct {
+	set   bool
+	count int // 0 when no count was given
+}
+
+func (f *nextCountFlag) String() string {
+	if f == nil || !f.set {
+		return ""
+	}
+	return strconv.Itoa(f.count)
+}
+
+func (f *nextCountFlag) Set(value string) error {
+	switch value {
+	case "true":
+		f.set = true
+		return nil
+	case "false":
+		f.set = false
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("expected a positive count, got %q", value)
+	}
+	f.set, f.count = true, n
+	return nil
+}
+
+func (f *nextCountFlag) IsBoolFlag() bool { return true }
+
+// parseNextCount picks up the count in "--next 5", which the flag package
+// leaves as a positional argument, and parses any flags after it
+func parseNextCount(f *nextCountFlag) {
+	if !f.set || f.count != 0 || flag.NArg() == 0 {
+		return
+	}
+	n, err := strconv.Atoi(flag.Arg(0))
+	if err != nil || n < 1 {
+		return
+	}
+	f.count = n
+	flag.CommandLine.Parse(flag.Args()[1:])
+}
+
+// stringListFlag collects the values of a repeatable flag
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// filterEventsByCalendar keeps events from the included calendars (all if
+// include is empty) minus the excluded ones. Names match case-insensitively.
+func filterEventsByCalendar(events []Event, include, exclude []string) []Event {
+	if len(include) == 0 && len(exclude) == 0 {
+		return events
+	}
+
+	matches := func(names []string, name string) bool {
+		for _, n := range names {
+			if strings.EqualFold(n, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var filtered []Event
+	for _, event := range events {
+		if len(include) > 0 && !matches(include, event.CalendarName) {
+			continue
+		}
+		if matches(exclude, event.CalendarName) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
+// excludedFromNext lists the calendars configured with include_in_next: false
+func excludedFromNext(config *Config) []string {
+	if config == nil {
+		return nil
+	}
+	var names []string
+	for _, cal := range config.Calendars {
+		if cal.IncludeInNext != nil && !*cal.IncludeInNext {
+			names = append(names, cal.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// forwardPrefixes are stripped from summaries before comparing them, as
+// forwarding an invite to another calendar often adds one
+var forwardPrefixes = []string{"fw:", "fwd:", "wg:", "invitation:", "updated invitation:", "accepted:"}
+
+// mergeDuplicates folds events with the same start and end and a similar
+// summary in different calendars into the first one, which lists the
+// others in AlsoIn
+func mergeDuplicates(events []Event) []Event {
+	type slot struct{ start, end int64 }
+	first := make(map[slot][]int) // Indexes of the kept events per slot
+
+	var merged []Event
+	for _, event := range events {
+		key := slot{event.Start.Unix(), event.End.Unix()}
+		duplicate := false
+		for _, i := range first[key] {
+			kept := &merged[i]
+			if kept.CalendarName == event.CalendarName || slices.Contains(kept.AlsoIn, event.CalendarName) {
+				continue
+			}
+			if similarSummaries(kept.Summary, event.Summary) {
+				kept.AlsoIn = append(kept.AlsoIn, event.CalendarName)
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			first[key] = append(first[key], len(merged))
+			merged = append(merged, event)
+		}
+	}
+	return merged
+}
+
+// similarSummaries reports whether two summaries likely name the same
+// meeting: once forwarding prefixes, case and punctuation are ignored, the
+// words of one are all in the other, or they share most of their words. A
+// single word is too common to be contained in another summary: "Lunch" is
+// not "Lunch with investors".
+func similarSummaries(a, b string) bool {
+	setA, setB := wordSet(summaryWords(a)), wordSet(summaryWords(b))
+	if len(setA) == 0 || len(setB) == 0 {
+		return false
+	}
+
+	shared := 0
+	for word := range setB {
+		if setA[word] {
+			shared++
+		}
+	}
+	if min(len(setA), len(setB)) >= 2 && (shared == len(setA) || shared == len(setB)) {
+		return true
+	}
+	// Jaccard similarity of the word sets
+	union := len(setA) + len(setB) - shared
+	return float64(shared)/float64(union) >= 0.6
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// summaryWords lowercases a summary, strips forwarding prefixes and splits
+// it into words
+func summaryWords(summary string) []string {
+	summary = strings.ToLower(strings.TrimSpace(summary))
+	for stripped := true; stripped; {
+		stripped = false
+		for _, prefix := range forwardPrefixes {
+			if strings.HasPrefix(summary, prefix) {
+				summary = strings.TrimSpace(summary[len(prefix):])
+				stripped = true
+			}
+		}
+	}
+	return strings.FieldsFunc(summary, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimilarSummaries(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Weekly sync", "Weekly sync", true},
+		{"Weekly sync", "FW: Weekly Sync", true},
+		{"Fwd: WG: Budget review", "budget review!", true},
+		{"Budget review", "Budget review with finance", true},
+		{"Q3 planning session, part 2", "Q3 planning session part 2 (updated)", true},
+		{"Call", "Recall meeting", false},
+		{"Sync", "Async standup", false},
+		{"Review", "Preview of the release", false},
+		{"Design review", "Code review", false},
+		{"Lunch", "Lunch", true},
+		{"Lunch", "Lunch with investors", false},
+		{"Standup", "Standup with the platform team", false},
+		{"Lunch", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		if got := similarSummaries(test.a, test.b); got != test.want {
+			t.Errorf("similarSummaries(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	events := []Event{
+		{Summary: "Weekly sync", Start: start, End: end, CalendarName: "Work"},
+		{Summary: "FW: Weekly sync", Start: start, End: end, CalendarName: "Personal"},
+		{Summary: "Call", Start: start, End: end, CalendarName: "Family"},
+		{Summary: "Recall meeting", Start: start, End: end, CalendarName: "Personal"},
+		{Summary: "Lunch", Start: start, End: end, CalendarName: "Family"},
+		{Summary: "Lunch with investors", Start: start, End: end, CalendarName: "Work"},
+		{Summary: "Weekly sync", Start: start.Add(time.Hour), End: end.Add(time.Hour), CalendarName: "Personal"},
+	}
+
+	merged := mergeDuplicates(events)
+	if len(merged) != 6 {
+		t.Fatalf("got %d events, want 6", len(merged))
+	}
+	if got := merged[0].AlsoIn; len(got) != 1 || got[0] != "Personal" {
+		t.Errorf("Weekly sync also in %v, want [Personal]", got)
+	}
+	for _, event := range merged[1:] {
+		if len(event.AlsoIn) > 0 {
+			t.Errorf("%q merged with %v", event.Summary, event.AlsoIn)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import "strings"
+
+// sharedCalendar reports whether a calendar is marked as shared, so the
+// mine-only filter applies to it
+func (c *Config) sharedCalendar(name string) bool {
+	if c == nil {
+		return false
+	}
+	for _, cal := range c.Calendars {
+		if cal.Name == name && cal.Shared {
+			return true
+		}
+	}
+	return false
+}
+
+// isMine reports whether one of the configured emails organizes or attends
+// an event
+func (c *Config) isMine(event Event) bool {
+	for _, email := range c.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+		if event.Organizer == email {
+			return true
+		}
+		for _, attendee := range event.Attendees {
+			if attendee == email {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// visible reports whether an event is shown: its calendar and the
+// calendar's group must not be hidden, and with the mine-only filter on,
+// events on shared calendars need to involve the user
+func (m model) visible(event Event) bool {
+	if m.calendarHidden(event.CalendarName) {
+		return false
+	}
+	if !m.mineOnly || !m.config.sharedCalendar(event.CalendarName) {
+		return true
+	}
+	return m.config.isMine(event)
+}
+
+// toggleMineOnly switches between all events and only the user's own on
+// shared calendars (u)
+func (m model) toggleMineOnly() model {
+	if m.config == nil || len(m.config.Emails) == 0 {
+		m.message = tr("Set emails in the config first")
+		return m
+	}
+	m.mineOnly = !m.mineOnly
+	m.cursor = 0
+	if m.mineOnly {
+		m.message = tr("Shared calendars: only my events")
+	} else {
+		m.message = tr("Shared calendars: all events")
+	}
+	return m
+}
@@ -2,51 +2,30 @@ package main
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/ical"
 )
 
-func initialModel(viewMode ViewMode, oneShot bool, radicaleConfig *RadicaleConfig) model {
-	currentDate := time.Now()
-
-	events, calendars, calendarURLs, err := loadAllCalendars(radicaleConfig)
-	if err != nil {
-		events = []Event{
-			{
-				Summary:       "Team Standup",
-				Start:         time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 9, 0, 0, 0, time.Local),
-				End:           time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 9, 30, 0, 0, time.Local),
-				CalendarName:  "Work",
-				CalendarColor: calendarColors[0],
-			},
-			{
-				Summary:       "Lunch Break",
-				Start:         time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 12, 0, 0, 0, time.Local),
-				End:           time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 13, 0, 0, 0, time.Local),
-				CalendarName:  "Personal",
-				CalendarColor: calendarColors[1],
-			},
-		}
-		calendars = map[string]lipgloss.Color{
-			"Work":     calendarColors[0],
-			"Personal": calendarColors[1],
-		}
-		calendarURLs = make(map[string]string)
-	}
+func initialModel(viewMode ViewMode, oneShot bool, config *Config, clock Clock) model {
+	currentDate := clock.Now()
 
-	// Set default selected calendar
-	var defaultCalendar string
-	for name := range calendars {
-		defaultCalendar = name
-		break
+	var radicaleConfig *RadicaleConfig
+	if config != nil {
+		radicaleConfig = config.Radicale
 	}
 
+	// Calendars are loaded by the caller (one-shot modes) or in the
+	// background once the TUI starts
+	calendars := make(map[string]lipgloss.Color)
+
 	// Initialize progress bar
 	prog := progress.New(progress.WithScaledGradient("#FF7CCB", "#FDFF8C"))
 	prog.Width = 40
@@ -54,26 +33,40 @@ func initialModel(viewMode ViewMode, oneShot bool, radicaleConfig *RadicaleConfi
 	// Initialize form data
 	summary := ""
 	description := ""
+	attendees := ""
+	eventURL := ""
 	dateStr := currentDate.Format("02-01-2006") // DD-MM-YYYY format
 	startTime := "09:00"
 	endTime := "10:00"
-	selectedCal := defaultCalendar
+	endDate := ""
+	selectedCal := ""
 	repeatOptions := "none"
 	repeatEndDate := ""
+	repeatInterval := "1"
+	var repeatDays []string
+	repeatMonthly := "day"
+	repeatCount := ""
+
+	// Input left in the form last time, offered when it is opened again
+	draft, _ := loadDraft()
 
 	// Build event form
-	eventForm := buildEventForm(&summary, &description, &dateStr, &startTime, &endTime, &selectedCal, &repeatOptions, &repeatEndDate, calendars)
+	eventForm := buildEventForm(&summary, &description, &attendees, &eventURL, &dateStr, &startTime, &endTime, &endDate, &selectedCal, &repeatOptions, &repeatEndDate, &repeatInterval, &repeatDays, &repeatMonthly, &repeatCount, nil, nil)
 
 	return model{
-		events:           events,
-		calendars:        calendars,
-		calendarURLs:     calendarURLs,
-		currentDate:      currentDate,
-		viewMode:         viewMode,
-		oneShot:          oneShot,
-		err:              err,
-		radicaleConfig:   radicaleConfig,
-		selectedCalendar: defaultCalendar,
+		calendars:      calendars,
+		calendarURLs:   make(map[string]string),
+		currentDate:    currentDate,
+		clock:          clock,
+		loadCenter:     currentDate,
+		viewMode:       viewMode,
+		oneShot:        oneShot,
+		radicaleConfig: radicaleConfig,
+		selected:       make(map[string]bool),
+		config:         config,
+		dirty:          make(map[string]eventVersion),
+		notes:          make(map[string]ical.Journal),
+		pendingCount:   countPendingOps(),
 		uiFormState: UIFormState{
 			date:      currentDate,
 			startTime: "09:00",
@@ -81,29 +74,57 @@ func initialModel(viewMode ViewMode, oneShot bool, radicaleConfig *RadicaleConfi
 		},
 		eventForm:         eventForm,
 		loadingProgress:   prog,
-		isLoading:         false,
+		isLoading:         !oneShot,
 		formSummary:       &summary,
 		formDescription:   &description,
+		formAttendees:     &attendees,
+		formURL:           &eventURL,
 		formDate:          &dateStr,
 		formStartTime:     &startTime,
 		formEndTime:       &endTime,
+		formEndDate:       &endDate,
 		formCalendar:      &selectedCal,
 		formRepeatOptions: &repeatOptions,
 		formRepeatEndDate: &repeatEndDate,
+		formRepeatEvery:   &repeatInterval,
+		formRepeatDays:    &repeatDays,
+		formRepeatMonthly: &repeatMonthly,
+		formRepeatCount:   &repeatCount,
 		formScrollOffset:  0,
+		draft:             draft,
 	}
 }
 func (m model) Init() tea.Cmd {
 	if m.oneShot {
 		return tea.Quit
 	}
+	cmds := []tea.Cmd{
+		loadCalendarsWithProgress(m.clock, m.radicaleConfig),
+		scheduleRefresh(m.refreshInterval()),
+		loadContactsCmd(m.config),
+	}
+	if m.titleEnabled() {
+		cmds = append(cmds, scheduleTitleUpdate())
+	}
+	if m.alertsEnabled() {
+		cmds = append(cmds, scheduleAlertCheck())
+	}
 	if m.eventForm != nil {
-		return m.eventForm.Init()
+		cmds = append(cmds, m.eventForm.Init())
 	}
-	return nil
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Contacts arrive in the background, maybe while the form is open
+	if loaded, ok := msg.(contactsLoadedMsg); ok {
+		if loaded.err != nil {
+			m.message = fmt.Sprintf("Contacts: %v", loaded.err)
+		}
+		m.contacts = loaded.contacts
+		return m, nil
+	}
+
 	// If we're in form mode, handle ALL messages through the form first
 	// This gives the form complete control over its own state
 	if m.creationMode == UIFormInput && m.eventForm != nil {
@@ -122,11 +143,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Until the draft question is answered, keys go to it
+		if kmsg, ok := msg.(tea.KeyMsg); ok && m.draftPrompt {
+			return m.handleDraftPrompt(kmsg)
+		}
+
+		// ctrl+e edits the description in an external editor from any
+		// field but the attendees, where it completes a contact
+		if kmsg, ok := msg.(tea.KeyMsg); ok && kmsg.String() == "ctrl+e" && m.eventForm.GetFocusedField().GetKey() != "attendees" {
+			return m, editDescription(*m.formDescription)
+		}
+		if kmsg, ok := msg.(tea.KeyMsg); ok {
+			if stepped, ok := m.stepFormTime(kmsg); ok {
+				return stepped, nil
+			}
+		}
+		if edited, ok := msg.(descriptionEditedMsg); ok {
+			if edited.err != nil {
+				return m.reopenForm(fieldDescription, fmt.Sprintf("Editor failed: %v", edited.err))
+			}
+			*m.formDescription = edited.text
+			m = m.trackDraft()
+			return m.reopenForm(fieldDescription, "")
+		}
+
 		// Pass ALL messages directly to the form
 		form, cmd := m.eventForm.Update(msg)
 		if f, ok := form.(*huh.Form); ok {
 			m.eventForm = f
 		}
+		m = m.trackDraft()
 
 		// Check form state after it processes the message
 		if m.eventForm.State == huh.StateCompleted {
@@ -136,9 +182,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.eventForm.State == huh.StateAborted {
 			m.creationMode = NoCreation
 			m.formScrollOffset = 0
+			m.formError = ""
 			m.message = ""
+			if m.draft != nil {
+				m.message = "Draft kept, it is offered when the form is opened again"
+			}
 			// Rebuild form for next time
-			m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
+			m.eventForm = m.buildForm()
 			return m, m.eventForm.Init()
 		}
 
@@ -146,6 +196,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.noteForm != nil {
+		if wmsg, ok := msg.(tea.WindowSizeMsg); ok {
+			m.width = wmsg.Width
+			m.height = wmsg.Height
+			m.noteForm = m.noteForm.WithWidth(m.width)
+		}
+		return m.updateNoteForm(msg)
+	}
+	if m.focusForm != nil {
+		return m.updateFocusForm(msg)
+	}
+
 	// Main view handling (only when NOT in form mode)
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -166,6 +228,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.isLoading = true
 		m.loadingMessage = msg.message
 		cmd := m.loadingProgress.SetPercent(msg.progress)
+		if msg.updates != nil {
+			cmd = tea.Batch(cmd, waitForUpdate(msg.updates))
+		}
 		return m, cmd
 
 	case loadingCompleteMsg:
@@ -173,6 +238,83 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loadingMessage = ""
 		return m, nil
 
+	case bulkDoneMsg:
+		return m.applyBulkResults(msg), nil
+
+	case createProgressMsg:
+		return m.applyCreateProgress(msg)
+
+	case createDoneMsg:
+		return m.applyCreateResults(msg), nil
+
+	case timerTickMsg:
+		return m.updateTimer()
+
+	case zenTickMsg:
+		if m.zen {
+			return m, scheduleZenTick()
+		}
+		return m, nil
+
+	case titleTickMsg:
+		return m, tea.Batch(m.updateTitle(), scheduleTitleUpdate())
+
+	case alertCheckMsg:
+		var cmd tea.Cmd
+		m, cmd = m.checkAlerts(msg.dismissed)
+		if msg.tick {
+			cmd = tea.Batch(cmd, scheduleAlertCheck())
+		}
+		return m, cmd
+
+	case alertFlashMsg:
+		return m.updateAlertFlash()
+
+	case refreshTickMsg:
+		return m, tea.Batch(loadCalendarsCmd(m.clock, m.radicaleConfig, m.loadCenter), scheduleRefresh(m.refreshInterval()))
+
+	case eventsUpdatedMsg:
+		if msg.initial {
+			m = m.applyInitialLoad(msg)
+		} else {
+			m = m.applyRefresh(msg)
+		}
+		cmds := []tea.Cmd{m.refreshNotes(), m.refreshFreeBusy(freeBusyDays), m.updateTitle()}
+		if m.pendingCount > 0 {
+			cmds = append(cmds, flushQueueCmd(m.radicaleConfig))
+		}
+		if m.alertsEnabled() {
+			// Don't wait for the next check to announce what was just loaded
+			cmds = append(cmds, checkAlertsCmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case syncErrorMsg:
+		if msg.initial {
+			m = m.applyLoadFailure(msg.err)
+			return m, tea.Batch(m.refreshNotes(), m.refreshFreeBusy(freeBusyDays), m.updateTitle())
+		}
+		m.message = fmt.Sprintf("Refresh failed: %v", msg.err)
+		return m, nil
+
+	case eventPushedMsg:
+		return m.applyEventPushed(msg), nil
+
+	case notesLoadedMsg:
+		return m.applyNotes(msg), nil
+
+	case tasksLoadedMsg:
+		return m.applyTasks(msg), nil
+
+	case freeBusyLoadedMsg:
+		return m.applyFreeBusy(msg), nil
+
+	case noteSavedMsg:
+		return m.applyNoteSaved(msg), nil
+
+	case queueFlushedMsg:
+		return m.applyQueueFlush(msg), nil
+
 	case tea.KeyMsg:
 
 		// Handle event creation mode (natural language)
@@ -181,51 +323,163 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.String() == "l" {
 				m.creationMode = UIFormInput
 				// Rebuild form
-				m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
+				m.eventForm = m.buildForm()
 				return m, m.eventForm.Init()
 			}
 			return m.handleEventCreationInput(msg)
 		}
 
+		// A pending bulk action captures all keys until confirmed or cancelled
+		if m.pendingBulk != nil {
+			return m.handleBulkConfirm(msg)
+		}
+
+		if m.confirmQuit > 0 {
+			return m.handleQuitConfirm(msg)
+		}
+
+		if m.groupPrompt {
+			return m.handleGroupKey(msg)
+		}
+
+		// Conflicts from a refresh must be resolved before continuing
+		if len(m.conflicts) > 0 && msg.String() != "ctrl+c" {
+			return m.handleConflictKey(msg)
+		}
+
+		if m.timer != nil {
+			return m.handleTimerKey(msg)
+		}
+
+		if m.detail != nil {
+			return m.handleDetailKey(msg)
+		}
+
+		if m.planner != nil {
+			return m.handlePlannerKey(msg)
+		}
+
+		if m.zen {
+			return m.handleZenKey(msg)
+		}
+
+		if m.invite != nil {
+			return m.handleInviteKey(msg)
+		}
+
+		// Esc cancels a running batch of event writes
+		if m.creating != nil && msg.String() == "esc" {
+			m.creating.stop()
+			m.message = "Cancelling..."
+			return m, nil
+		}
+
 		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
+		case "q":
+			if count := m.unsyncedCount(); count > 0 {
+				m.confirmQuit = count
+				return m, nil
+			}
+			return m, m.quit()
+		case "ctrl+c":
+			return m, m.quit()
 		case "n", "a": // 'n' for new, 'a' for add
-			m.creationMode = UIFormInput
-			// Reset form values
-			*m.formSummary = ""
-			*m.formDescription = ""
-			*m.formDate = m.currentDate.Format("02-01-2006") // DD-MM-YYYY format
-			*m.formStartTime = ""                            // No default
-			*m.formEndTime = ""                              // No default
-			*m.formCalendar = m.selectedCalendar
-			*m.formRepeatOptions = "none" // Default to "None"
-			*m.formRepeatEndDate = ""
-			m.formScrollOffset = 0
-			// Rebuild form
-			m.eventForm = buildEventForm(m.formSummary, m.formDescription, m.formDate, m.formStartTime, m.formEndTime, m.formCalendar, m.formRepeatOptions, m.formRepeatEndDate, m.calendars)
-			return m, m.eventForm.Init()
+			return m.openEventForm("", "")
+		case "o": // New event in the focused slot
+			return m.openEventForm(m.focusedSlot())
 		case "left", "h":
-			if m.viewMode == DailyView {
+			// h moves the focus of the weekly view by a day
+			if m.viewMode == DailyView || (m.viewMode == WeeklyView && msg.String() == "h") {
 				m.currentDate = m.currentDate.AddDate(0, 0, -1)
-			} else if m.viewMode == WeeklyView {
+			} else if m.viewMode == WeeklyView || m.viewMode == RollingView {
 				m.currentDate = m.currentDate.AddDate(0, 0, -7)
 			} else if m.viewMode == MonthlyView {
 				m.currentDate = m.currentDate.AddDate(0, -1, 0)
 			}
 			m.dayInput = ""
+			m.cursor = 0
 		case "right", "l":
-			if m.viewMode == DailyView {
+			if m.viewMode == DailyView || (m.viewMode == WeeklyView && msg.String() == "l") {
 				m.currentDate = m.currentDate.AddDate(0, 0, 1)
-			} else if m.viewMode == WeeklyView {
+			} else if m.viewMode == WeeklyView || m.viewMode == RollingView {
 				m.currentDate = m.currentDate.AddDate(0, 0, 7)
 			} else if m.viewMode == MonthlyView {
 				m.currentDate = m.currentDate.AddDate(0, 1, 0)
 			}
 			m.dayInput = ""
+			m.cursor = 0
+		case "Z":
+			m.redact = !m.redact
+		case "u":
+			m = m.toggleMineOnly()
+		case "G":
+			m = m.startGroupToggle()
+		case "x":
+			m = m.toggleHabitDone()
+		case "*":
+			m = m.togglePin()
+		case "T":
+			return m.startPlanner()
+		case "p":
+			return m.startTimer()
+		case "f":
+			return m.startZen()
+		case "J":
+			return m.startNote()
+		case "b":
+			return m.blockNextFreeSlot()
+		case "F":
+			m.message = m.availabilityMessage()
+		case "B":
+			return m.startFocusRange()
+		case "r":
+			m.message = "Refreshing..."
+			// Pick up calendars added or removed on the server too
+			clearDiscovery()
+			m.loadCenter = m.currentDate
+			return m, loadCalendarsCmd(m.clock, m.radicaleConfig, m.loadCenter)
 		case "t":
-			m.currentDate = time.Now()
-			m.dayInput = ""
+			m = m.jumpTo(m.clock.Now(), m.viewMode)
+		case "ctrl+o":
+			m = m.jumpBackward()
+		case "tab": // ctrl+i
+			m = m.jumpAhead()
+		case "down", "j":
+			if m.viewMode == DailyView && m.cursor < len(m.dailyEvents())-1 {
+				m.cursor++
+			}
+		case "up", "k":
+			if m.viewMode == DailyView && m.cursor > 0 {
+				m.cursor--
+			}
+		case " ":
+			if m.viewMode == DailyView {
+				dayEvents := m.dailyEvents()
+				if m.cursor < len(dayEvents) {
+					key := eventKey(dayEvents[m.cursor])
+					if m.selected[key] {
+						delete(m.selected, key)
+					} else {
+						m.selected[key] = true
+					}
+				}
+			}
+		case "V":
+			if m.viewMode == DailyView {
+				for _, event := range m.dailyEvents() {
+					m.selected[eventKey(event)] = true
+				}
+			}
+		case "D":
+			m = m.startBulkAction(BulkDelete)
+		case "C":
+			m = m.startBulkAction(BulkMove)
+		case ">":
+			m = m.startBulkAction(BulkShiftForward)
+		case "<":
+			m = m.startBulkAction(BulkShiftBack)
+		case "E":
+			m = m.startBulkAction(BulkExport)
 		case "d":
 			m.viewMode = DailyView
 			m.dayInput = ""
@@ -235,30 +489,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "m":
 			m.viewMode = MonthlyView
 			m.dayInput = ""
+		case "g":
+			m.viewMode = RollingView
+			m.dayInput = ""
 		case "enter":
+			if m.viewMode == DailyView {
+				m = m.openDetail()
+			}
+			if m.viewMode == WeeklyView {
+				// Expand the focused day, and open it once expanded
+				if !m.expandedDay.IsZero() && sameDay(m.expandedDay, m.currentDate) {
+					m.expandedDay = time.Time{}
+					m = m.jumpTo(m.currentDate, DailyView)
+				} else {
+					m.expandedDay = m.currentDate
+				}
+			}
 			if m.viewMode == MonthlyView && m.dayInput != "" {
-				if day, err := strconv.Atoi(m.dayInput); err == nil && day >= 1 && day <= 31 {
+				if day, err := strconv.Atoi(strings.TrimPrefix(m.dayInput, ":")); err == nil && day >= 1 && day <= 31 {
 					lastDay := time.Date(m.currentDate.Year(), m.currentDate.Month()+1, 0, 0, 0, 0, 0, time.Local).Day()
 					if day <= lastDay {
-						m.currentDate = time.Date(m.currentDate.Year(), m.currentDate.Month(), day, 0, 0, 0, 0, time.Local)
-						m.viewMode = DailyView
-						m.dayInput = ""
+						m = m.jumpTo(time.Date(m.currentDate.Year(), m.currentDate.Month(), day, 0, 0, 0, 0, time.Local), DailyView)
 					}
 				}
 			}
-		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		case ":":
+			// Starts typing a day of the month to jump to
 			if m.viewMode == MonthlyView {
+				m.dayInput = ":"
+			}
+		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if m.dayInput != "" {
 				m.dayInput += msg.String()
+			} else if key := msg.String(); key != "0" {
+				m = m.toggleCalendar(int(key[0] - '0'))
 			}
 		case "backspace":
 			if len(m.dayInput) > 0 {
 				m.dayInput = m.dayInput[:len(m.dayInput)-1]
 			}
-		case "escape":
+		case "esc", "escape":
 			m.dayInput = ""
+			m.expandedDay = time.Time{}
+			m.selected = make(map[string]bool)
 		}
 	}
-	return m, nil
+	return m.followView()
 }
 
 func (m model) handleEventCreationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -289,33 +565,10 @@ func (m model) handleEventCreationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if err == nil {
 				// Set calendar
 				event.CalendarName = m.selectedCalendar
-				if color, ok := m.calendars[m.selectedCalendar]; ok {
-					event.CalendarColor = color
-				} else {
-					// Use first available color
-					for _, c := range m.calendars {
-						event.CalendarColor = c
-						break
-					}
-				}
 
-				// Save to Radicale if configured
-				if m.radicaleConfig != nil && m.calendarURLs[m.selectedCalendar] != "" {
-					if err := createEventOnRadicale(m.calendarURLs[m.selectedCalendar], event, m.radicaleConfig); err != nil {
-						m.message = fmt.Sprintf("Error: %v", err)
-					} else {
-						m.message = "Event created successfully!"
-						m.events = append(m.events, *event)
-						m.creationMode = NoCreation
-						m.naturalLangInput = ""
-					}
-				} else {
-					// Save locally
-					m.events = append(m.events, *event)
-					m.message = "Event created successfully!"
-					m.creationMode = NoCreation
-					m.naturalLangInput = ""
-				}
+				m.creationMode = NoCreation
+				m.naturalLangInput = ""
+				return m.saveNewEvents([]*Event{event})
 			} else {
 				m.message = fmt.Sprintf("Parse error: %v", err)
 			}
@@ -353,11 +606,7 @@ func (m model) handleEventCreationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.uiFormState.endTime = m.uiFormState.editBuffer
 					}
 				case 5: // Calendar - cycle through
-					calNames := make([]string, 0, len(m.calendars))
-					for name := range m.calendars {
-						calNames = append(calNames, name)
-					}
-					sort.Strings(calNames)
+					calNames := m.sortedCalendarNames()
 					for i, name := range calNames {
 						if name == m.selectedCalendar {
 							if i+1 < len(calNames) {
@@ -417,11 +666,7 @@ func (m model) handleEventCreationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.uiFormState.editBuffer = m.uiFormState.endTime
 				case 5:
 					// Calendar selection - just cycle, no editing
-					calNames := make([]string, 0, len(m.calendars))
-					for name := range m.calendars {
-						calNames = append(calNames, name)
-					}
-					sort.Strings(calNames)
+					calNames := m.sortedCalendarNames()
 					for i, name := range calNames {
 						if name == m.selectedCalendar {
 							if i+1 < len(calNames) {
@@ -461,25 +706,8 @@ func (m model) handleEventCreationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					CalendarName: m.selectedCalendar,
 				}
 
-				if color, ok := m.calendars[m.selectedCalendar]; ok {
-					event.CalendarColor = color
-				}
-
-				// Save to Radicale if configured
-				if m.radicaleConfig != nil && m.calendarURLs[m.selectedCalendar] != "" {
-					if err := createEventOnRadicale(m.calendarURLs[m.selectedCalendar], event, m.radicaleConfig); err != nil {
-						m.message = fmt.Sprintf("Error: %v", err)
-					} else {
-						m.message = "Event created successfully!"
-						m.events = append(m.events, *event)
-						m.creationMode = NoCreation
-					}
-				} else {
-					// Save locally
-					m.events = append(m.events, *event)
-					m.message = "Event created successfully!"
-					m.creationMode = NoCreation
-				}
+				m.creationMode = NoCreation
+				return m.saveNewEvents([]*Event{event})
 			}
 		}
 	}
@@ -490,12 +718,39 @@ func (m model) View() string {
 	if m.isLoading {
 		return m.viewLoading()
 	}
+	defer startupProfile.rendered()
+
+	if m.tooSmall() {
+		return m.viewTooSmall()
+	}
 
 	// Render form view if creating event
 	if m.creationMode == UIFormInput && m.eventForm != nil {
 		return m.viewEventForm()
 	}
 
+	if m.noteForm != nil {
+		return m.noteForm.View()
+	}
+	if m.focusForm != nil {
+		return m.focusForm.View()
+	}
+	if m.timer != nil {
+		return m.viewTimer()
+	}
+	if m.detail != nil {
+		return m.viewDetail()
+	}
+	if m.planner != nil {
+		return m.viewPlanner()
+	}
+	if m.zen {
+		return m.viewZen()
+	}
+	if m.invite != nil {
+		return m.viewInvite()
+	}
+
 	// Render natural language input view
 	if m.creationMode == NaturalLanguageInput {
 		return m.viewNaturalLanguage()
@@ -509,7 +764,70 @@ func (m model) View() string {
 		return m.viewWeekly()
 	case MonthlyView:
 		return m.viewMonthly()
+	case RollingView:
+		return m.viewRolling()
 	default:
 		return ""
 	}
 }
+
+// applyInitialLoad shows the calendars loaded at startup
+func (m model) applyInitialLoad(msg eventsUpdatedMsg) model {
+	return m.applyRefresh(msg).finishLoading()
+}
+
+// applyLoadFailure falls back to sample data if no calendar could be loaded
+// at startup
+func (m model) applyLoadFailure(err error) model {
+	m.err = err
+	m.events, m.calendars = sampleEvents(m.currentDate)
+	return m.finishLoading()
+}
+
+// finishLoading leaves the loading view for the loaded (or sample) events
+func (m model) finishLoading() model {
+	m.isLoading = false
+	m.loadingMessage = ""
+
+	// Default to the first calendar for new events
+	if calNames := m.sortedCalendarNames(); len(calNames) > 0 {
+		m.selectedCalendar = calNames[0]
+	}
+
+	if m.openUID != "" {
+		m = m.focusEvent(m.openUID)
+	}
+	if m.invite != nil && m.invite.calendar == "" {
+		// The calendar for new events, if it can take them
+		for _, name := range m.inviteCalendars() {
+			if m.invite.calendar == "" || name == m.selectedCalendar {
+				m.invite.calendar = name
+			}
+		}
+	}
+	return m
+}
+
+// sampleEvents is shown when no calendars are configured
+func sampleEvents(currentDate time.Time) ([]Event, map[string]lipgloss.Color) {
+	events := []Event{
+		{
+			Summary:      "Team Standup",
+			Start:        time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 9, 0, 0, 0, time.Local),
+			End:          time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 9, 30, 0, 0, time.Local),
+			CalendarName: "Work",
+		},
+		{
+			Summary:      "Lunch Break",
+			Start:        time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 12, 0, 0, 0, time.Local),
+			End:          time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 13, 0, 0, 0, time.Local),
+			CalendarName: "Personal",
+		},
+	}
+	calendars := map[string]lipgloss.Color{
+		"Work":     calendarColors[0],
+		"Personal": calendarColors[1],
+	}
+	setCalendarColors(calendars)
+	return events, calendars
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	eventCacheFile = "event_cache.json"
+	// Events kept in the cache from the start of today
+	eventCacheWindow = 8 * 24 * time.Hour
+	// --motd runs at shell startup, so it must not keep the prompt waiting
+	motdBudget = 200 * time.Millisecond
+)
+
+// eventCache holds the events of the coming days from the last successful
+// load, for output that can't wait for the servers
+type eventCache struct {
+	SavedAt time.Time                 `json:"saved_at"`
+	Events  []Event                   `json:"events"`
+	Colors  map[string]lipgloss.Color `json:"colors,omitempty"` // By calendar name
+}
+
+// saveEventCache keeps the events of today and the coming days, without
+// their raw source, and the colors of their calendars
+func saveEventCache(events []Event, calendars map[string]lipgloss.Color, now time.Time) error {
+	cachePath, err := getStatePath(eventCacheFile)
+	if err != nil {
+		return err
+	}
+
+	from := dayStart(now)
+	to := from.Add(eventCacheWindow)
+	cache := eventCache{SavedAt: now, Colors: calendars}
+	for _, event := range events {
+		if event.End.After(from) && event.Start.Before(to) {
+			event.Raw = ""
+			cache.Events = append(cache.Events, event)
+		}
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	// A motd reading concurrently never sees half a file
+	return writeFileAtomic(cachePath, data)
+}
+
+func loadEventCache() (*eventCache, error) {
+	cachePath, err := getStatePath(eventCacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cache eventCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	setCalendarColors(cache.Colors)
+	return &cache, nil
+}
+
+// runMotd prints today's events and the next one for a shell greeting
+// (--motd). It reads the cache and refreshes it in the background when
+// stale, so it stays within motdBudget even without network. Without a
+// config it prints nothing. started is when the process started, which the
+// budget counts from.
+func runMotd(config *Config, clock Clock, started time.Time) {
+	if config == nil {
+		return
+	}
+	now := clock.Now()
+
+	cache, _ := loadEventCache()
+	interval := defaultRefreshInterval
+	if config.RefreshMinutes > 0 {
+		interval = time.Duration(config.RefreshMinutes) * time.Minute
+	}
+	if cache == nil || now.Sub(cache.SavedAt) > interval || dayStart(cache.SavedAt).Before(dayStart(now)) {
+		refreshCacheInBackground()
+	}
+	if cache == nil {
+		// First run: local calendars may still load in time. Leave some of
+		// the budget for printing and exiting.
+		cache = loadWithin(clock, motdBudget-10*time.Millisecond-time.Since(started))
+	}
+	if cache == nil {
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(tr("📅 Loading calendars, check back in a moment")))
+		return
+	}
+
+	fmt.Println(renderMotd(cache, config, now))
+}
+
+// loadWithin loads the calendars if that takes less than budget
+func loadWithin(clock Clock, budget time.Duration) *eventCache {
+	loaded := make(chan *eventCache, 1)
+	go func() {
+		events, _, _, err := loadAllCalendars(clock, nil, nil, func(string) {})
+		if err != nil {
+			loaded <- nil
+			return
+		}
+		loaded <- &eventCache{SavedAt: time.Now(), Events: events}
+	}()
+	select {
+	case cache := <-loaded:
+		return cache
+	case <-time.After(budget):
+		return nil
+	}
+}
+
+// refreshCacheInBackground starts "zebracal refresh-cache" without waiting
+// for it
+func refreshCacheInBackground() {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(executable, "refresh-cache")
+	if cmd.Start() == nil {
+		cmd.Process.Release()
+	}
+}
+
+// runRefreshCacheCommand loads all calendars, which updates the cache used
+// by --motd. It is also suitable for cron.
+func runRefreshCacheCommand() {
+	config, _ := loadConfig()
+	var radicaleConfig *RadicaleConfig
+	if config != nil {
+		radicaleConfig = config.Radicale
+		if err := setupHTTPClient(config.HTTP); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := setFloatingTimezone(config.FloatingTimezone); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if _, _, _, err := loadAllCalendars(systemClock{}, radicaleConfig, nil, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// renderMotd renders the greeting block: today's remaining events, the next
+// event if it isn't today, and when the data is from if it is old
+func renderMotd(cache *eventCache, config *Config, now time.Time) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("117"))
+
+	var today []Event
+	for _, event := range cache.Events {
+		if onDay(event, now) && event.End.After(now) {
+			today = append(today, event)
+		}
+	}
+	sort.SliceStable(today, func(i, j int) bool {
+		return today[i].Start.Before(today[j].Start)
+	})
+
+	var b strings.Builder
+	header := "📅 " + formatDate(now, "Mon Jan 2")
+	switch len(today) {
+	case 0:
+		header += " · " + tr("nothing left today")
+	case 1:
+		header += " · " + tr("1 event left today")
+	default:
+		header += " · " + fmt.Sprintf(tr("%d events left today"), len(today))
+	}
+	b.WriteString(headerStyle.Render(header))
+
+	whenWidth := max(5, lipgloss.Width(tr("all day")))
+	for _, event := range today {
+		when := event.Start.Format("15:04")
+		switch {
+		case isAllDay(event):
+			when = tr("all day")
+		case event.Start.Before(now):
+			when = tr("now")
+		}
+		b.WriteString("\n  " + timeStyle.Render(fmt.Sprintf("%-*s", whenWidth, when)) + " " +
+			lipgloss.NewStyle().Foreground(eventColor(event)).Render(event.Summary))
+	}
+
+	next := getNextEvent(filterEventsByCalendar(cache.Events, nil, excludedFromNext(config)), now)
+	if next != nil && dayIndex(now, next.Start) > 0 {
+		b.WriteString("\n  " + dimStyle.Render(tr("Next:")+" "+humanizeDay(next.Start, now)+" "+next.Start.Format("15:04")+" ") +
+			lipgloss.NewStyle().Foreground(eventColor(*next)).Render(next.Summary))
+	}
+
+	if age := now.Sub(cache.SavedAt); age > time.Hour {
+		b.WriteString("\n  " + dimStyle.Render(fmt.Sprintf(tr("(as of %s)"), formatDate(cache.SavedAt, "Mon 15:04"))))
+	}
+	return b.String()
+}
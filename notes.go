@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"mytuiapp/internal/ical"
+)
+
+// noteKey is the key of a day in model.notes
+func noteKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// notesCalendar returns the Radicale calendar daily notes are kept in: the
+// configured notes_calendar, or the first calendar by name
+func (m model) notesCalendar() (name string, url string) {
+	if m.radicaleConfig == nil {
+		return "", ""
+	}
+	if m.config != nil && m.config.NotesCalendar != "" {
+		return m.config.NotesCalendar, m.calendarURLs[m.config.NotesCalendar]
+	}
+	names := make([]string, 0, len(m.calendarURLs))
+	for name := range m.calendarURLs {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", ""
+	}
+	sort.Strings(names)
+	return names[0], m.calendarURLs[names[0]]
+}
+
+// loadNotesCmd fetches the VJOURNALs of the notes calendar
+func loadNotesCmd(calendarURL, calendarName string, config *RadicaleConfig) tea.Cmd {
+	return func() tea.Msg {
+		docs, err := newCalDAVClient(config).FetchCalendar(calendarURL, nil)
+		if err != nil {
+			return notesLoadedMsg{err: err}
+		}
+		var notes []ical.Journal
+		for _, doc := range docs {
+			journals, err := ical.ParseJournals(strings.NewReader(doc), calendarName)
+			if err != nil {
+				return notesLoadedMsg{err: err}
+			}
+			notes = append(notes, journals...)
+		}
+		return notesLoadedMsg{notes: notes}
+	}
+}
+
+// refreshNotes reloads the daily notes if a notes calendar is available
+func (m model) refreshNotes() tea.Cmd {
+	name, url := m.notesCalendar()
+	if url == "" {
+		return nil
+	}
+	return loadNotesCmd(url, name, m.radicaleConfig)
+}
+
+func (m model) applyNotes(msg notesLoadedMsg) model {
+	if msg.err != nil {
+		return m
+	}
+	m.notes = make(map[string]ical.Journal)
+	for _, note := range msg.notes {
+		m.notes[noteKey(note.Date)] = note
+	}
+	return m
+}
+
+// startNote opens the note editor for the current day
+func (m model) startNote() (model, tea.Cmd) {
+	if _, url := m.notesCalendar(); url == "" {
+		m.message = tr("Daily notes need a Radicale calendar")
+		return m, nil
+	}
+
+	text := ""
+	if note, ok := m.notes[noteKey(m.currentDate)]; ok {
+		text = note.Description
+	}
+	m.noteText = &text
+	m.noteForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title(fmt.Sprintf(tr("Note for %s"), formatDate(m.currentDate, "Monday, January 2"))).
+				Description(tr("Leave empty to delete the note")).
+				Value(m.noteText),
+		),
+	).WithShowHelp(true)
+	if m.width > 0 {
+		m.noteForm = m.noteForm.WithWidth(m.width)
+	}
+	return m, m.noteForm.Init()
+}
+
+// updateNoteForm passes messages to the note editor while it is open
+func (m model) updateNoteForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.noteForm = nil
+		return m, nil
+	}
+
+	form, cmd := m.noteForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.noteForm = f
+	}
+
+	switch m.noteForm.State {
+	case huh.StateCompleted:
+		m.noteForm = nil
+		return m, m.saveNoteCmd(strings.TrimSpace(*m.noteText))
+	case huh.StateAborted:
+		m.noteForm = nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// saveNoteCmd writes the current day's note, or deletes it if text is empty
+func (m model) saveNoteCmd(text string) tea.Cmd {
+	name, url := m.notesCalendar()
+	note, exists := m.notes[noteKey(m.currentDate)]
+	if !exists {
+		if text == "" {
+			return nil
+		}
+		note = ical.Journal{UID: ical.NewUID(), Date: m.currentDate, CalendarName: name}
+	}
+
+	config := m.radicaleConfig
+	return func() tea.Msg {
+		client := newCalDAVClient(config)
+		if text == "" {
+			return noteSavedMsg{note: note, deleted: true, err: client.Delete(url, note.UID)}
+		}
+
+		// The first line doubles as the title other clients show
+		note.Summary, _, _ = strings.Cut(text, "\n")
+		note.Description = text
+		return noteSavedMsg{note: note, err: client.Put(url, note.UID, ical.BuildJournal(note))}
+	}
+}
+
+func (m model) applyNoteSaved(msg noteSavedMsg) model {
+	if msg.err != nil {
+		m.message = fmt.Sprintf(tr("Failed to save note: %v"), msg.err)
+		return m
+	}
+	if msg.deleted {
+		delete(m.notes, noteKey(msg.note.Date))
+		m.message = tr("Note deleted")
+	} else {
+		m.notes[noteKey(msg.note.Date)] = msg.note
+		m.message = tr("Note saved")
+	}
+	return m
+}
+
+// renderNote shows the day's note under the date header
+func (m model) renderNote() string {
+	note, ok := m.notes[noteKey(m.currentDate)]
+	if !ok {
+		return ""
+	}
+	text := note.Description
+	if m.redact {
+		text = redactedDescription
+	}
+	return noteStyle.Render("✎ "+text) + "\n"
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// notifyAction is a button of a desktop notification
+type notifyAction struct {
+	id, label string
+}
+
+// notify shows a desktop notification with notify-send. With actions it
+// waits until one is chosen and returns its id, or "" if the notification
+// was closed; without, it returns at once.
+func notify(summary, body string, actions ...notifyAction) (string, error) {
+	args := []string{"--app-name=zebracal"}
+	if len(actions) > 0 {
+		args = append(args, "--wait")
+	}
+	for _, action := range actions {
+		args = append(args, "--action="+action.id+"="+action.label)
+	}
+	args = append(args, summary)
+	if body != "" {
+		args = append(args, body)
+	}
+	cmd := exec.Command("notify-send", args...)
+
+	if len(actions) == 0 {
+		if err := cmd.Start(); err != nil {
+			return "", err
+		}
+		go cmd.Wait()
+		return "", nil
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
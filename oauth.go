@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"mytuiapp/internal/oauth"
+)
+
+// loadToken reads an OAuth token from the state directory, nil if the user
+// hasn't logged in yet
+func loadToken(name string) (*oauth.Token, error) {
+	tokenPath, err := getStatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(tokenPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var token oauth.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// saveToken stores the token readable only by the user
+func saveToken(name string, token *oauth.Token) error {
+	tokenPath, err := getStatePath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(tokenPath, data, 0600)
+}
+
+// validToken returns the saved token, refreshing and saving it if it expired
+func validToken(client *oauth.Client, name string) (*oauth.Token, error) {
+	token, err := loadToken(name)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid() {
+		if token, err = client.Refresh(token); err != nil {
+			return nil, err
+		}
+		if err := saveToken(name, token); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// deviceLogin runs the device flow on the terminal and saves the token
+func deviceLogin(client *oauth.Client, name string) (*oauth.Token, error) {
+	code, err := client.StartDevice()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Open %s and enter the code %s\nWaiting for authorization...\n", code.VerificationURI, code.UserCode)
+
+	token, err := client.WaitForToken(code)
+	if err != nil {
+		return nil, err
+	}
+	return token, saveToken(name, token)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/oauth"
+	"mytuiapp/internal/outlook"
+)
+
+const outlookTokenFile = "outlook_token.json"
+
+// outlookOAuth returns the OAuth client configured under "outlook"
+func outlookOAuth(config *OutlookConfig) (*oauth.Client, error) {
+	if config == nil || config.ClientID == "" {
+		return nil, fmt.Errorf(`set "outlook": {"client_id": ..., "tenant": ...} in the config`)
+	}
+	return outlook.OAuth(httpClient, config.ClientID, config.Tenant), nil
+}
+
+// outlookClient returns a Graph client with a valid access token
+func outlookClient(config *OutlookConfig) (*outlook.Client, error) {
+	client, err := outlookOAuth(config)
+	if err != nil {
+		return nil, err
+	}
+	token, err := validToken(client, outlookTokenFile)
+	if err != nil {
+		return nil, err
+	}
+	return &outlook.Client{HTTP: httpClient, Token: token}, nil
+}
+
+// outlookProvider reads an Exchange Online calendar. Outlook calendars are
+// read-only.
+type outlookProvider struct {
+	singleCalendar
+	readOnly
+	config *OutlookConfig
+}
+
+func newOutlookProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	return &outlookProvider{singleCalendar: singleCalendar{entry}, config: config.Outlook}
+}
+
+func (p *outlookProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	client, err := outlookClient(p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.Events(cal.CalendarID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, item := range items {
+		if item.IsCancelled {
+			continue
+		}
+		start, end, err := item.Times(time.Local)
+		if err != nil {
+			continue
+		}
+
+		summary := item.Subject
+		if summary == "" {
+			summary = "(No title)"
+		}
+		transp := ""
+		if item.ShowAs == "free" {
+			transp = "TRANSPARENT"
+		}
+		var attendees []string
+		for _, attendee := range item.Attendees {
+			attendees = append(attendees, strings.ToLower(attendee.EmailAddress.Address))
+		}
+		events = append(events, Event{
+			Summary:      summary,
+			Start:        start,
+			End:          end,
+			Description:  item.BodyPreview,
+			Location:     item.Location.DisplayName,
+			CalendarName: cal.Name,
+			UID:          item.ICalUID,
+			LastModified: item.LastModifiedDateTime,
+			Transp:       transp,
+			Organizer:    strings.ToLower(item.Organizer.EmailAddress.Address),
+			Attendees:    attendees,
+		})
+	}
+	return events, nil
+}
+
+// runOutlookLoginCommand authorizes zebracal for the user's Exchange Online
+// mailbox with the device flow and lists its calendars
+func runOutlookLoginCommand() {
+	config, _ := loadConfig()
+	var outlookConfig *OutlookConfig
+	if config != nil {
+		if err := setupHTTPClient(config.HTTP); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		outlookConfig = config.Outlook
+	}
+
+	client, err := outlookOAuth(outlookConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	token, err := deviceLogin(client, outlookTokenFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	calendars, err := (&outlook.Client{HTTP: httpClient, Token: token}).Calendars()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Logged in. Add calendars to the config like")
+	fmt.Println(`  {"name": "Work", "type": "outlook"}`)
+	fmt.Println("and set \"calendar_id\" for calendars other than the default one:")
+	fmt.Println()
+	for _, cal := range calendars {
+		name := cal.Name
+		if cal.IsDefaultCalendar {
+			name += " (default)"
+		}
+		fmt.Printf("  %-40s %s\n", cal.ID, name)
+	}
+}
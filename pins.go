@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const pinFile = "pins.json"
+
+// pinnedEvents are the seriesIDs of the events pinned with '*', which are
+// starred and listed first. Pinning an occurrence pins its series.
+var pinnedEvents = make(map[string]bool)
+
+func isPinned(event Event) bool {
+	return pinnedEvents[seriesID(event)]
+}
+
+func loadPins() error {
+	pinPath, err := getStatePath(pinFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(pinPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		pinnedEvents[id] = true
+	}
+	return nil
+}
+
+func savePins() error {
+	pinPath, err := getStatePath(pinFile)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(pinnedEvents))
+	for id := range pinnedEvents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pinPath, data, 0644)
+}
+
+// pinnedOnly keeps the pinned events (--pinned)
+func pinnedOnly(events []Event) []Event {
+	var pinned []Event
+	for _, event := range events {
+		if isPinned(event) {
+			pinned = append(pinned, event)
+		}
+	}
+	return pinned
+}
+
+// eventMarker is the bullet before an event's title, a star if pinned and
+// hollow for events that leave the time free
+func eventMarker(event Event) string {
+	if isPinned(event) {
+		return "★ "
+	}
+	if transparent(event) {
+		return "○ "
+	}
+	return "● "
+}
+
+// togglePin pins the focused event of the daily view ('*'), or unpins it.
+// The cursor follows the event to its new place in the list.
+func (m model) togglePin() model {
+	if m.viewMode != DailyView {
+		return m
+	}
+	dayEvents := m.dailyEvents()
+	if m.cursor >= len(dayEvents) {
+		return m
+	}
+	event := dayEvents[m.cursor]
+
+	id := seriesID(event)
+	pinned := !pinnedEvents[id]
+	if pinned {
+		pinnedEvents[id] = true
+	} else {
+		delete(pinnedEvents, id)
+	}
+	if err := savePins(); err != nil {
+		m.message = fmt.Sprintf("Error: %v", err)
+		return m
+	}
+	if pinned {
+		m.message = fmt.Sprintf(tr("Pinned %s"), m.eventTitle(event))
+	} else {
+		m.message = fmt.Sprintf(tr("Unpinned %s"), m.eventTitle(event))
+	}
+
+	for i, e := range m.dailyEvents() {
+		if eventKey(e) == eventKey(event) {
+			m.cursor = i
+		}
+	}
+	return m
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"mytuiapp/internal/pdf"
+)
+
+// Layout of the printed month, in points
+const (
+	printMargin     = 36
+	printHeader     = 18 // Row of weekday names
+	printEventSize  = 7  // Font size of the events
+	printEventLine  = 9
+	printDaySize    = 11
+	printTitleSize  = 22
+	printTitleSpace = 44 // Room for the title above the weekday names
+)
+
+var (
+	printInk   = pdf.Color{R: 34, G: 34, B: 34}
+	printMuted = pdf.Color{R: 150, G: 150, B: 150}
+	printRule  = pdf.Color{R: 190, G: 190, B: 190}
+	printShade = pdf.Color{R: 243, G: 243, B: 243}
+)
+
+// parsePrintMonth parses the month of --print-month, as YYYY-MM
+func parsePrintMonth(value string) (time.Time, error) {
+	month, err := time.ParseInLocation("2006-01", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a month like 2025-06, got %q", value)
+	}
+	return month, nil
+}
+
+// writeMonthPDF prints the month to a PDF file at path
+func (m model) writeMonthPDF(month time.Time, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := m.monthPDF(month).WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// monthPDF lays out the month as a classic wall calendar on a landscape A4
+// page: a grid of weeks with the events listed in each day
+func (m model) monthPDF(month time.Time) *pdf.Document {
+	var doc pdf.Document
+	page := doc.AddPage(pdf.A4Width, pdf.A4Height)
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	gridStart := m.getWeekStart(first)
+	weeks := (dayIndex(gridStart, first.AddDate(0, 1, -1)) + 7) / 7
+
+	page.Text(printMargin, printMargin+printTitleSize, pdf.HelveticaBold, printTitleSize, printInk, formatDate(first, "January 2006"))
+
+	top := float64(printMargin + printTitleSpace)
+	cellWidth := (pdf.A4Width - 2*printMargin) / 7.0
+	cellHeight := (pdf.A4Height - printMargin - top - printHeader) / float64(weeks)
+	for i := 0; i < 7; i++ {
+		name := formatDate(gridStart.AddDate(0, 0, i), "Monday")
+		page.Text(printMargin+float64(i)*cellWidth+4, top+printHeader-6, pdf.HelveticaBold, 9, printMuted, name)
+	}
+	top += printHeader
+
+	for week := 0; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			date := gridStart.AddDate(0, 0, 7*week+weekday)
+			x := printMargin + float64(weekday)*cellWidth
+			y := top + float64(week)*cellHeight
+			inMonth := date.Month() == first.Month()
+
+			var fill *pdf.Color
+			if !inMonth {
+				fill = &printShade
+			}
+			page.Rect(x, y, cellWidth, cellHeight, fill, &printRule, 0.5)
+			dayColor := printInk
+			if !inMonth {
+				dayColor = printMuted
+			}
+			page.Text(x+4, y+printDaySize+3, pdf.HelveticaBold, printDaySize, dayColor, fmt.Sprint(date.Day()))
+			if inMonth {
+				m.printDayEvents(page, date, x, y+printDaySize+6, cellWidth, y+cellHeight)
+			}
+		}
+	}
+	return &doc
+}
+
+// printDayEvents lists the events of a day in its cell from y down to
+// bottom, with a line for the rest when they don't all fit
+func (m model) printDayEvents(page *pdf.Page, date time.Time, x, y, width, bottom float64) {
+	events := m.getEventsForDay(date)
+	lines := int((bottom - y - 3) / printEventLine)
+	for i, event := range events {
+		baseline := y + float64(i+1)*printEventLine
+		if i == lines-1 && len(events) > lines {
+			page.Text(x+4, baseline, pdf.Helvetica, printEventSize, printMuted, fmt.Sprintf(tr("and %d more"), len(events)-i))
+			return
+		}
+		if i >= lines {
+			return
+		}
+
+		color := printInk
+		if r, g, b, ok := rgbColor(eventColor(event)); ok {
+			color = pdf.Color{R: r, G: g, B: b}
+		}
+		page.Rect(x+4, baseline-5, 4, 4, &color, nil, 0)
+
+		text := m.eventTitle(event)
+		if !isAllDay(event) {
+			text = event.Start.Format("15:04") + " " + text
+		}
+		page.Text(x+11, baseline, pdf.Helvetica, printEventSize, printInk, pdf.Truncate(pdf.Helvetica, printEventSize, text, width-15))
+	}
+}
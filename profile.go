@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"mytuiapp/internal/ical"
+)
+
+// startupProfile times the steps of startup for --profile, nil without it
+var startupProfile *profile
+
+// profile records how long each step of startup took, until the first
+// render. Its methods do nothing on a nil profile.
+type profile struct {
+	mu      sync.Mutex
+	started time.Time
+	last    time.Time // End of the last step
+	steps   []profileStep
+	timing  *ical.Timing // Of the calendar being loaded
+	done    bool
+}
+
+type profileStep struct {
+	name   string
+	took   time.Duration
+	timing *ical.Timing // For calendars
+	events int
+}
+
+func newProfile(started time.Time) *profile {
+	return &profile{started: started, last: started}
+}
+
+// step ends a step of startup
+func (p *profile) step(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endStep(profileStep{name: name})
+}
+
+func (p *profile) endStep(step profileStep) {
+	if p.done {
+		return
+	}
+	now := time.Now()
+	step.took = now.Sub(p.last)
+	p.last = now
+	p.steps = append(p.steps, step)
+}
+
+// startCalendar starts timing the load of a calendar, whose parse adds to
+// parseTiming
+func (p *profile) startCalendar() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.done {
+		p.timing = &ical.Timing{}
+	}
+}
+
+// endCalendar ends the load of a calendar started with startCalendar
+func (p *profile) endCalendar(name string, events int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endStep(profileStep{name: name, timing: p.timing, events: events})
+	p.timing = nil
+}
+
+// parseTiming returns the Timing of the calendar being loaded, if any
+func (p *profile) parseTiming() *ical.Timing {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.timing
+}
+
+// rendered ends startup with the first render of the loaded calendars
+func (p *profile) rendered() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endStep(profileStep{name: "first render"})
+	p.done = true
+}
+
+// report prints the steps on stderr. One-shot output that isn't a view
+// counts as the render.
+func (p *profile) report() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.done {
+		p.endStep(profileStep{name: "output"})
+		p.done = true
+	}
+
+	nameWidth := 0
+	for _, step := range p.steps {
+		nameWidth = max(nameWidth, len(step.name))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Startup took %s\n", roundDuration(p.last.Sub(p.started)))
+	for _, step := range p.steps {
+		fmt.Fprintf(&b, "  %-*s %8s", nameWidth, step.name, roundDuration(step.took))
+		if timing := step.timing; timing != nil {
+			parse := timing.Parse - timing.Read - timing.Expand
+			fmt.Fprintf(&b, "  fetch %s, parse %s, expand %s, %d events",
+				roundDuration(step.took-parse-timing.Expand), roundDuration(parse), roundDuration(timing.Expand), step.events)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+// roundDuration rounds to what is worth reading in a profile
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(100 * time.Microsecond)
+}
+
+// startPprof writes a CPU profile to prefix.cpu until the returned function
+// is called, which also writes a heap profile to prefix.heap (--pprof)
+func startPprof(prefix string) (func(), error) {
+	cpu, err := os.Create(prefix + ".cpu")
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, err
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		cpu.Close()
+
+		heap, err := os.Create(prefix + ".heap")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return
+		}
+		defer heap.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"mytuiapp/internal/caldav"
+)
+
+// errReadOnly is returned when writing to a calendar whose backend can only
+// be read
+var errReadOnly = errors.New("calendar is read-only")
+
+// CalendarProvider is a calendar backend. A provider is created for each
+// source in the config and lists the calendars it holds, which are all
+// loaded the same way.
+type CalendarProvider interface {
+	// Discover lists the source's calendars. Each has a name and the
+	// limits of its config entry; writable ones have the URL to write to.
+	// A list cached earlier is reused while it is still fresh at now.
+	Discover(now time.Time, onRetry caldav.RetryFunc) ([]CalendarConfig, error)
+	// FetchEvents returns at least the events of cal overlapping [from, to),
+	// with recurring events expanded from now. Events that can't be read
+	// are left out and reported to warn.
+	FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error)
+	// CreateEvent adds an event, giving it a UID if it has none
+	CreateEvent(cal CalendarConfig, event *Event) error
+	// UpdateEvent replaces the event with the same UID, or adds it
+	UpdateEvent(cal CalendarConfig, event *Event) error
+	DeleteEvent(cal CalendarConfig, event Event) error
+}
+
+// providers creates the provider of a config entry by its type. Backends
+// are added here and to CalendarConfig.Type's documentation.
+var providers = map[string]func(config *Config, entry CalendarConfig) CalendarProvider{
+	"radicale": newRadicaleProvider,
+	"url":      newURLProvider,
+	"file":     newFileProvider,
+	"google":   newGoogleProvider,
+	"outlook":  newOutlookProvider,
+	"ews":      newEWSProvider,
+	"vdir":     newVdirProvider,
+	"store":    newStoreProvider,
+}
+
+// providerType returns an entry's type, "url" or "file" for entries
+// without one
+func providerType(entry CalendarConfig) string {
+	switch {
+	case entry.Type != "":
+		return entry.Type
+	case entry.URL != "":
+		return "url"
+	case entry.File != "":
+		return "file"
+	}
+	return ""
+}
+
+// fetchRange is the range loaded from backends that are queried by date.
+// In low memory mode it is the window around center, the viewed date, and
+// limits all backends.
+func fetchRange(config *Config, center, now time.Time) (time.Time, time.Time) {
+	if days := config.lowMemoryDays(); days > 0 {
+		day := dayStart(center)
+		return day.AddDate(0, 0, -days), day.AddDate(0, 0, days+1)
+	}
+	return now.AddDate(0, -1, 0), now.AddDate(1, 0, 0)
+}
+
+// singleCalendar implements Discover for providers of one calendar, the
+// config entry itself
+type singleCalendar struct {
+	entry CalendarConfig
+}
+
+func (s singleCalendar) Discover(time.Time, caldav.RetryFunc) ([]CalendarConfig, error) {
+	return []CalendarConfig{s.entry}, nil
+}
+
+// readOnly implements the writes of providers that can't write
+type readOnly struct{}
+
+func (readOnly) CreateEvent(CalendarConfig, *Event) error { return errReadOnly }
+func (readOnly) UpdateEvent(CalendarConfig, *Event) error { return errReadOnly }
+func (readOnly) DeleteEvent(CalendarConfig, Event) error  { return errReadOnly }
+
+// isReadOnly marks providers whose calendars can't be written
+func (readOnly) isReadOnly() {}
+
+// writable reports whether events can be written through the provider
+func writable(provider CalendarProvider) bool {
+	_, ok := provider.(interface{ isReadOnly() })
+	return !ok
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// publishAgendaDays are the days listed on the front page of the site
+const publishAgendaDays = 14
+
+// publishStyle is the stylesheet of the site, style.css
+const publishStyle = `body { font-family: system-ui, sans-serif; margin: 1.5em auto; max-width: 72em; padding: 0 1em; color: #222; }
+h1 { font-size: 1.5em; margin-bottom: 0.2em; }
+nav { margin: 0.5em 0 1em; }
+nav a { margin: 0 0.5em; }
+table.month { border-collapse: collapse; width: 100%; table-layout: fixed; }
+table.month th { text-align: left; font-weight: normal; color: #777; padding: 0.3em; }
+table.month td { border: 1px solid #ddd; vertical-align: top; height: 7em; padding: 0.3em; }
+td.other { background: #f5f5f5; color: #aaa; }
+td.today .day { background: #d6336c; color: #fff; border-radius: 1em; padding: 0 0.4em; }
+.day { font-weight: bold; }
+ul { list-style: none; margin: 0.3em 0 0; padding: 0; }
+li { border-left: 3px solid #999; padding-left: 0.3em; margin-bottom: 0.2em; font-size: 0.85em; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+time { color: #666; font-variant-numeric: tabular-nums; }
+.agenda li { font-size: 1em; white-space: normal; }
+footer { margin-top: 1.5em; color: #999; font-size: 0.85em; }
+`
+
+// publishedCalendar reports whether a calendar is on the site
+func (c *PublishConfig) publishedCalendar(name string) bool {
+	return c == nil || len(c.Calendars) == 0 || slices.Contains(c.Calendars, name)
+}
+
+// busyCalendar reports whether a calendar is published as busy times only
+func (c *PublishConfig) busyCalendar(name string) bool {
+	return c != nil && slices.Contains(c.Busy, name)
+}
+
+func (c *PublishConfig) title() string {
+	if c == nil || c.Title == "" {
+		return tr("Calendar")
+	}
+	return c.Title
+}
+
+func (c *PublishConfig) months() int {
+	if c == nil || c.Months <= 0 {
+		return 3
+	}
+	return c.Months
+}
+
+// publishedEvents returns the events of the published calendars as the
+// site shows them. Descriptions, links and attendees are never published;
+// events of busy calendars and private ones (CLASS:PRIVATE or
+// CONFIDENTIAL) are only "Busy".
+func publishedEvents(events []Event, config *PublishConfig) []Event {
+	var published []Event
+	for _, event := range events {
+		if !config.publishedCalendar(event.CalendarName) {
+			continue
+		}
+		event.Description, event.HTML, event.URL = "", "", ""
+		event.Organizer, event.Attendees, event.AttendeeNames = "", nil, nil
+		event.RelatedTo, event.Raw = "", ""
+		if config.busyCalendar(event.CalendarName) || event.Class == "PRIVATE" || event.Class == "CONFIDENTIAL" {
+			event.Summary = tr("Busy")
+			event.Location = ""
+			event.Since, event.Anniversary = 0, ""
+		}
+		published = append(published, event)
+	}
+	return published
+}
+
+// publishSite writes the site to dir: a page per month from the current
+// one, a front page with the coming days and agenda.json with the events
+// of all the published months
+func (m model) publishSite(dir string, config *PublishConfig) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	today := dayStart(m.clock.Now())
+	first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	months := make([]time.Time, config.months())
+	for i := range months {
+		months[i] = first.AddDate(0, i, 0)
+	}
+
+	files := map[string]string{
+		"style.css":  publishStyle,
+		"index.html": m.publishIndex(config.title(), today, months),
+	}
+	for _, month := range months {
+		files[month.Format("2006-01")+".html"] = m.publishMonth(config.title(), month, months)
+	}
+
+	var agenda []Event
+	end := first.AddDate(0, len(months), 0)
+	for _, event := range m.events {
+		if event.End.After(first) && event.Start.Before(end) {
+			agenda = append(agenda, event)
+		}
+	}
+	slices.SortStableFunc(agenda, func(a, b Event) int { return a.Start.Compare(b.Start) })
+	json, err := renderEventsJSON(agenda)
+	if err != nil {
+		return err
+	}
+	files["agenda.json"] = json + "\n"
+
+	for name, content := range files {
+		// Readers of a synced directory never see half a page
+		if err := writeFileAtomic(filepath.Join(dir, name), []byte(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishPage wraps the body of a page of the site
+func publishPage(title, heading, nav, body string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<link rel=\"stylesheet\" href=\"style.css\">\n</head>\n<body>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<nav>%s</nav>\n%s", html.EscapeString(heading), nav, body)
+	fmt.Fprintf(&b, "<footer>%s</footer>\n</body>\n</html>\n",
+		html.EscapeString(fmt.Sprintf(tr("Updated %s"), formatDate(time.Now(), "Mon Jan 2, 2006 15:04"))))
+	return b.String()
+}
+
+// publishNav links the front page and the month pages, marking current
+func publishNav(months []time.Time, current time.Time) string {
+	var links []string
+	if current.IsZero() {
+		links = append(links, "<strong>"+html.EscapeString(tr("Coming up"))+"</strong>")
+	} else {
+		links = append(links, "<a href=\"index.html\">"+html.EscapeString(tr("Coming up"))+"</a>")
+	}
+	for _, month := range months {
+		name := html.EscapeString(formatDate(month, "January 2006"))
+		if month.Equal(current) {
+			links = append(links, "<strong>"+name+"</strong>")
+		} else {
+			links = append(links, fmt.Sprintf("<a href=\"%s.html\">%s</a>", month.Format("2006-01"), name))
+		}
+	}
+	return strings.Join(links, " · ")
+}
+
+// publishEventItem renders an event as a list item in its calendar's color
+func (m model) publishEventItem(event Event) string {
+	when := ""
+	if !isAllDay(event) {
+		when = "<time>" + event.Start.Format("15:04") + "</time> "
+	}
+	text := html.EscapeString(m.eventTitle(event))
+	if location := m.eventLocation(event); location != "" {
+		text += " <small>(" + html.EscapeString(location) + ")</small>"
+	}
+	return fmt.Sprintf("<li style=\"border-color: %s\">%s%s</li>\n", cssColor(eventColor(event)), when, text)
+}
+
+// publishIndex renders the front page: the events of the coming days
+func (m model) publishIndex(title string, today time.Time, months []time.Time) string {
+	var b strings.Builder
+	b.WriteString("<ul class=\"agenda\">\n")
+	empty := true
+	for i := 0; i < publishAgendaDays; i++ {
+		day := today.AddDate(0, 0, i)
+		events := m.getEventsForDay(day)
+		if len(events) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "<li><strong>%s</strong>\n<ul>\n", html.EscapeString(formatDate(day, "Monday, January 2")))
+		for _, event := range events {
+			b.WriteString(m.publishEventItem(event))
+		}
+		b.WriteString("</ul>\n</li>\n")
+	}
+	b.WriteString("</ul>\n")
+	if empty {
+		b.Reset()
+		b.WriteString("<p>" + html.EscapeString(tr("No events scheduled")) + "</p>\n")
+	}
+	return publishPage(title, title, publishNav(months, time.Time{}), b.String())
+}
+
+// publishMonth renders the page of a month, a grid of its weeks
+func (m model) publishMonth(title string, month time.Time, months []time.Time) string {
+	today := dayStart(m.clock.Now())
+	gridStart := m.getWeekStart(month)
+	weeks := (dayIndex(gridStart, month.AddDate(0, 1, -1)) + 7) / 7
+
+	var b strings.Builder
+	b.WriteString("<table class=\"month\">\n<thead><tr>")
+	for _, name := range weekdayHeaders() {
+		b.WriteString("<th>" + html.EscapeString(name) + "</th>")
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for week := 0; week < weeks; week++ {
+		b.WriteString("<tr>\n")
+		for weekday := 0; weekday < 7; weekday++ {
+			date := gridStart.AddDate(0, 0, 7*week+weekday)
+			class := ""
+			switch {
+			case date.Month() != month.Month():
+				class = " class=\"other\""
+			case date.Equal(today):
+				class = " class=\"today\""
+			}
+			fmt.Fprintf(&b, "<td%s><span class=\"day\">%d</span>", class, date.Day())
+			if date.Month() == month.Month() {
+				if events := m.getEventsForDay(date); len(events) > 0 {
+					b.WriteString("\n<ul>\n")
+					for _, event := range events {
+						b.WriteString(m.publishEventItem(event))
+					}
+					b.WriteString("</ul>\n")
+				}
+			}
+			b.WriteString("</td>\n")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+
+	heading := formatDate(month, "January 2006")
+	return publishPage(title+" – "+heading, heading, publishNav(months, month), b.String())
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mytuiapp/internal/ical"
+)
+
+const pendingOpsFile = "pending.json"
+
+// pendingOp is a server write that failed because the server was unreachable.
+// Ops are journaled to disk and replayed in order on the next refresh.
+type pendingOp struct {
+	Kind        string    `json:"kind"` // "put" or "delete"
+	CalendarURL string    `json:"calendar_url"`
+	Event       Event     `json:"event"`
+	QueuedAt    time.Time `json:"queued_at"`
+}
+
+// queueMu guards the journal, which is written from the UI and from commands
+var queueMu sync.Mutex
+
+// isOfflineError reports whether err means the server could not be reached
+// (as opposed to the server rejecting the request)
+func isOfflineError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func loadPendingOps() ([]pendingOp, error) {
+	journalPath, err := getStatePath(pendingOpsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ops []pendingOp
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func savePendingOps(ops []pendingOp) error {
+	journalPath, err := getStatePath(pendingOpsFile)
+	if err != nil {
+		return err
+	}
+
+	if len(ops) == 0 {
+		if err := os.Remove(journalPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(journalPath, data, 0644)
+}
+
+func enqueueOp(op pendingOp) error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	ops, err := loadPendingOps()
+	if err != nil {
+		return err
+	}
+	op.QueuedAt = time.Now()
+	return savePendingOps(append(ops, op))
+}
+
+func countPendingOps() int {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	ops, _ := loadPendingOps()
+	return len(ops)
+}
+
+// writeProvider returns the provider that writes to a calendar URL: the
+// vdir for file:// URLs, the store for ics+file:// ones, the Radicale
+// server otherwise
+func writeProvider(calendarURL string, config *RadicaleConfig) CalendarProvider {
+	if strings.HasPrefix(calendarURL, vdirScheme) {
+		return &vdirProvider{}
+	}
+	if strings.HasPrefix(calendarURL, storeScheme) {
+		return &storeProvider{}
+	}
+	return &radicaleProvider{account: config}
+}
+
+// putEventOrQueue creates or updates an event on the server. If the server is
+// unreachable the write is queued and queued is true.
+func putEventOrQueue(calendarURL string, event *Event, config *RadicaleConfig) (queued bool, err error) {
+	if event.UID == "" {
+		event.UID = ical.NewUID()
+	}
+
+	err = writeProvider(calendarURL, config).UpdateEvent(CalendarConfig{URL: calendarURL}, event)
+	if err == nil || !isOfflineError(err) {
+		return false, err
+	}
+
+	if qerr := enqueueOp(pendingOp{Kind: "put", CalendarURL: calendarURL, Event: *event}); qerr != nil {
+		return false, fmt.Errorf("%v (and failed to queue: %v)", err, qerr)
+	}
+	return true, nil
+}
+
+// deleteEventOrQueue deletes an event on the server, queueing the delete if
+// the server is unreachable
+func deleteEventOrQueue(calendarURL string, event Event, config *RadicaleConfig) (queued bool, err error) {
+	err = writeProvider(calendarURL, config).DeleteEvent(CalendarConfig{URL: calendarURL}, event)
+	if err == nil || !isOfflineError(err) {
+		return false, err
+	}
+
+	if qerr := enqueueOp(pendingOp{Kind: "delete", CalendarURL: calendarURL, Event: event}); qerr != nil {
+		return false, fmt.Errorf("%v (and failed to queue: %v)", err, qerr)
+	}
+	return true, nil
+}
+
+// flushPendingOps replays queued ops in order. It stops at the first op that
+// still can't reach the server; ops the server rejects are dropped.
+func flushPendingOps(config *RadicaleConfig) (flushed int, failed int, remaining int, err error) {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	ops, err := loadPendingOps()
+	if err != nil || len(ops) == 0 || config == nil {
+		return 0, 0, len(ops), err
+	}
+
+	i := 0
+	for ; i < len(ops); i++ {
+		op := ops[i]
+		cal := CalendarConfig{URL: op.CalendarURL}
+		var opErr error
+		switch op.Kind {
+		case "put":
+			opErr = writeProvider(op.CalendarURL, config).UpdateEvent(cal, &op.Event)
+		case "delete":
+			opErr = writeProvider(op.CalendarURL, config).DeleteEvent(cal, op.Event)
+		}
+
+		if opErr != nil && isOfflineError(opErr) {
+			break
+		}
+		if opErr != nil {
+			failed++
+			err = opErr
+		} else {
+			flushed++
+		}
+	}
+
+	remainingOps := ops[i:]
+	if saveErr := savePendingOps(remainingOps); saveErr != nil {
+		err = saveErr
+	}
+	return flushed, failed, len(remainingOps), err
+}
+
+func flushQueueCmd(config *RadicaleConfig) tea.Cmd {
+	return func() tea.Msg {
+		flushed, failed, remaining, err := flushPendingOps(config)
+		return queueFlushedMsg{flushed: flushed, failed: failed, remaining: remaining, err: err}
+	}
+}
+
+func (m model) applyQueueFlush(msg queueFlushedMsg) model {
+	m.pendingCount = msg.remaining
+	switch {
+	case msg.failed > 0:
+		m.message = fmt.Sprintf("Synced %d pending changes, %d rejected by server: %v", msg.flushed, msg.failed, msg.err)
+	case msg.flushed > 0:
+		m.message = fmt.Sprintf("Synced %d pending changes", msg.flushed)
+	case msg.err != nil:
+		m.message = fmt.Sprintf("Error: %v", msg.err)
+	}
+	return m
+}
+
+func pendingBadge(count int) string {
+	if count == 1 {
+		return tr("1 pending change")
+	}
+	return fmt.Sprintf(tr("%d pending changes"), count)
+}
+
+// unsyncedCount counts the changes lost or left behind by quitting now: the
+// queued writes and the events created in calendars without a server or
+// vdir, which only live in memory
+func (m model) unsyncedCount() int {
+	count := countPendingOps()
+	for _, event := range m.events {
+		if _, ok := m.dirty[seriesID(event)]; ok && m.calendarURLs[event.CalendarName] == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// quit leaves the app, clearing the terminal title first
+func (m model) quit() tea.Cmd {
+	if m.titleEnabled() {
+		return tea.Sequence(tea.SetWindowTitle(""), tea.Quit)
+	}
+	return tea.Quit
+}
+
+// quitPrompt asks whether to quit with unsynced changes
+func quitPrompt(count int) string {
+	if count == 1 {
+		return tr("1 event not yet synced — quit anyway? (y/n)")
+	}
+	return fmt.Sprintf(tr("%d events not yet synced — quit anyway? (y/n)"), count)
+}
+
+// handleQuitConfirm answers the quit prompt. Pressing q again quits too.
+func (m model) handleQuitConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "q", "ctrl+c":
+		return m, m.quit()
+	case "n", "esc":
+		m.confirmQuit = 0
+		m.message = "Cancelled"
+	}
+	return m, nil
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReminderLead = 10 * time.Minute
+	reminderHorizon     = 24 * time.Hour
+	reminderStateFile   = "reminders.json"
+)
+
+// reminderState is persisted between daemon runs so that snoozed and
+// dismissed alarms survive a restart
+type reminderState struct {
+	Snoozed   map[string]time.Time `json:"snoozed"`   // key -> snoozed until
+	Dismissed map[string]time.Time `json:"dismissed"` // key -> occurrence start (for pruning)
+}
+
+type reminder struct {
+	Key   string
+	Event Event
+	Due   time.Time
+}
+
+// shortReminderID returns a short, stable id for use on the command line
+func shortReminderID(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func loadReminderState() (*reminderState, error) {
+	state := &reminderState{
+		Snoozed:   make(map[string]time.Time),
+		Dismissed: make(map[string]time.Time),
+	}
+
+	statePath, err := getStatePath(reminderStateFile)
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return state, err
+	}
+	if state.Snoozed == nil {
+		state.Snoozed = make(map[string]time.Time)
+	}
+	if state.Dismissed == nil {
+		state.Dismissed = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+func (s *reminderState) save() error {
+	statePath, err := getStatePath(reminderStateFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath, data, 0644)
+}
+
+// prune drops state for occurrences that are long gone
+func (s *reminderState) prune(now time.Time) {
+	cutoff := now.Add(-24 * time.Hour)
+	for key, until := range s.Snoozed {
+		if until.Before(cutoff) {
+			delete(s.Snoozed, key)
+		}
+	}
+	for key, start := range s.Dismissed {
+		if start.Before(cutoff) {
+			delete(s.Dismissed, key)
+		}
+	}
+}
+
+func reminderLead(config *Config) time.Duration {
+	if config != nil && config.Reminders != nil && config.Reminders.LeadMinutes > 0 {
+		return time.Duration(config.Reminders.LeadMinutes) * time.Minute
+	}
+	return defaultReminderLead
+}
+
+// pendingReminders returns reminders for upcoming events that were not dismissed,
+// with snoozes applied, sorted by due time
+func pendingReminders(events []Event, state *reminderState, lead time.Duration, now time.Time) []reminder {
+	var reminders []reminder
+	for _, event := range events {
+		if event.Start.After(now.Add(reminderHorizon)) {
+			continue
+		}
+
+		key := eventKey(event)
+		if _, dismissed := state.Dismissed[key]; dismissed {
+			continue
+		}
+
+		// A snooze may run past the start of the event and still fires
+		due := event.Start.Add(-lead)
+		if until, ok := state.Snoozed[key]; ok {
+			due = until
+		} else if !event.Start.After(now) {
+			continue
+		}
+		reminders = append(reminders, reminder{Key: key, Event: event, Due: due})
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].Due.Before(reminders[j].Due)
+	})
+	return reminders
+}
+
+func findReminder(reminders []reminder, id string) *reminder {
+	for i := range reminders {
+		if strings.HasPrefix(shortReminderID(reminders[i].Key), id) {
+			return &reminders[i]
+		}
+	}
+	return nil
+}
+
+func runRemindCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: zebracal remind <list|snooze ID MINUTES|dismiss ID|daemon>")
+		os.Exit(1)
+	}
+
+	config, _ := loadConfig()
+	var radicaleConfig *RadicaleConfig
+	if config != nil {
+		radicaleConfig = config.Radicale
+		setLocale(config.Locale)
+		if err := setupHTTPClient(config.HTTP); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := setFloatingTimezone(config.FloatingTimezone); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	state, err := loadReminderState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to read reminder state: %v\n", err)
+	}
+
+	if args[0] == "daemon" {
+		runReminderDaemon(config, radicaleConfig, state, systemClock{})
+		return
+	}
+
+	now := time.Now()
+	events, _, _, _ := loadAllCalendars(systemClock{}, radicaleConfig, nil, nil)
+	reminders := pendingReminders(events, state, reminderLead(config), now)
+
+	switch args[0] {
+	case "list":
+		if len(reminders) == 0 {
+			fmt.Println(noEventsStyle.Render("No pending reminders"))
+			return
+		}
+		for _, r := range reminders {
+			status := ""
+			if _, ok := state.Snoozed[r.Key]; ok {
+				status = " (snoozed)"
+			}
+			fmt.Printf("%s  %s  %s%s\n",
+				shortReminderID(r.Key),
+				formatDate(r.Due, "Mon 15:04"),
+				r.Event.Summary,
+				status)
+		}
+
+	case "snooze", "dismiss":
+		if len(args) < 2 {
+			fmt.Printf("Usage: zebracal remind %s ID\n", args[0])
+			os.Exit(1)
+		}
+		r := findReminder(reminders, args[1])
+		if r == nil {
+			fmt.Fprintf(os.Stderr, "No pending reminder with id %s\n", args[1])
+			os.Exit(1)
+		}
+
+		if args[0] == "snooze" {
+			minutes := 5
+			if len(args) > 2 {
+				if val, err := strconv.Atoi(args[2]); err == nil && val > 0 {
+					minutes = val
+				}
+			}
+			state.Snoozed[r.Key] = now.Add(time.Duration(minutes) * time.Minute)
+			fmt.Printf("Snoozed %s for %d minutes\n", r.Event.Summary, minutes)
+		} else {
+			delete(state.Snoozed, r.Key)
+			state.Dismissed[r.Key] = r.Event.Start
+			fmt.Printf("Dismissed %s\n", r.Event.Summary)
+		}
+
+		if err := state.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to save reminder state: %v\n", err)
+			os.Exit(1)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown remind command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// runReminderDaemon polls the calendars and fires desktop notifications with
+// snooze/dismiss actions, and mails the reminders if reminders.email is set.
+// It never returns.
+func runReminderDaemon(config *Config, radicaleConfig *RadicaleConfig, state *reminderState, clock Clock) {
+	var mu sync.Mutex
+	lead := reminderLead(config)
+	mailer, err := newReminderMailer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, not sending reminder emails\n", err)
+	}
+	inFlight := make(map[string]bool)
+
+	var events []Event
+	var lastLoad time.Time
+
+	for {
+		now := clock.Now()
+		if now.Sub(lastLoad) > 15*time.Minute {
+			events, _, _, _ = loadAllCalendars(clock, radicaleConfig, nil, nil)
+			lastLoad = now
+		}
+
+		mu.Lock()
+		state.prune(now)
+		for _, r := range pendingReminders(events, state, lead, now) {
+			if r.Due.After(now) || inFlight[r.Key] {
+				continue
+			}
+
+			// Mark as handled before notifying, so a restart while the
+			// notification is open doesn't fire it again
+			delete(state.Snoozed, r.Key)
+			state.Dismissed[r.Key] = r.Event.Start
+			inFlight[r.Key] = true
+
+			if mailer != nil && mailer.wants(r.Event) {
+				go func(event Event) {
+					if err := mailer.send(event, clock.Now()); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: Failed to send reminder email: %v\n", err)
+					}
+				}(r.Event)
+			}
+			go func(r reminder) {
+				action := sendReminderNotification(r.Event)
+
+				mu.Lock()
+				defer mu.Unlock()
+				delete(inFlight, r.Key)
+				if minutes, ok := strings.CutPrefix(action, "snooze"); ok {
+					if val, err := strconv.Atoi(minutes); err == nil {
+						delete(state.Dismissed, r.Key)
+						state.Snoozed[r.Key] = clock.Now().Add(time.Duration(val) * time.Minute)
+					}
+				}
+				if err := state.save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Failed to save reminder state: %v\n", err)
+				}
+			}(r)
+		}
+		if err := state.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save reminder state: %v\n", err)
+		}
+		mu.Unlock()
+
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// sendReminderNotification shows a desktop notification and returns the
+// chosen action ("snooze5", "snooze15", "snooze60", "dismiss" or "")
+func sendReminderNotification(event Event) string {
+	body := fmt.Sprintf("%s - %s", event.Start.Format("15:04"), event.End.Format("15:04"))
+	if event.CalendarName != "" {
+		body += " (" + event.CalendarName + ")"
+	}
+
+	action, err := notify(event.Summary, body,
+		notifyAction{"snooze5", "Snooze 5m"},
+		notifyAction{"snooze15", "Snooze 15m"},
+		notifyAction{"snooze60", "Snooze 1h"},
+		notifyAction{"dismiss", "Dismiss"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to send notification: %v\n", err)
+	}
+	return action
+}
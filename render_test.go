@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// update rewrites the golden files with the current rendering
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenDir holds the expected output of the views
+const goldenDir = "testdata/golden"
+
+// renderWidths are the terminal widths every view is rendered at
+var renderWidths = []int{60, 80, 120}
+
+// renderFixture is a fixed week of events covering the layout edge cases:
+// overlaps, emoji and CJK titles, long descriptions and many calendars
+func renderFixture() ([]Event, map[string]lipgloss.Color, time.Time) {
+	day := time.Date(2026, 1, 14, 0, 0, 0, 0, time.Local)
+	at := func(dayOffset, hour, minute int) time.Time {
+		return day.AddDate(0, 0, dayOffset).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	calendars := map[string]lipgloss.Color{
+		"Work":     calendarColors[0],
+		"Personal": calendarColors[1],
+		"Family":   calendarColors[2],
+	}
+	events := []Event{
+		{UID: "f1", Summary: "Team Standup", Location: "Room 4.01 🏢", Start: at(0, 9, 0), End: at(0, 9, 15), CalendarName: "Work"},
+		{UID: "f2", Summary: "🎉 Release party 🚀", Start: at(0, 12, 0), End: at(0, 13, 30), CalendarName: "Personal",
+			Description: "Bring snacks. " + strings.Repeat("A very long description that needs to be cut off. ", 5)},
+		{UID: "f3", Summary: "会議：四半期レビュー", Start: at(0, 12, 30), End: at(0, 14, 0), CalendarName: "Work",
+			Description: "日本語の説明文がここに入ります。絵文字 🗓️ も含みます。"},
+		{UID: "f4", Summary: "Dentist", Start: at(1, 8, 0), End: at(1, 9, 0), CalendarName: "Family"},
+		{UID: "f5", Summary: "Planning with an unusually long title that will not fit in narrow terminals", Start: at(-1, 14, 0), End: at(-1, 17, 0), CalendarName: "Work"},
+		{UID: "f6", Summary: "Birthday", Start: at(3, 0, 0), End: at(3, 23, 59), CalendarName: "Family"},
+		{UID: "f8", Summary: "Offsite in Lisbon", Start: at(1, 13, 0), End: at(3, 16, 0), CalendarName: "Work"},
+		{UID: "f9", Summary: "Ski week", Start: at(4, 0, 0), End: at(11, 0, 0), CalendarName: "Personal"},
+		{UID: "f7", Summary: "Retro", Start: at(16, 10, 0), End: at(16, 11, 0), CalendarName: "Work"},
+	}
+	setCalendarColors(calendars)
+	return events, calendars, day
+}
+
+// TestRenderGolden renders each view of the fixture at each width and
+// compares it with its golden file. Run with -update to accept changes.
+func TestRenderGolden(t *testing.T) {
+	// Plain text and English so the output doesn't depend on the terminal
+	lipgloss.SetColorProfile(termenv.Ascii)
+	setLocale("en")
+
+	events, calendars, day := renderFixture()
+	// Most views are rendered long after the fixture, with nothing today
+	later := day.AddDate(1, 0, 0)
+	views := []struct {
+		name    string
+		mode    ViewMode
+		density string
+		now     time.Time
+	}{
+		{"daily", DailyView, "", later},
+		{"daily-compact", DailyView, "compact", later},
+		{"weekly", WeeklyView, "", later},
+		{"monthly", MonthlyView, "", later},
+		{"rolling", RollingView, "", later},
+		// During the overlapping lunch events
+		{"daily-now", DailyView, "", day.Add(12*time.Hour + 45*time.Minute)},
+	}
+
+	if *update {
+		if err := os.MkdirAll(goldenDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, view := range views {
+		for _, width := range renderWidths {
+			name := fmt.Sprintf("%s-%d.txt", view.name, width)
+			t.Run(name, func(t *testing.T) {
+				// Three rolling weeks reach across the end of January
+				m := initialModel(view.mode, true, &Config{Density: view.density, RollingWeeks: 3}, fixedClock(view.now))
+				m.events = events
+				m.calendars = calendars
+				m.currentDate = day
+				m.width = width
+				got := m.View() + "\n"
+
+				path := filepath.Join(goldenDir, name)
+				if *update {
+					if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if line, ok := firstDifference(string(want), got); !ok {
+					t.Errorf("differs at line %d (run go test -run TestRenderGolden -update to accept)\n  want: %q\n  got:  %q",
+						line+1, lineAt(string(want), line), lineAt(got, line))
+				}
+			})
+		}
+	}
+}
+
+// firstDifference returns the first line where a and b differ, and true if
+// they are equal
+func firstDifference(a, b string) (int, bool) {
+	if a == b {
+		return 0, true
+	}
+	aLines, bLines := strings.Split(a, "\n"), strings.Split(b, "\n")
+	for i := 0; i < len(aLines) && i < len(bLines); i++ {
+		if aLines[i] != bLines[i] {
+			return i, false
+		}
+	}
+	return min(len(aLines), len(bLines)), false
+}
+
+func lineAt(s string, i int) string {
+	lines := strings.Split(s, "\n")
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/ical"
+)
+
+const replyUsage = `Usage: zebracal reply accept|decline|tentative [--as EMAIL] [--output FILE] < invite.ics`
+
+// replyAnswers are the answers of the reply command by their PARTSTAT
+var replyAnswers = map[string]string{
+	"accept":    ical.PartStatAccepted,
+	"decline":   ical.PartStatDeclined,
+	"tentative": ical.PartStatTentative,
+}
+
+// replyOptions are the flags of the reply command
+type replyOptions struct {
+	as, output string
+}
+
+// replyFlags returns the flags of the reply command, read into opts
+func replyFlags(opts *replyOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("reply", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, replyUsage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.as, "as", "", "Reply as the attendee with this `EMAIL` (default the first invited of the emails in the config)")
+	fs.StringVar(&opts.output, "output", "", "Write the reply to `FILE` instead of stdout")
+	return fs
+}
+
+// runReplyCommand answers the invitation read from stdin with a REPLY for
+// the mail client to send to the organizer
+func runReplyCommand(args []string) {
+	var opts replyOptions
+	fs := replyFlags(&opts)
+
+	// The answer may come before the flags
+	var answers []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			break
+		}
+		answers = append(answers, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(answers) != 1 || replyAnswers[answers[0]] == "" {
+		fmt.Fprintln(os.Stderr, replyUsage)
+		os.Exit(2)
+	}
+
+	attendees := []string{opts.as}
+	if opts.as == "" {
+		config, _ := loadConfig()
+		if config == nil || len(config.Emails) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no emails in the config, use --as to name the attendee")
+			os.Exit(1)
+		}
+		attendees = config.Emails
+	}
+
+	invitation, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var reply string
+	for _, attendee := range attendees {
+		if reply, err = ical.Reply(strings.NewReader(string(invitation)), attendee, replyAnswers[answers[0]], time.Now()); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.output == "" {
+		fmt.Print(reply)
+		return
+	}
+	if err := os.WriteFile(opts.output, []byte(reply), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
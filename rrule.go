@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleUnits name the step of each FREQ, once and in the plural
+var rruleUnits = map[string][2]string{
+	"MINUTELY": {"every minute", "every %d minutes"},
+	"HOURLY":   {"every hour", "every %d hours"},
+	"DAILY":    {"every day", "every %d days"},
+	"WEEKLY":   {"every week", "every %d weeks"},
+	"MONTHLY":  {"every month", "every %d months"},
+	"YEARLY":   {"every year", "every %d years"},
+}
+
+// weekdayCodes are the BYDAY codes by time.Weekday
+var weekdayCodes = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// humanizeRRule describes a recurrence rule as stored, e.g. "every 2 weeks
+// on Tue, Thu until Jun 30, 2026". start is the first occurrence, which
+// rules without BYDAY or BYMONTH follow. Parts it doesn't know are left
+// out, and a rule without a known FREQ is shown as is.
+func humanizeRRule(rule string, start time.Time) string {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			parts[key] = value
+		}
+	}
+	units, ok := rruleUnits[parts["FREQ"]]
+	if !ok {
+		return rule
+	}
+
+	text := tr(units[0])
+	if interval, err := strconv.Atoi(parts["INTERVAL"]); err == nil && interval > 1 {
+		text = fmt.Sprintf(tr(units[1]), interval)
+	}
+
+	var days []string
+	for _, code := range strings.Split(parts["BYDAY"], ",") {
+		if day := humanizeByDay(code); day != "" {
+			days = append(days, day)
+		}
+	}
+	var months []string
+	for _, value := range strings.Split(parts["BYMONTH"], ",") {
+		if month, err := strconv.Atoi(value); err == nil && month >= 1 && month <= 12 {
+			months = append(months, currentLocale.shortMonths[month-1])
+		}
+	}
+
+	switch {
+	case len(days) > 0:
+		text += " " + fmt.Sprintf(tr("on %s"), strings.Join(days, ", "))
+	case parts["BYMONTHDAY"] != "":
+		text += " " + fmt.Sprintf(tr("on day %s"), strings.ReplaceAll(parts["BYMONTHDAY"], ",", ", "))
+	case parts["FREQ"] == "MONTHLY":
+		text += " " + fmt.Sprintf(tr("on day %s"), strconv.Itoa(start.Day()))
+	case parts["FREQ"] == "YEARLY" && len(months) == 0:
+		text += " " + fmt.Sprintf(tr("on %s"), formatDate(start, "Jan 2"))
+	}
+	if len(months) > 0 {
+		text += " " + fmt.Sprintf(tr("in %s"), strings.Join(months, ", "))
+	}
+
+	if until, ok := parseUntil(parts["UNTIL"], start.Location()); ok {
+		text += " " + fmt.Sprintf(tr("until %s"), formatDate(until.In(time.Local), "Jan 2, 2006"))
+	} else if count, err := strconv.Atoi(parts["COUNT"]); err == nil && count > 0 {
+		if count == 1 {
+			text += ", " + tr("once")
+		} else {
+			text += ", " + fmt.Sprintf(tr("%d times"), count)
+		}
+	}
+	return text
+}
+
+// humanizeByDay names a BYDAY entry: "Tue", or "the second Tue" and "the
+// last Fri" with an ordinal. It is "" for codes it doesn't know.
+func humanizeByDay(code string) string {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return ""
+	}
+	weekday := -1
+	for i, c := range weekdayCodes {
+		if c == code[len(code)-2:] {
+			weekday = i
+		}
+	}
+	if weekday < 0 {
+		return ""
+	}
+	name := currentLocale.shortWeekdays[weekday]
+
+	ordinal := strings.TrimPrefix(code[:len(code)-2], "+")
+	if ordinal == "" {
+		return name
+	}
+	n, err := strconv.Atoi(ordinal)
+	switch {
+	case err != nil:
+		return ""
+	case n == -1:
+		return fmt.Sprintf(tr("the last %s"), name)
+	case n >= 1 && n <= len(ordinals):
+		return fmt.Sprintf(tr("the %s %s"), tr(ordinals[n-1]), name)
+	}
+	return ""
+}
+
+// parseUntil reads an UNTIL value, in UTC, floating in loc or a date
+func parseUntil(value string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// timeSlot is a span of free time
+type timeSlot struct {
+	start time.Time
+	end   time.Time
+}
+
+// workingHours returns the working day bounds for date, or false if date is
+// not a working day
+func (c *WorkingHoursConfig) workingHours(date time.Time) (time.Time, time.Time, bool) {
+	startClock, endClock, weekends := "09:00", "17:00", false
+	if c != nil {
+		if c.Start != "" {
+			startClock = c.Start
+		}
+		if c.End != "" {
+			endClock = c.End
+		}
+		weekends = c.Weekends
+	}
+
+	if !weekends && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start, err1 := time.Parse("15:04", startClock)
+	end, err2 := time.Parse("15:04", endClock)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		start, end = time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+	}
+	return atClock(date, start), atClock(date, end), true
+}
+
+// dayLength returns the length of a working day
+func (c *WorkingHoursConfig) dayLength() time.Duration {
+	// Any Monday, so weekends don't matter
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	start, end, _ := c.workingHours(monday)
+	return end.Sub(start)
+}
+
+// bookedTime returns how much of a day is taken by busy events, counting
+// overlapping events once
+func bookedTime(events []Event, day time.Time) time.Duration {
+	from, to := dayStart(day), dayStart(day).AddDate(0, 0, 1)
+	booked := to.Sub(from)
+	for _, slot := range freeSlots(events, from, to) {
+		booked -= slot.end.Sub(slot.start)
+	}
+	return booked
+}
+
+// atClock returns date at the hour and minute of clock
+func atClock(date time.Time, clock time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, date.Location())
+}
+
+// isAllDay reports whether an event spans a whole day. All-day events don't
+// make the day busy.
+func isAllDay(event Event) bool {
+	return event.Start.Hour() == 0 && event.Start.Minute() == 0 &&
+		event.End.Sub(event.Start) >= 23*time.Hour+59*time.Minute
+}
+
+// transparent reports whether an event is marked as free time
+// (TRANSP:TRANSPARENT), like reminders and holidays
+func transparent(event Event) bool {
+	return strings.EqualFold(event.Transp, "TRANSPARENT")
+}
+
+// busy reports whether an event blocks time
+func busy(event Event) bool {
+	return !transparent(event) && !isAllDay(event)
+}
+
+// freeSlots returns the gaps between busy events within [from, to)
+func freeSlots(events []Event, from, to time.Time) []timeSlot {
+	var blocking []Event
+	for _, event := range events {
+		if busy(event) && event.End.After(from) && event.Start.Before(to) {
+			blocking = append(blocking, event)
+		}
+	}
+	sort.Slice(blocking, func(i, j int) bool {
+		return blocking[i].Start.Before(blocking[j].Start)
+	})
+
+	var slots []timeSlot
+	cursor := from
+	for _, event := range blocking {
+		if event.Start.After(cursor) {
+			slots = append(slots, timeSlot{start: cursor, end: event.Start})
+		}
+		if event.End.After(cursor) {
+			cursor = event.End
+		}
+		if event.Start.After(cursor) { // End before start in the source data
+			cursor = event.Start
+		}
+	}
+	if to.After(cursor) {
+		slots = append(slots, timeSlot{start: cursor, end: to})
+	}
+	return slots
+}
+
+// nextQuarterHour rounds t up to a quarter hour, the earliest time free slots
+// are offered from
+func nextQuarterHour(t time.Time) time.Time {
+	rounded := t.Truncate(15 * time.Minute)
+	if rounded.Before(t) {
+		rounded = rounded.Add(15 * time.Minute)
+	}
+	return rounded
+}
+
+// freeWorkingSlots returns the free time of a working day after a point in
+// time, between the given events
+func (m model) freeWorkingSlots(events []Event, date time.Time, after time.Time) []timeSlot {
+	var workingHours *WorkingHoursConfig
+	if m.config != nil {
+		workingHours = m.config.WorkingHours
+	}
+	start, end, ok := workingHours.workingHours(date)
+	if !ok {
+		return nil
+	}
+	if after.After(start) {
+		start = after
+	}
+	if !end.After(start) {
+		return nil
+	}
+	return freeSlots(events, start, end)
+}
+
+// minAvailableSlot is the shortest gap worth offering to others
+const minAvailableSlot = 30 * time.Minute
+
+// availability lists the free working time of the given days, one line per
+// day with free time, e.g. "Tue Oct 20: 09:00–11:30, 14:00–16:00". Known
+// busy times of colleagues count too, so the slots suit a meeting with them.
+func (m model) availability(from time.Time, days int) []string {
+	now := nextQuarterHour(m.clock.Now())
+	events := append(m.colleagueBlocks(), m.events...)
+	var lines []string
+	for i := 0; i < days; i++ {
+		day := from.AddDate(0, 0, i)
+		var free []string
+		for _, slot := range m.freeWorkingSlots(events, day, now) {
+			if slot.end.Sub(slot.start) >= minAvailableSlot {
+				free = append(free, slot.start.Format("15:04")+"–"+slot.end.Format("15:04"))
+			}
+		}
+		if len(free) > 0 {
+			lines = append(lines, formatDate(day, "Mon Jan 2")+": "+strings.Join(free, ", "))
+		}
+	}
+	return lines
+}
+
+// availabilityMessage summarizes the free time of the displayed day, week or
+// month in one line for the status bar
+func (m model) availabilityMessage() string {
+	from, days := m.currentDate, 1
+	switch m.viewMode {
+	case WeeklyView:
+		from, days = m.getWeekStart(m.currentDate), 7
+	case RollingView:
+		from, days = m.getWeekStart(m.currentDate), 7*m.rollingWeeks()
+	case MonthlyView:
+		from = time.Date(m.currentDate.Year(), m.currentDate.Month(), 1, 0, 0, 0, 0, m.currentDate.Location())
+		days = from.AddDate(0, 1, -1).Day()
+	}
+
+	lines := m.availability(from, days)
+	if len(lines) == 0 {
+		return tr("No free time in working hours")
+	}
+	if len(m.colleagueBusy) > 0 {
+		return tr("Free for everyone: ") + strings.Join(lines, "; ")
+	}
+	return tr("Free: ") + strings.Join(lines, "; ")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Terminal sizes below which the views fall back to simpler layouts
+const (
+	gridWidth     = 7 * 12 // Seven bordered month cells
+	gridChrome    = 12     // Lines around the grid: headers, legend and help
+	boxWidth      = 44     // Event boxes of the daily view
+	timelineSplit = 110    // The daily view's timeline beside its event boxes
+	tinyWidth     = 24     // Below these only a message fits
+	tinyHeight    = 9
+	compactHelp   = 30 // Height below which the legend and full help are left out
+)
+
+// sized reports whether the terminal size is known. One-shot output and
+// the first frame before a WindowSizeMsg use the full layouts.
+func (m model) sized() bool {
+	return !m.oneShot && m.width > 0 && m.height > 0
+}
+
+// narrow reports whether event boxes don't fit, so events are listed one
+// line each
+func (m model) narrow() bool {
+	return m.sized() && m.width < boxWidth
+}
+
+// smallGrid reports whether a grid of weeks of month cells doesn't fit, so
+// it is drawn with a line per week and event counts instead of bars
+func (m model) smallGrid(weeks int) bool {
+	return m.sized() && (m.width < gridWidth || m.height < 7*weeks+gridChrome)
+}
+
+// short reports whether the legend and the full help bar are left out to
+// leave the lines to the events
+func (m model) short() bool {
+	return m.sized() && (m.height < compactHelp || m.width < boxWidth)
+}
+
+// tooSmall reports whether the terminal is too small for any view
+func (m model) tooSmall() bool {
+	return m.sized() && (m.width < tinyWidth || m.height < tinyHeight)
+}
+
+func (m model) viewTooSmall() string {
+	message := fmt.Sprintf(tr("Terminal too small (%dx%d, need %dx%d)"), m.width, m.height, tinyWidth, tinyHeight)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(message))
+}
+
+// renderTitle renders a view's title and date line. Short terminals get the
+// date alone, without the blank lines around it.
+func (m model) renderTitle(title, date string) string {
+	if m.short() {
+		return dateHeaderStyle.UnsetMargins().Render(date) + "\n"
+	}
+	return titleStyle.Render(title) + "\n" + dateHeaderStyle.Render(date) + "\n"
+}
+
+// renderFooter renders the legend, status line and help below a view. Short
+// terminals get a single line: the status, or else the essential keys.
+func (m model) renderFooter(sections ...[]string) string {
+	if m.short() {
+		footer := m.renderStatusLine()
+		if footer == "" {
+			footer = renderHelp([]string{"d/w/m/g: views", "← →: navigate", "q: quit"})
+		}
+		// Without the blank line above
+		return strings.TrimLeft(footer, " \n")
+	}
+	return m.renderCalendarLegend() + m.renderStatusLine() + "\n" + renderHelp(sections...)
+}
+
+// weekdayLabels returns the weekday headers cut to fit columns of width
+func weekdayLabels(width int) []string {
+	headers := weekdayHeaders()
+	for i, header := range headers {
+		headers[i] = truncate(header, width)
+	}
+	return headers
+}
+
+// renderSmallGrid renders weeks of days as a line per week, each day with
+// its number and count of events. Zero days are left blank.
+func (m model) renderSmallGrid(weeks [][]time.Time) string {
+	colWidth := max(4, min(12, m.width/7))
+	headerStyle := weekdayHeaderStyle.Width(colWidth).Align(lipgloss.Left)
+
+	var b strings.Builder
+	for _, day := range weekdayLabels(colWidth - 1) {
+		b.WriteString(headerStyle.Render(day))
+	}
+	b.WriteString("\n")
+
+	today := m.clock.Now()
+	for _, week := range weeks {
+		for _, date := range week {
+			if date.IsZero() {
+				b.WriteString(strings.Repeat(" ", colWidth))
+				continue
+			}
+			dayStyle := lipgloss.NewStyle().Bold(true)
+			isToday := dayIndex(today, date) == 0
+			if isToday {
+				dayStyle = dayStyle.Foreground(lipgloss.Color("205")).Underline(true)
+			}
+			events := m.getEventsForDay(date)
+			if color, ok := m.heatColor(events, date); ok {
+				dayStyle = dayStyle.Background(color)
+				if !isToday {
+					dayStyle = dayStyle.Foreground(lipgloss.Color("255"))
+				}
+			}
+
+			// The count follows the day number, leaving a space to the next
+			count, countStyle := "", lipgloss.NewStyle()
+			if len(events) > 0 {
+				count = fmt.Sprintf(" %d", len(events))
+				if colWidth == 4 {
+					// No room for a digit apart from the day number
+					count = "•"
+				} else if len(count) > colWidth-3 {
+					count = " +"
+				}
+				countStyle = countStyle.Foreground(eventColor(events[0]))
+			}
+			b.WriteString(dayStyle.Render(fmt.Sprintf("%2d", date.Day())) + countStyle.Render(fmt.Sprintf("%-*s", colWidth-2, count)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderDayLine renders a day of the week as one line: its first event and
+// how many more there are
+func (m model) renderDayLine(day time.Time, events []Event) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("117")).Render(formatDate(day, "Mon 02") + " ")
+	if len(events) == 0 {
+		return header + dimStyle.Render("–")
+	}
+
+	event := events[0]
+	when := event.Start.Format("15:04") + " "
+	if isAllDay(event) {
+		when = tr("all day") + " "
+	}
+	more := ""
+	if len(events) > 1 {
+		more = fmt.Sprintf(" +%d", len(events)-1)
+	}
+	used := lipgloss.Width(header) + lipgloss.Width(when) + lipgloss.Width(more)
+	return header + timeStyle.Render(when) +
+		lipgloss.NewStyle().Foreground(eventColor(event)).Render(m.fitLine(m.eventTitle(event), used)) +
+		dimStyle.Render(more)
+}
+
+// monthWeeks returns the weeks of the month starting at firstDay, Monday
+// first, with zero times for the days of other months
+func monthWeeks(firstDay time.Time) [][]time.Time {
+	offset := (int(firstDay.Weekday()) + 6) % 7
+	var weeks [][]time.Time
+	for day := firstDay.AddDate(0, 0, -offset); day.Month() == firstDay.Month() || day.Before(firstDay); day = day.AddDate(0, 0, 7) {
+		week := make([]time.Time, 7)
+		for i := range week {
+			if date := day.AddDate(0, 0, i); date.Month() == firstDay.Month() {
+				week[i] = date
+			}
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
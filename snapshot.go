@@ -0,0 +1,221 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	// Only upcoming events within this window are tracked, so that
+	// recurrences sliding into view aren't reported as new
+	snapshotWindow = 30 * 24 * time.Hour
+)
+
+// snapshotEntry is the last-seen state of one event
+type snapshotEntry struct {
+	Summary      string    `json:"summary"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	CalendarName string    `json:"calendar"`
+	Hash         string    `json:"hash"`
+}
+
+// calendarSnapshot is the set of upcoming events seen on the last run
+type calendarSnapshot struct {
+	TakenAt time.Time                `json:"taken_at"`
+	Events  map[string]snapshotEntry `json:"events"`
+}
+
+// eventChange is an event added ("+"), changed ("~") or cancelled ("-")
+// since the last snapshot
+type eventChange struct {
+	Kind   string
+	Before snapshotEntry
+	After  snapshotEntry
+}
+
+// snapshotKey identifies an event across runs. Single events are keyed by
+// UID so a moved event shows up as changed rather than cancelled and re-added.
+func snapshotKey(event Event) string {
+	if event.UID != "" && event.RRule == "" {
+		return event.UID
+	}
+	return eventKey(event)
+}
+
+func takeSnapshot(events []Event, now time.Time) calendarSnapshot {
+	snapshot := calendarSnapshot{TakenAt: now, Events: make(map[string]snapshotEntry)}
+	for _, event := range events {
+		if event.End.Before(now) || event.Start.After(now.Add(snapshotWindow)) {
+			continue
+		}
+		sum := sha1.Sum([]byte(strings.Join([]string{
+			event.Summary,
+			event.Description,
+			event.CalendarName,
+			event.Start.UTC().Format(time.RFC3339),
+			event.End.UTC().Format(time.RFC3339),
+		}, "\x00")))
+		snapshot.Events[snapshotKey(event)] = snapshotEntry{
+			Summary:      event.Summary,
+			Start:        event.Start,
+			End:          event.End,
+			CalendarName: event.CalendarName,
+			Hash:         hex.EncodeToString(sum[:]),
+		}
+	}
+	return snapshot
+}
+
+func loadSnapshot() (*calendarSnapshot, error) {
+	snapshotPath, err := getStatePath(snapshotFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshot calendarSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (s calendarSnapshot) save() error {
+	snapshotPath, err := getStatePath(snapshotFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotPath, data, 0644)
+}
+
+// diffSnapshots compares two snapshots over the window both of them cover:
+// events that ended since the old snapshot aren't cancelled, and events that
+// only entered the window since then aren't new
+func diffSnapshots(old, current calendarSnapshot) []eventChange {
+	now := current.TakenAt
+	horizon := old.TakenAt.Add(snapshotWindow)
+	inWindow := func(e snapshotEntry) bool {
+		return !e.End.Before(now) && !e.Start.After(horizon)
+	}
+
+	var changes []eventChange
+	for key, after := range current.Events {
+		before, existed := old.Events[key]
+		switch {
+		case !existed && inWindow(after):
+			changes = append(changes, eventChange{Kind: "+", After: after})
+		case existed && before.Hash != after.Hash:
+			changes = append(changes, eventChange{Kind: "~", Before: before, After: after})
+		}
+	}
+	for key, before := range old.Events {
+		if _, exists := current.Events[key]; !exists && inWindow(before) {
+			changes = append(changes, eventChange{Kind: "-", Before: before})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].start().Equal(changes[j].start()) {
+			return changes[i].start().Before(changes[j].start())
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+func (c eventChange) start() time.Time {
+	if c.Kind == "-" {
+		return c.Before.Start
+	}
+	return c.After.Start
+}
+
+// checkCalendarChanges diffs events against the last snapshot and stores the
+// new one. The first run has nothing to compare against and reports no changes.
+func checkCalendarChanges(events []Event, now time.Time) ([]eventChange, error) {
+	current := takeSnapshot(events, now)
+	old, err := loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if err := current.save(); err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, nil
+	}
+	return diffSnapshots(*old, current), nil
+}
+
+// String renders a change like "+ Design review Thu 14:00"
+func (c eventChange) String() string {
+	switch c.Kind {
+	case "+":
+		return fmt.Sprintf("+ %s %s", c.After.Summary, formatDate(c.After.Start, "Mon 15:04"))
+	case "-":
+		return fmt.Sprintf(tr("− %s %s cancelled"), c.Before.Summary, formatDate(c.Before.Start, "Mon 15:04"))
+	}
+	if !c.Before.Start.Equal(c.After.Start) {
+		return fmt.Sprintf("~ %s %s → %s", c.After.Summary,
+			formatDate(c.Before.Start, "Mon 15:04"),
+			formatDate(c.After.Start, "Mon 15:04"))
+	}
+	return fmt.Sprintf(tr("~ %s %s updated"), c.After.Summary, formatDate(c.After.Start, "Mon 15:04"))
+}
+
+// renderChanges renders the full change list for --changes
+func renderChanges(changes []eventChange) string {
+	if len(changes) == 0 {
+		return noEventsStyle.Render(tr("No changes since last run"))
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + titleStyle.Render(tr("📅 Changes Since Last Run")) + "\n\n")
+	for _, change := range changes {
+		b.WriteString(" " + change.String() + "\n")
+	}
+	return b.String()
+}
+
+// changesSummary condenses changes into a one-line status message
+func changesSummary(changes []eventChange) string {
+	const maxShown = 3
+	var shown []string
+	for i, change := range changes {
+		if i == maxShown {
+			shown = append(shown, fmt.Sprintf(tr("and %d more"), len(changes)-maxShown))
+			break
+		}
+		shown = append(shown, change.String())
+	}
+	return fmt.Sprintf(tr("Since last run: %s"), strings.Join(shown, ", "))
+}
+
+// changesMessage is the status line shown when calendars changed, without
+// titles in redact mode
+func (m model) changesMessage(changes []eventChange) string {
+	if m.redact {
+		return fmt.Sprintf(tr("%d calendar changes since last run"), len(changes))
+	}
+	return changesSummary(changes)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/ical"
+)
+
+// storeScheme marks calendar URLs that are single .ics files, so writes to
+// them rewrite the file
+const storeScheme = "ics+file://"
+
+// storeMu serializes the rewrites of store files, which batches write
+// concurrently
+var storeMu sync.Mutex
+
+// storeProvider reads and writes a calendar kept in a single .ics file,
+// such as a local store ending in .ics
+type storeProvider struct {
+	singleCalendar
+	config *Config
+}
+
+func newStoreProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	entry.URL = storeScheme + expandHome(entry.Path)
+	return &storeProvider{singleCalendar: singleCalendar{entry}, config: config}
+}
+
+// storePath returns the file of a store calendar
+func storePath(cal CalendarConfig) string {
+	return strings.TrimPrefix(cal.URL, storeScheme)
+}
+
+// FetchEvents loads the file, which doesn't exist before the first write
+func (p *storeProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	events, err := loadICSFromFile(storePath(cal), cal.Name, parseOptions(p.config, cal, from, to, now, true))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return events, err
+}
+
+func (p *storeProvider) CreateEvent(cal CalendarConfig, event *Event) error {
+	if event.UID == "" {
+		event.UID = ical.NewUID()
+	}
+	return p.UpdateEvent(cal, event)
+}
+
+// UpdateEvent replaces the event's UID in the file, or adds it
+func (p *storeProvider) UpdateEvent(cal CalendarConfig, event *Event) error {
+	_, err := rewriteStore(storePath(cal), event.UID, []Event{*event})
+	return err
+}
+
+func (p *storeProvider) DeleteEvent(cal CalendarConfig, event Event) error {
+	found, err := rewriteStore(storePath(cal), event.UID, nil)
+	if err == nil && !found {
+		err = fmt.Errorf("no event %s in %s", event.UID, storePath(cal))
+	}
+	return err
+}
+
+// rewriteStore replaces the event of uid in the file at path with events,
+// reporting whether the file held it
+func rewriteStore(path, uid string, events []Event) (bool, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	doc, found, err := ical.Replace(string(data), uid, events)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	// Sync tools watching the file never see half of it
+	return found, writeFileAtomic(path, []byte(doc))
+}
+
+// localStoreName returns the calendar name of the local store, "" if
+// there is none
+func (c *Config) localStoreName() string {
+	if c == nil || c.LocalStore == nil || c.LocalStore.Path == "" {
+		return ""
+	}
+	if c.LocalStore.Name == "" {
+		return "Local"
+	}
+	return c.LocalStore.Name
+}
+
+// localStoreSource returns the config entry of the local store: a "store"
+// for an .ics file, a vdir otherwise, whose directory is created if missing
+func (c *Config) localStoreSource(warn func(message string)) CalendarConfig {
+	entry := CalendarConfig{Name: c.localStoreName(), Path: c.LocalStore.Path}
+	if strings.EqualFold(filepath.Ext(entry.Path), ".ics") {
+		entry.Type = "store"
+		return entry
+	}
+	entry.Type = "vdir"
+	if err := os.MkdirAll(expandHome(entry.Path), 0755); err != nil {
+		warn(fmt.Sprintf("Failed to create the local store: %v", err))
+	}
+	return entry
+}
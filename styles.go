@@ -1,21 +1,89 @@
 package main
 
 import (
+	"sync"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Color palette for calendars
-var calendarColors = []lipgloss.Color{
-	lipgloss.Color("205"), // Pink
-	lipgloss.Color("117"), // Light Blue
-	lipgloss.Color("229"), // Yellow
-	lipgloss.Color("120"), // Green
-	lipgloss.Color("183"), // Purple
-	lipgloss.Color("216"), // Peach
-	lipgloss.Color("86"),  // Cyan
-	lipgloss.Color("211"), // Light Pink
+// Color palette for calendars, replaced by setPalette
+var calendarColors = palettes["default"]
+
+// shownColors are the colors given to calendars by name as they are loaded.
+// Events only name their calendar; the color is looked up here.
+var shownColors = struct {
+	sync.RWMutex
+	byName map[string]lipgloss.Color
+}{byName: make(map[string]lipgloss.Color)}
+
+// setCalendarColors records the colors of calendars by name
+func setCalendarColors(calendars map[string]lipgloss.Color) {
+	shownColors.Lock()
+	defer shownColors.Unlock()
+	for name, color := range calendars {
+		shownColors.byName[name] = color
+	}
+}
+
+// eventColor is the color of the event's calendar
+func eventColor(event Event) lipgloss.Color {
+	shownColors.RLock()
+	defer shownColors.RUnlock()
+	return shownColors.byName[event.CalendarName]
+}
+
+// Calendar palettes selectable with the "palette" config option
+var palettes = map[string][]lipgloss.Color{
+	"default": {
+		lipgloss.Color("205"), // Pink
+		lipgloss.Color("117"), // Light Blue
+		lipgloss.Color("229"), // Yellow
+		lipgloss.Color("120"), // Green
+		lipgloss.Color("183"), // Purple
+		lipgloss.Color("216"), // Peach
+		lipgloss.Color("86"),  // Cyan
+		lipgloss.Color("211"), // Light Pink
+	},
+	// Okabe-Ito colors, which stay distinct with red-green color blindness
+	"colorblind": {
+		lipgloss.Color("#E69F00"), // Orange
+		lipgloss.Color("#56B4E9"), // Sky Blue
+		lipgloss.Color("#009E73"), // Bluish Green
+		lipgloss.Color("#F0E442"), // Yellow
+		lipgloss.Color("#0072B2"), // Blue
+		lipgloss.Color("#D55E00"), // Vermillion
+		lipgloss.Color("#CC79A7"), // Reddish Purple
+		lipgloss.Color("#999999"), // Grey
+	},
+	// The 16 ANSI colors, for consoles without 256-color support
+	"basic": {
+		lipgloss.Color("13"), // Bright Magenta
+		lipgloss.Color("14"), // Bright Cyan
+		lipgloss.Color("11"), // Bright Yellow
+		lipgloss.Color("10"), // Bright Green
+		lipgloss.Color("12"), // Bright Blue
+		lipgloss.Color("9"),  // Bright Red
+		lipgloss.Color("6"),  // Cyan
+		lipgloss.Color("3"),  // Yellow
+	},
 }
 
+// Backgrounds of the monthly day numbers from a light to an overbooked day,
+// see heatColor
+var heatColors = []lipgloss.Color{
+	lipgloss.Color("22"),  // Dark Green, up to a quarter of the working day
+	lipgloss.Color("28"),  // Green, up to half
+	lipgloss.Color("100"), // Olive, up to three quarters
+	lipgloss.Color("172"), // Orange, up to a full day
+	lipgloss.Color("160"), // Red, more than the working day
+}
+
+// Placeholders shown in redact mode
+const (
+	redactedTitle       = "Event"
+	redactedDescription = "Details hidden"
+)
+
 // Styles
 var (
 	titleStyle = lipgloss.NewStyle().
@@ -72,6 +140,12 @@ var (
 				Width(12).
 				Align(lipgloss.Center)
 
+	promptStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Bold(true).
+			MarginTop(1).
+			Padding(0, 1)
+
 	inputStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("117")).
 			Bold(true)
@@ -83,9 +157,19 @@ var (
 				Foreground(lipgloss.Color("117")).
 				Bold(true)
 
+	noteStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Italic(true).
+			Padding(0, 1).
+			MarginBottom(1)
+
 	summaryStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("63")).
 			Padding(1, 2).
 			Width(30)
+
+	formErrorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true)
 )
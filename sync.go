@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultRefreshInterval = 15 * time.Minute
+
+// eventVersion is the revision of an event as tracked by SEQUENCE and LAST-MODIFIED
+type eventVersion struct {
+	Sequence     int
+	LastModified time.Time
+}
+
+// eventConflict is an event changed both locally and on the server since the
+// last sync. Both sides hold all occurrences of the event.
+type eventConflict struct {
+	local  []Event
+	remote []Event
+}
+
+func versionOf(event Event) eventVersion {
+	return eventVersion{Sequence: event.Sequence, LastModified: event.LastModified}
+}
+
+// newerThan reports whether v is a later revision than other. SEQUENCE wins,
+// LAST-MODIFIED breaks ties.
+func (v eventVersion) newerThan(other eventVersion) bool {
+	if v.Sequence != other.Sequence {
+		return v.Sequence > other.Sequence
+	}
+	return v.LastModified.After(other.LastModified)
+}
+
+func (v eventVersion) equal(other eventVersion) bool {
+	return v.Sequence == other.Sequence && v.LastModified.Equal(other.LastModified)
+}
+
+// seriesID groups all occurrences of an event
+func seriesID(event Event) string {
+	if event.UID != "" {
+		return event.UID
+	}
+	return eventKey(event)
+}
+
+func groupBySeries(events []Event) (map[string][]Event, []string) {
+	groups := make(map[string][]Event)
+	var order []string
+	for _, event := range events {
+		id := seriesID(event)
+		if _, ok := groups[id]; !ok {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], event)
+	}
+	return groups, order
+}
+
+// mergeEvents merges a fresh server copy into the local events. dirty holds the
+// version each unsynced local edit was based on; entries whose edit has since
+// reached the server are removed from it. Non-dirty events prefer the newer
+// revision; dirty events keep the local copy unless the server changed too,
+// which is reported as a conflict (the local copy is kept until resolved).
+func mergeEvents(local, remote []Event, dirty map[string]eventVersion) ([]Event, []eventConflict) {
+	localGroups, localOrder := groupBySeries(local)
+	remoteGroups, remoteOrder := groupBySeries(remote)
+
+	var merged []Event
+	var conflicts []eventConflict
+
+	for _, id := range remoteOrder {
+		remoteEvents := remoteGroups[id]
+		localEvents, hasLocal := localGroups[id]
+		if !hasLocal {
+			merged = append(merged, remoteEvents...)
+			continue
+		}
+
+		localVersion := versionOf(localEvents[0])
+		remoteVersion := versionOf(remoteEvents[0])
+
+		base, isDirty := dirty[id]
+		switch {
+		case isDirty && remoteVersion.equal(localVersion):
+			// The local edit reached the server (e.g. a flushed offline write)
+			delete(dirty, id)
+			merged = append(merged, remoteEvents...)
+		case isDirty && remoteVersion.newerThan(base):
+			conflicts = append(conflicts, eventConflict{local: localEvents, remote: remoteEvents})
+			merged = append(merged, localEvents...)
+		case isDirty, localVersion.newerThan(remoteVersion):
+			merged = append(merged, localEvents...)
+		default:
+			merged = append(merged, remoteEvents...)
+		}
+	}
+
+	// Events missing on the server were deleted there, unless they are
+	// local edits that have not been synced yet
+	for _, id := range localOrder {
+		if _, onServer := remoteGroups[id]; onServer {
+			continue
+		}
+		if _, isDirty := dirty[id]; isDirty {
+			merged = append(merged, localGroups[id]...)
+		}
+	}
+
+	return merged, conflicts
+}
+
+// markDirty records an unsynced local change, remembering the version it was based on
+func (m model) markDirty(before Event) {
+	id := seriesID(before)
+	if _, ok := m.dirty[id]; !ok {
+		m.dirty[id] = versionOf(before)
+	}
+}
+
+func (m model) refreshInterval() time.Duration {
+	if m.config != nil && m.config.RefreshMinutes > 0 {
+		return time.Duration(m.config.RefreshMinutes) * time.Minute
+	}
+	return defaultRefreshInterval
+}
+
+func scheduleRefresh(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+// loadCalendarsCmd reloads all calendars in the background, around center
+// (the viewed date) in low memory mode
+func loadCalendarsCmd(clock Clock, radicaleConfig *RadicaleConfig, center time.Time) tea.Cmd {
+	return func() tea.Msg {
+		var warnings []string
+		events, calendars, calendarURLs, err := loadCalendarsAround(clock, center, radicaleConfig, nil, func(message string) {
+			warnings = append(warnings, message)
+		})
+		if err != nil {
+			return syncErrorMsg{err: err}
+		}
+		return eventsUpdatedMsg{
+			events:       events,
+			calendars:    calendars,
+			calendarURLs: calendarURLs,
+			warnings:     warnings,
+		}
+	}
+}
+
+// loadCalendarsWithProgress does the startup load, streaming progress to the
+// loading view as loadingMsgs and finishing with an initial eventsUpdatedMsg
+// or syncErrorMsg
+func loadCalendarsWithProgress(clock Clock, radicaleConfig *RadicaleConfig) tea.Cmd {
+	updates := make(chan tea.Msg)
+	go func() {
+		var warnings []string
+		events, calendars, calendarURLs, err := loadAllCalendars(clock, radicaleConfig,
+			func(progress float64, message string) {
+				updates <- loadingMsg{progress: progress, message: message, updates: updates}
+			},
+			func(message string) {
+				warnings = append(warnings, message)
+			})
+		if err != nil {
+			updates <- syncErrorMsg{err: err, initial: true}
+		} else {
+			updates <- eventsUpdatedMsg{
+				events:       events,
+				calendars:    calendars,
+				calendarURLs: calendarURLs,
+				warnings:     warnings,
+				initial:      true,
+			}
+		}
+		close(updates)
+	}()
+	return waitForUpdate(updates)
+}
+
+// waitForUpdate delivers the next update from a background operation
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// applyRefresh merges freshly loaded calendars into the model
+func (m model) applyRefresh(msg eventsUpdatedMsg) model {
+	m.calendars = msg.calendars
+	m.calendarURLs = msg.calendarURLs
+
+	merged, conflicts := mergeEvents(m.events, msg.events, m.dirty)
+	m.events = merged
+	m.conflicts = append(m.conflicts, conflicts...)
+	if len(conflicts) > 0 {
+		m.message = fmt.Sprintf("%d conflicting changes", len(conflicts))
+	} else if len(msg.warnings) > 0 {
+		m.message = "Warning: " + strings.Join(msg.warnings, "; ")
+	} else if changes, err := checkCalendarChanges(msg.events, m.clock.Now()); err == nil && len(changes) > 0 {
+		m.message = m.changesMessage(changes)
+	}
+	return m
+}
+
+func (m model) handleConflictKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	conflict := m.conflicts[0]
+	id := seriesID(conflict.local[0])
+
+	var keep []Event
+	var cmd tea.Cmd
+	key := msg.String()
+	if key == "k" && conflict.local[0].RRule != "" {
+		// Only single events are pushed, so a kept series would never
+		// reach the server
+		key = "s"
+	}
+	switch key {
+	case "k": // Keep mine, push it to the server again
+		keep = conflict.local
+		local := conflict.local[0]
+		local.Sequence = max(local.Sequence, conflict.remote[0].Sequence) + 1
+		local.LastModified = m.clock.Now()
+		for i := range keep {
+			keep[i].Sequence = local.Sequence
+			keep[i].LastModified = local.LastModified
+		}
+		m.dirty[id] = versionOf(conflict.remote[0])
+		if url := m.calendarURLs[local.CalendarName]; url != "" {
+			cmd = pushEventCmd(url, id, local, m.radicaleConfig)
+		}
+		m.message = "Kept local version of " + local.Summary
+	case "s": // Take the server's version
+		keep = conflict.remote
+		delete(m.dirty, id)
+		m.message = "Took server version of " + conflict.remote[0].Summary
+		if msg.String() == "k" {
+			m.message = "Can't keep the local version of a recurring series, took the server's of " + conflict.remote[0].Summary
+		}
+	default:
+		return m, nil
+	}
+
+	var events []Event
+	for _, event := range m.events {
+		if seriesID(event) != id {
+			events = append(events, event)
+		}
+	}
+	m.events = append(events, keep...)
+	m.conflicts = m.conflicts[1:]
+	return m, cmd
+}
+
+// pushEventCmd writes the kept local version of a conflicting event
+func pushEventCmd(calendarURL, id string, event Event, config *RadicaleConfig) tea.Cmd {
+	return func() tea.Msg {
+		queued, err := putEventOrQueue(calendarURL, &event, config)
+		return eventPushedMsg{id: id, queued: queued, err: err}
+	}
+}
+
+func (m model) applyEventPushed(msg eventPushedMsg) model {
+	m.pendingCount = countPendingOps()
+	switch {
+	case msg.err != nil:
+		m.message = fmt.Sprintf("Error: %v", msg.err)
+	case !msg.queued:
+		delete(m.dirty, msg.id)
+	}
+	return m
+}
+
+func (c eventConflict) prompt() string {
+	local := c.local[0]
+	remote := c.remote[0]
+	return fmt.Sprintf("Conflict: %q changed locally (%s) and on the server (%s) — k: keep mine, s: take server",
+		local.Summary,
+		local.Start.Format("Mon 15:04"),
+		remote.Start.Format("Mon 15:04"))
+}
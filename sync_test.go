@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMergeEvents(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	event := func(uid, summary string, sequence int) Event {
+		return Event{UID: uid, Summary: summary, Start: start, End: start.Add(time.Hour), Sequence: sequence}
+	}
+
+	tests := []struct {
+		name      string
+		local     []Event
+		remote    []Event
+		dirty     map[string]eventVersion
+		want      []string // Summaries of the merged events
+		conflicts int
+		stayDirty bool
+	}{
+		{
+			name:   "new on the server",
+			remote: []Event{event("a", "Server", 0)},
+			want:   []string{"Server"},
+		},
+		{
+			name:   "newer on the server",
+			local:  []Event{event("a", "Old", 1)},
+			remote: []Event{event("a", "New", 2)},
+			want:   []string{"New"},
+		},
+		{
+			name:   "newer locally",
+			local:  []Event{event("a", "Local", 3)},
+			remote: []Event{event("a", "Stale", 2)},
+			want:   []string{"Local"},
+		},
+		{
+			name:  "deleted on the server",
+			local: []Event{event("a", "Gone", 0)},
+			want:  nil,
+		},
+		{
+			name:      "unsynced local event",
+			local:     []Event{event("a", "Offline", 0)},
+			dirty:     map[string]eventVersion{"a": {}},
+			want:      []string{"Offline"},
+			stayDirty: true,
+		},
+		{
+			name:   "local edit reached the server",
+			local:  []Event{event("a", "Edited", 2)},
+			remote: []Event{event("a", "Edited", 2)},
+			dirty:  map[string]eventVersion{"a": {Sequence: 1}},
+			want:   []string{"Edited"},
+		},
+		{
+			name:      "edited on both sides",
+			local:     []Event{event("a", "Mine", 2)},
+			remote:    []Event{event("a", "Theirs", 3)},
+			dirty:     map[string]eventVersion{"a": {Sequence: 1}},
+			want:      []string{"Mine"},
+			conflicts: 1,
+			stayDirty: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dirty := test.dirty
+			if dirty == nil {
+				dirty = make(map[string]eventVersion)
+			}
+			merged, conflicts := mergeEvents(test.local, test.remote, dirty)
+
+			var got []string
+			for _, event := range merged {
+				got = append(got, event.Summary)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("merged %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("merged %v, want %v", got, test.want)
+				}
+			}
+			if len(conflicts) != test.conflicts {
+				t.Errorf("%d conflicts, want %d", len(conflicts), test.conflicts)
+			}
+			if _, ok := dirty["a"]; ok != test.stayDirty {
+				t.Errorf("still dirty = %v, want %v", ok, test.stayDirty)
+			}
+		})
+	}
+}
+
+// Keeping the local version of a recurring series takes the server's
+// instead, as the series can't be pushed
+func TestKeepRecurringConflict(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	local := Event{UID: "a", Summary: "Mine", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY", CalendarName: "Work"}
+	remote := local
+	remote.Summary = "Theirs"
+
+	m := initialModel(DailyView, false, nil, fixedClock(start))
+	m.calendarURLs["Work"] = "https://example.com/work/"
+	m.events = []Event{local}
+	m.dirty["a"] = eventVersion{}
+	m.conflicts = []eventConflict{{local: []Event{local}, remote: []Event{remote}}}
+
+	updated, cmd := m.handleConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	m = updated.(model)
+	if cmd != nil {
+		t.Error("pushed a recurring event")
+	}
+	if len(m.events) != 1 || m.events[0].Summary != "Theirs" {
+		t.Errorf("kept %v, want the server's version", m.events)
+	}
+	if _, ok := m.dirty["a"]; ok {
+		t.Error("series still marked unsynced")
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/ical"
+)
+
+// Tasks without a DURATION are planned for this long
+const defaultTaskEstimate = 30 * time.Minute
+
+// taskPlanner lists the open tasks to drop into the current day's free
+// slots (T in the daily view)
+type taskPlanner struct {
+	tasks   []ical.Todo
+	loading bool
+	err     error
+	cursor  int
+	planned map[string]bool // Tasks planned since the list opened, by UID
+}
+
+// loadTasksCmd reads the VTODOs of the writable calendars
+func loadTasksCmd(calendarURLs map[string]string, config *RadicaleConfig) tea.Cmd {
+	return func() tea.Msg {
+		var tasks []ical.Todo
+		for name, calendarURL := range calendarURLs {
+			docs, err := calendarDocuments(calendarURL, config)
+			if err != nil {
+				return tasksLoadedMsg{err: err}
+			}
+			for _, doc := range docs {
+				todos, err := ical.ParseTodos(strings.NewReader(doc), name)
+				if err != nil {
+					continue
+				}
+				tasks = append(tasks, todos...)
+			}
+		}
+		return tasksLoadedMsg{tasks: tasks}
+	}
+}
+
+// calendarDocuments returns the iCalendar documents of a writable calendar:
+// the files of a vdir, the file of a store, or the server's resources
+func calendarDocuments(calendarURL string, config *RadicaleConfig) ([]string, error) {
+	if strings.HasPrefix(calendarURL, storeScheme) {
+		data, err := os.ReadFile(strings.TrimPrefix(calendarURL, storeScheme))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return []string{string(data)}, err
+	}
+	if !strings.HasPrefix(calendarURL, vdirScheme) {
+		return newCalDAVClient(config).FetchCalendar(calendarURL, nil)
+	}
+	files, err := filepath.Glob(filepath.Join(strings.TrimPrefix(calendarURL, vdirScheme), "*.ics"))
+	if err != nil {
+		return nil, err
+	}
+	var docs []string
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			docs = append(docs, string(data))
+		}
+	}
+	return docs, nil
+}
+
+// startPlanner opens the task list and loads the tasks
+func (m model) startPlanner() (model, tea.Cmd) {
+	if m.viewMode != DailyView {
+		return m, nil
+	}
+	m.planner = &taskPlanner{loading: true, planned: make(map[string]bool)}
+	return m, loadTasksCmd(m.calendarURLs, m.radicaleConfig)
+}
+
+func (m model) applyTasks(msg tasksLoadedMsg) model {
+	if m.planner == nil {
+		return m
+	}
+	planner := *m.planner
+	planner.loading = false
+	planner.err = msg.err
+	planner.tasks = msg.tasks
+	// Due first, then by priority
+	sort.SliceStable(planner.tasks, func(i, j int) bool {
+		a, b := planner.tasks[i], planner.tasks[j]
+		if a.Due.IsZero() != b.Due.IsZero() {
+			return !a.Due.IsZero()
+		}
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		return taskRank(a) < taskRank(b)
+	})
+	m.planner = &planner
+	return m
+}
+
+// taskRank orders priorities, with undefined (0) last
+func taskRank(task ical.Todo) int {
+	if task.Priority == 0 {
+		return 10
+	}
+	return task.Priority
+}
+
+// unplannedTasks are the tasks no event is planned for yet
+func (m model) unplannedTasks() []ical.Todo {
+	planned := make(map[string]bool)
+	for _, event := range m.events {
+		if event.RelatedTo != "" {
+			planned[event.RelatedTo] = true
+		}
+	}
+	var tasks []ical.Todo
+	for _, task := range m.planner.tasks {
+		if !planned[task.UID] && !m.planner.planned[task.UID] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// handlePlannerKey moves through the tasks, enter plans the focused one
+// and esc or q closes the list
+func (m model) handlePlannerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	planner := *m.planner
+	tasks := m.unplannedTasks()
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc", "q", "T":
+		m.planner = nil
+		return m, nil
+	case "down", "j":
+		planner.cursor++
+	case "up", "k":
+		planner.cursor--
+	case "enter":
+		if planner.cursor < len(tasks) {
+			return m.planTask(tasks[planner.cursor])
+		}
+	}
+	planner.cursor = max(0, min(planner.cursor, len(tasks)-1))
+	m.planner = &planner
+	return m, nil
+}
+
+// planTask creates an event for the task in the first free slot of the
+// current day that fits its estimate, linked to the task with RELATED-TO
+func (m model) planTask(task ical.Todo) (model, tea.Cmd) {
+	estimate := task.Estimate
+	if estimate <= 0 {
+		estimate = defaultTaskEstimate
+	}
+	var slot *timeSlot
+	for _, free := range m.freeWorkingSlots(m.events, m.currentDate, nextQuarterHour(m.clock.Now())) {
+		if free.end.Sub(free.start) >= estimate {
+			slot = &timeSlot{start: free.start, end: free.start.Add(estimate)}
+			break
+		}
+	}
+	if slot == nil {
+		m.message = fmt.Sprintf(tr("No free slot of %s left on %s"), formatEstimate(estimate), formatDate(m.currentDate, "Mon Jan 2"))
+		return m, nil
+	}
+
+	event := &Event{
+		Summary:      task.Summary,
+		Description:  task.Description,
+		Start:        slot.start,
+		End:          slot.end,
+		CalendarName: task.CalendarName,
+		RelatedTo:    task.UID,
+	}
+	m.planner.planned[task.UID] = true
+	m, cmd := m.saveNewEvents([]*Event{event})
+	if m.creating == nil {
+		m.message = fmt.Sprintf(tr("Planned %s %s–%s"), task.Summary, slot.start.Format("15:04"), slot.end.Format("15:04"))
+	}
+	return m, cmd
+}
+
+// formatEstimate shows a task's estimate like "30m" or "1h30m"
+func formatEstimate(d time.Duration) string {
+	s := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// viewPlanner lists the open tasks next to the day's free time
+func (m model) viewPlanner() string {
+	var b strings.Builder
+	b.WriteString(m.renderTitle(tr("📋 Plan the day"), formatDate(m.currentDate, "Mon Jan 2, 2006")))
+
+	var free []string
+	for _, slot := range m.freeWorkingSlots(m.events, m.currentDate, nextQuarterHour(m.clock.Now())) {
+		free = append(free, slot.start.Format("15:04")+"–"+slot.end.Format("15:04"))
+	}
+	if len(free) == 0 {
+		free = append(free, tr("none"))
+	}
+	b.WriteString(noEventsStyle.Render(m.fitLine(tr("Free:")+" "+strings.Join(free, ", "), 2)) + "\n\n")
+
+	tasks := m.unplannedTasks()
+	switch {
+	case m.planner.loading:
+		b.WriteString(noEventsStyle.Render(tr("Loading tasks...")) + "\n")
+	case m.planner.err != nil:
+		b.WriteString(noEventsStyle.Render(fmt.Sprintf(tr("Failed to load tasks: %v"), m.planner.err)) + "\n")
+	case len(tasks) == 0:
+		b.WriteString(noEventsStyle.Render(tr("No open tasks to plan")) + "\n")
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	for i, task := range tasks {
+		marker := "  "
+		if i == m.planner.cursor {
+			marker = "▸ "
+		}
+		estimate := task.Estimate
+		if estimate <= 0 {
+			estimate = defaultTaskEstimate
+		}
+		details := " · " + formatEstimate(estimate)
+		if !task.Due.IsZero() {
+			details += " · " + fmt.Sprintf(tr("due %s"), formatDate(task.Due, "Mon Jan 2"))
+		}
+		details += " · " + task.CalendarName
+		// Truncated before styling, which truncate doesn't skip
+		title := marker + task.Summary
+		line, rest := m.fitLine(title+details, 0), ""
+		if strings.HasPrefix(line, title) {
+			line, rest = title, line[len(title):]
+		}
+		b.WriteString(lipgloss.NewStyle().Foreground(m.calendars[task.CalendarName]).Render(line) + dimStyle.Render(rest) + "\n")
+	}
+
+	if m.message != "" {
+		b.WriteString("\n" + helpStyle.Render(m.message))
+	}
+	b.WriteString("\n" + renderHelp([]string{"j/k: move", "enter: plan in next free slot", "esc: close"}))
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// ellipsis marks text cut off by truncate
+const ellipsis = "…"
+
+// maxDescriptionLines is how many lines of an event description are shown
+const maxDescriptionLines = 3
+
+// truncate shortens s to at most width terminal cells, ending in an
+// ellipsis when anything was cut. It counts grapheme clusters, so emoji and
+// wide CJK characters are never split or miscounted.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if uniseg.StringWidth(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	used := 0
+	state := -1
+	rest := s
+	for len(rest) > 0 {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteString(cluster)
+		used += w
+	}
+	return b.String() + ellipsis
+}
+
+// wrapText word-wraps s to lines of at most width cells, breaking words
+// that are longer than a line. At most maxLines lines are returned (0 for
+// no limit); the last one is truncated if text was left over.
+func wrapText(s string, width, maxLines int) []string {
+	if width <= 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line, lineWidth := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			wordWidth := uniseg.StringWidth(word)
+			if lineWidth > 0 && lineWidth+1+wordWidth <= width {
+				line += " " + word
+				lineWidth += 1 + wordWidth
+				continue
+			}
+			if lineWidth > 0 {
+				lines = append(lines, line)
+			}
+			// Split words wider than a line at grapheme boundaries
+			for wordWidth > width {
+				head, headWidth := splitWidth(word, width)
+				lines = append(lines, head)
+				word = word[len(head):]
+				wordWidth -= headWidth
+			}
+			line, lineWidth = word, wordWidth
+		}
+		lines = append(lines, line)
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		last := lines[maxLines-1]
+		if uniseg.StringWidth(last) < width {
+			last += ellipsis
+		} else {
+			last = truncate(last, width-1)
+		}
+		lines = append(lines[:maxLines-1], last)
+	}
+	return lines
+}
+
+// splitWidth returns the longest prefix of s that fits in width cells and
+// its width. At least one grapheme cluster is returned so callers make
+// progress even when width is smaller than a wide character.
+func splitWidth(s string, width int) (string, int) {
+	used, end := 0, 0
+	state := -1
+	rest := s
+	for len(rest) > 0 {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if used+w > width && end > 0 {
+			break
+		}
+		used += w
+		end += len(cluster)
+	}
+	return s[:end], used
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// timelineWidth is the width of the daily view's timeline in the split
+// layout
+const timelineWidth = 32
+
+// split reports whether the daily view shows the timeline beside the event
+// boxes: configured with layout "split", on a terminal wide enough for both
+func (m model) split() bool {
+	return m.dayViewConfig().splitLayout() && m.sized() && !m.short() && m.width >= timelineSplit
+}
+
+// renderTimeline draws the day hour by hour, the working hours stretched to
+// the events: a colored block where an event is, with its title on the row
+// it starts, and nothing where the day is free
+func (m model) renderTimeline(dayEvents []Event) string {
+	day := dayStart(m.currentDate)
+	var events []Event
+	for _, event := range dayEvents {
+		if !isAllDay(event) && busy(event) {
+			events = append(events, event)
+		}
+	}
+
+	var workingHours *WorkingHoursConfig
+	if m.config != nil {
+		workingHours = m.config.WorkingHours
+	}
+	from, to := day.Add(9*time.Hour), day.Add(17*time.Hour)
+	if start, end, ok := workingHours.workingHours(day); ok {
+		from, to = start, end
+	}
+	for _, event := range events {
+		if event.Start.Before(from) {
+			from = event.Start
+		}
+		if event.End.After(to) {
+			to = event.End
+		}
+	}
+	from = from.Truncate(time.Hour)
+	if from.Before(day) {
+		from = day
+	}
+	if end := day.AddDate(0, 0, 1); to.After(end) {
+		to = end
+	}
+
+	// Half hours while they fit beside the boxes, hours otherwise
+	slot := 30 * time.Minute
+	if int(to.Sub(from)/slot) > m.height-16 {
+		slot = time.Hour
+	}
+
+	var focused *Event
+	if m.cursor >= 0 && m.cursor < len(dayEvents) {
+		focused = &dayEvents[m.cursor]
+	}
+	now := m.clock.Now()
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	nowStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+
+	var b strings.Builder
+	for t := from; t.Before(to); t = t.Add(slot) {
+		label := t.Format("15:04")
+		if t.Minute() != 0 {
+			label = "  " + t.Format(":04")
+		}
+		if !now.Before(t) && now.Before(t.Add(slot)) {
+			label = nowStyle.Render(label + "▸")
+		} else {
+			label = dimStyle.Render(label + " ")
+		}
+
+		// The event in the slot that started last, so short meetings show
+		// within longer blocks
+		var active []Event
+		for _, event := range events {
+			if event.Start.Before(t.Add(slot)) && event.End.After(t) {
+				active = append(active, event)
+			}
+		}
+		if len(active) == 0 {
+			b.WriteString(label + dimStyle.Render("│") + "\n")
+			continue
+		}
+		event := active[0]
+		for _, other := range active[1:] {
+			if other.Start.After(event.Start) {
+				event = other
+			}
+		}
+
+		blockStyle := lipgloss.NewStyle().Foreground(eventColor(event))
+		line := label + blockStyle.Render("██")
+		if !event.Start.Before(t) || t.Equal(from) {
+			title := m.eventTitle(event)
+			if len(active) > 1 {
+				title += fmt.Sprintf(" +%d", len(active)-1)
+			}
+			titleStyle := blockStyle
+			if focused != nil && eventKey(*focused) == eventKey(event) {
+				titleStyle = titleStyle.Bold(true).Underline(true)
+			}
+			line += " " + titleStyle.Render(truncate(title, timelineWidth-9))
+		}
+		b.WriteString(line + "\n")
+	}
+	return lipgloss.NewStyle().Width(timelineWidth).Render(strings.TrimSuffix(b.String(), "\n"))
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	defaultPomodoroMinutes = 25
+	timerLogFile           = "timer_log.jsonl"
+)
+
+type timerTickMsg struct{}
+
+// countdown is a running timer (p), until the end of the current event or
+// for a pomodoro
+type countdown struct {
+	label string
+	start time.Time
+	end   time.Time
+	bar   progress.Model
+}
+
+// timerSession is one line of the timer log
+type timerSession struct {
+	Label     string    `json:"label"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Completed bool      `json:"completed"` // False if stopped early
+}
+
+func scheduleTimerTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return timerTickMsg{}
+	})
+}
+
+func (m model) timerConfig() TimerConfig {
+	if m.config != nil && m.config.Timer != nil {
+		return *m.config.Timer
+	}
+	return TimerConfig{}
+}
+
+// startTimer counts down to the end of the focused event if it is running,
+// else of any running event, else for a pomodoro
+func (m model) startTimer() (model, tea.Cmd) {
+	now := m.clock.Now()
+	var current *Event
+	if m.viewMode == DailyView {
+		if dayEvents := m.dailyEvents(); m.cursor < len(dayEvents) {
+			if event := dayEvents[m.cursor]; event.Start.Before(now) && event.End.After(now) {
+				current = &event
+			}
+		}
+	}
+	if current == nil {
+		for _, event := range m.getEventsForDay(now) {
+			if busy(event) && event.Start.Before(now) && event.End.After(now) {
+				current = &event
+				break
+			}
+		}
+	}
+
+	timer := &countdown{
+		start: now,
+		bar:   progress.New(progress.WithScaledGradient("#FF7CCB", "#FDFF8C"), progress.WithoutPercentage()),
+	}
+	if current != nil {
+		timer.label = m.eventTitle(*current)
+		timer.end = current.End
+	} else {
+		minutes := m.timerConfig().PomodoroMinutes
+		if minutes <= 0 {
+			minutes = defaultPomodoroMinutes
+		}
+		timer.label = tr("Pomodoro")
+		timer.end = now.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	m.timer = timer
+	return m, scheduleTimerTick()
+}
+
+// updateTimer advances the timer each second and finishes it at the end
+func (m model) updateTimer() (model, tea.Cmd) {
+	if m.timer == nil {
+		return m, nil
+	}
+	if m.clock.Now().Before(m.timer.end) {
+		return m, scheduleTimerTick()
+	}
+	return m.stopTimer(true), nil
+}
+
+// stopTimer closes the timer, logging the session and notifying if it ran
+// to the end
+func (m model) stopTimer(completed bool) model {
+	timer := m.timer
+	m.timer = nil
+	config := m.timerConfig()
+
+	end := m.clock.Now()
+	if completed {
+		end = timer.end
+		m.message = fmt.Sprintf(tr("%s finished"), timer.label)
+		if config.Notify {
+			notify(m.message, "")
+		}
+	} else {
+		m.message = fmt.Sprintf(tr("%s stopped"), timer.label)
+	}
+
+	if config.Log {
+		session := timerSession{Label: timer.label, Start: timer.start, End: end, Completed: completed}
+		if err := logTimerSession(session); err != nil {
+			m.message = fmt.Sprintf("Error: %v", err)
+		}
+	}
+	return m
+}
+
+// handleTimerKey lets esc, p or q stop a running timer
+func (m model) handleTimerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "p", "q":
+		return m.stopTimer(false), nil
+	}
+	return m, nil
+}
+
+// viewTimer renders the countdown as a large progress bar
+func (m model) viewTimer() string {
+	timer := m.timer
+	now := m.clock.Now()
+	total := timer.end.Sub(timer.start)
+	remaining := timer.end.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	percent := 1.0
+	if total > 0 {
+		percent = float64(now.Sub(timer.start)) / float64(total)
+	}
+
+	width := 60
+	if m.width > 0 {
+		width = max(20, m.width-10)
+	}
+	timer.bar.Width = width
+	bar := timer.bar.ViewAs(min(percent, 1))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("⏱ "+timer.label) + "\n\n")
+	remainingStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229")).Padding(0, 1)
+	b.WriteString(remainingStyle.Render(formatRemaining(remaining)) + "\n\n")
+	for i := 0; i < 3; i++ {
+		b.WriteString(" " + bar + "\n")
+	}
+	until := fmt.Sprintf(tr("until %s"), timer.end.Format("15:04"))
+	b.WriteString("\n" + noEventsStyle.Render(until) + "\n")
+	b.WriteString(renderHelp([]string{"esc/p: stop"}))
+	return b.String()
+}
+
+// formatRemaining formats a countdown as "mm:ss", or "h:mm:ss" from an hour
+func formatRemaining(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// logTimerSession appends a session to the timer log
+func logTimerSession(session timerSession) error {
+	logPath, err := getStatePath(timerLogFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const titleRefreshInterval = 30 * time.Second
+
+type titleTickMsg struct{}
+
+// titleEnabled reports whether the terminal title shows the next event
+func (m model) titleEnabled() bool {
+	return !m.oneShot && (m.config == nil || m.config.TerminalTitle == nil || *m.config.TerminalTitle)
+}
+
+func scheduleTitleUpdate() tea.Cmd {
+	return tea.Tick(titleRefreshInterval, func(time.Time) tea.Msg {
+		return titleTickMsg{}
+	})
+}
+
+// terminalTitle describes the next event, e.g. "Standup in 12m"
+func (m model) terminalTitle() string {
+	events := filterEventsByCalendar(m.events, nil, excludedFromNext(m.config))
+	now := m.clock.Now()
+	next := getNextEvent(events, now)
+	if next == nil {
+		return "zebracal"
+	}
+
+	until := next.Start.Sub(now)
+	var when string
+	switch {
+	case until < time.Hour:
+		when = fmt.Sprintf("%dm", int(until.Minutes())+1)
+	case until < 24*time.Hour:
+		when = fmt.Sprintf("%dh%02dm", int(until.Hours()), int(until.Minutes())%60)
+	default:
+		when = fmt.Sprintf("%dd", int(until.Hours()/24))
+	}
+	return fmt.Sprintf(tr("%s in %s"), m.eventTitle(*next), when)
+}
+
+// updateTitle sets the terminal title to the next event. Inside tmux this
+// also sets the pane title.
+func (m model) updateTitle() tea.Cmd {
+	if !m.titleEnabled() {
+		return nil
+	}
+	return tea.SetWindowTitle(m.terminalTitle())
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
-	"encoding/xml"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+
+	"mytuiapp/internal/ical"
 )
 
 type ViewMode int
@@ -15,6 +17,7 @@ const (
 	DailyView ViewMode = iota
 	WeeklyView
 	MonthlyView
+	RollingView // A few weeks from the current one, across month boundaries
 )
 
 type EventCreationMode int
@@ -25,72 +28,360 @@ const (
 	UIFormInput
 )
 
+// Background work never changes the model itself: commands do the I/O and
+// report back with a message, which Update applies. Loads and writes that
+// report progress pass the channel of their further updates along.
+
 type loadingMsg struct {
 	progress float64
 	message  string
+	updates  <-chan tea.Msg // Further loading updates, if any
 }
 
 type loadingCompleteMsg struct{}
 
-type Event struct {
-	Summary       string
-	Start         time.Time
-	End           time.Time
-	Description   string
-	CalendarName  string
-	CalendarColor lipgloss.Color
-	UID           string // For Radicale sync
+type refreshTickMsg struct{}
+
+// eventsUpdatedMsg brings the calendars loaded at startup or refreshed
+type eventsUpdatedMsg struct {
+	events       []Event
+	calendars    map[string]lipgloss.Color
+	calendarURLs map[string]string
+	warnings     []string // Calendars that failed to load
+	initial      bool     // The startup load rather than a background refresh
+}
+
+// syncErrorMsg reports that no calendar could be loaded
+type syncErrorMsg struct {
+	err     error
+	initial bool
+}
+
+// eventPushedMsg reports the local version of a conflicting event written
+// back to the server
+type eventPushedMsg struct {
+	id     string // seriesID
+	queued bool
+	err    error
+}
+
+type queueFlushedMsg struct {
+	flushed   int
+	failed    int
+	remaining int
+	err       error
+}
+
+type BulkActionKind int
+
+const (
+	BulkDelete BulkActionKind = iota
+	BulkMove
+	BulkShiftForward
+	BulkShiftBack
+	BulkExport
+)
+
+type bulkDoneMsg struct {
+	kind    BulkActionKind
+	results []bulkResult
+	skipped int
+	detail  string // Extra info for the status message (e.g. export path)
+	err     error
+}
+
+type freeBusyLoadedMsg struct {
+	busy   map[string][]ical.Period // Busy times by colleague
+	failed []string                 // Lookups that failed, "name: error"
+}
+
+type notesLoadedMsg struct {
+	notes []ical.Journal
+	err   error
+}
+
+type tasksLoadedMsg struct {
+	tasks []ical.Todo
+	err   error
+}
+
+type noteSavedMsg struct {
+	note    ical.Journal
+	deleted bool
+	err     error
+}
+
+type createProgressMsg struct {
+	done    int
+	updates <-chan tea.Msg
+}
+
+type createDoneMsg struct {
+	created   []Event // Written to the server
+	queued    []Event // Queued while offline
+	failed    int
+	err       error
+	cancelled bool
+}
+
+// Event is a single calendar event (or one occurrence of a recurring event)
+type Event = ical.Event
+
+// eventKey identifies a single occurrence of an event
+func eventKey(event Event) string {
+	id := event.UID
+	if id == "" {
+		id = event.CalendarName + "/" + event.Summary
+	}
+	return id + "|" + event.Start.UTC().Format(time.RFC3339)
 }
 
 type CalendarConfig struct {
 	Name string `json:"name"`
 	URL  string `json:"url,omitempty"`
 	File string `json:"file,omitempty"`
-	Type string `json:"type,omitempty"` // "radicale", "url", "file", or empty for auto-detect
+	Type string `json:"type,omitempty"` // "radicale", "url", "file", "google", "outlook", "ews", "vdir", "store", or empty for auto-detect
+	Path string `json:"path,omitempty"` // Directory of a "vdir" calendar, one .ics file per event, or the .ics file of a "store"
+
+	// Google calendar ID, default "primary" (see zebracal google-login), or
+	// Outlook calendar ID, default the mailbox's calendar (zebracal outlook-login)
+	CalendarID string `json:"calendar_id,omitempty"`
+
+	IncludeInNext *bool `json:"include_in_next,omitempty"` // Set to false to hide from --next (default true)
+
+	// Limits for huge feeds. Radicale calendars can be limited with an
+	// entry of type "radicale" and the calendar's display name.
+	MaxEvents  int `json:"max_events,omitempty"`  // Keep at most this many events, those closest to today
+	WindowDays int `json:"window_days,omitempty"` // Only keep events within this many days of today
+
+	// Only keep events passing all of these, e.g. `summary !~ "Canceled"`,
+	// `duration >= 15m` or `after 08:00` (see parseFilter)
+	Filter []string `json:"filter,omitempty"`
+
+	// Shared calendars can be filtered to events where one of the
+	// configured emails is organizer or attendee (u)
+	Shared bool `json:"shared,omitempty"`
+
+	Group string `json:"group,omitempty"` // e.g. "Work", groups the legend and is shown or hidden as one (G)
+
+	// Sent with each request for the calendar, e.g. {"Cookie": "session=…"}
+	// for a feed behind a single sign-on. Applies to ICS feeds and Radicale
+	// calendars.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Cost of an hour of a meeting per attendee, to show meetings'
+	// estimated cost ("~CHF 450") and the week's total
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+}
+
+// GoogleConfig is an OAuth client of type "TVs and Limited Input devices"
+// from the Google Cloud console, used by calendars of type "google"
+type GoogleConfig struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// OutlookConfig is an app registration in Microsoft Entra ID that allows
+// public client flows and the Calendars.Read permission, used by
+// calendars of type "outlook"
+type OutlookConfig struct {
+	ClientID string `json:"client_id"`
+	Tenant   string `json:"tenant,omitempty"` // Directory ID or domain, default "organizations"
+}
+
+// EWSConfig is an on-premises Exchange server, used by calendars of type
+// "ews" which read the user's own calendar
+type EWSConfig struct {
+	URL      string `json:"url"`      // e.g. https://mail.example.com/EWS/Exchange.asmx
+	Username string `json:"username"` // "user", "DOMAIN\user" or "user@domain"
+	Password string `json:"password"`
+	Auth     string `json:"auth,omitempty"` // "basic" (default) or "ntlm"
+}
+
+type TimerConfig struct {
+	PomodoroMinutes int  `json:"pomodoro_minutes,omitempty"` // Timer length when no event is running, default 25
+	Notify          bool `json:"notify,omitempty"`           // Desktop notification when the time is up
+	Log             bool `json:"log,omitempty"`              // Append sessions to timer_log.jsonl
+}
+
+// DescriptionConfig cleans the boilerplate of meeting invites out of event
+// descriptions
+type DescriptionConfig struct {
+	Presets  []string `json:"presets,omitempty"`  // "teams", "zoom" and "meet" by default, ["none"] for none
+	Patterns []string `json:"patterns,omitempty"` // Regexps whose matches are removed too, e.g. "(?s)Sent from my .*"
+}
+
+// LowMemoryConfig keeps memory use bounded on small devices: events are
+// only held around the viewed date, ICS feeds are parsed one event at a time
+// and read-only calendars don't keep their source
+type LowMemoryConfig struct {
+	WindowDays int `json:"window_days,omitempty"` // Days held either side of the viewed date, default 90
+}
+
+type ColleagueConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"` // Calendar URL on the Radicale server
+}
+
+// ContactsConfig is where attendees of new events are completed from
+type ContactsConfig struct {
+	File string `json:"file,omitempty"` // vCard (.vcf) file, or one "Name <address>" per line
+	URL  string `json:"url,omitempty"`  // CardDAV address book on the Radicale server
 }
 
 type RadicaleConfig struct {
 	ServerURL string `json:"server_url"`
 	Username  string `json:"username"`
 	Password  string `json:"password"`
+
+	// How long the discovered calendars are reused before asking the
+	// server again, default 1440 (a day). Refreshing with r always asks.
+	DiscoveryTTLMinutes int `json:"discovery_ttl_minutes,omitempty"`
 }
 
-type Config struct {
-	Radicale       *RadicaleConfig  `json:"radicale,omitempty"`
-	Calendars      []CalendarConfig `json:"calendars"`
-	LocalCalendars []string         `json:"local_calendars,omitempty"`
+type ReminderConfig struct {
+	LeadMinutes int   `json:"lead_minutes,omitempty"` // Minutes before start to notify (default 10)
+	InApp       *bool `json:"in_app,omitempty"`       // Flash upcoming events in the running TUI, default true
+	Bell        bool  `json:"bell,omitempty"`         // Ring the terminal bell with in-app alerts
+
+	Email *ReminderEmailConfig `json:"email,omitempty"` // Also mail the daemon's reminders
+}
+
+// ReminderEmailConfig sends the reminder daemon's reminders by mail, for
+// headless machines. Subject and body are text/template templates of a
+// reminderMail.
+type ReminderEmailConfig struct {
+	Host      string   `json:"host"`
+	Port      int      `json:"port,omitempty"` // Default 587 (STARTTLS); 465 connects with TLS
+	Username  string   `json:"username,omitempty"`
+	Password  string   `json:"password,omitempty"`
+	From      string   `json:"from"`
+	To        []string `json:"to,omitempty"`        // Defaults to the first of emails
+	Subject   string   `json:"subject,omitempty"`   // Default "Reminder: {{.Summary}} at {{.Start.Format "15:04"}}"
+	Body      string   `json:"body,omitempty"`      // Default the time, calendar, location and description
+	Calendars []string `json:"calendars,omitempty"` // Only mail reminders of these calendars, all if empty
+}
+
+// HTTPConfig tunes the HTTP client used for all calendar requests
+type HTTPConfig struct {
+	TimeoutSeconds     int      `json:"timeout_seconds,omitempty"`      // Request timeout (default 10)
+	Proxy              string   `json:"proxy,omitempty"`                // Proxy URL (http, https or socks5), defaults to HTTP(S)_PROXY or ALL_PROXY from the environment
+	CACerts            []string `json:"ca_certs,omitempty"`             // Extra PEM files to trust, e.g. a self-signed Radicale cert
+	InsecureSkipVerify bool     `json:"insecure_skip_verify,omitempty"` // Don't verify TLS certificates
+	MaxAttempts        int      `json:"max_attempts,omitempty"`         // Tries per request on server errors and timeouts (default 3)
+	UserAgent          string   `json:"user_agent,omitempty"`           // User-Agent header of all requests, for feeds that reject Go's default
+
+	// Proxies for single servers by host name, which includes subdomains,
+	// e.g. {"cal.corp.example": "socks5://localhost:1080"} for a server
+	// only reachable through an SSH tunnel. They take precedence over proxy.
+	Proxies map[string]string `json:"proxies,omitempty"`
+}
+
+// WorkingHoursConfig bounds the search for free time
+type WorkingHoursConfig struct {
+	Start    string `json:"start,omitempty"`    // "HH:MM" (default 09:00)
+	End      string `json:"end,omitempty"`      // "HH:MM" (default 17:00)
+	Weekends bool   `json:"weekends,omitempty"` // Count Saturday and Sunday as working days
+}
+
+// FocusConfig sets up the focus blocks created with 'b'
+type FocusConfig struct {
+	Title           string `json:"title,omitempty"`            // Default "Focus time"
+	Calendar        string `json:"calendar,omitempty"`         // Default is the selected calendar
+	DurationMinutes int    `json:"duration_minutes,omitempty"` // Default 90
+	Transparent     bool   `json:"transparent,omitempty"`      // Show as free (TRANSP:TRANSPARENT) instead of busy
 }
 
-type CalDAVCalendar struct {
-	DisplayName string
-	URL         string
+type DayViewConfig struct {
+	Sort            string `json:"sort,omitempty"`              // "start" (default), "duration" or "calendar"
+	GroupByCalendar bool   `json:"group_by_calendar,omitempty"` // Section headers per calendar
+	Layout          string `json:"layout,omitempty"`            // "list" (default) or "split": an hour-by-hour timeline beside the events on wide terminals
+
+	ShowDescription  *bool `json:"show_description,omitempty"`   // Default true
+	ShowLocation     *bool `json:"show_location,omitempty"`      // Default true
+	ShowCalendarName bool  `json:"show_calendar_name,omitempty"` // Name the calendar next to the time
 }
 
-// CalDAV XML structures
-type propfindRequest struct {
-	XMLName xml.Name `xml:"DAV: propfind"`
-	Prop    prop     `xml:"DAV: prop"`
+type WeekViewConfig struct {
+	ShowEndTime      *bool `json:"show_end_time,omitempty"` // Default true
+	ShowLocation     bool  `json:"show_location,omitempty"`
+	ShowCalendarName bool  `json:"show_calendar_name,omitempty"`
 }
 
-type prop struct {
-	DisplayName         string `xml:"DAV: displayname"`
-	CalendarDescription string `xml:"urn:ietf:params:xml:ns:caldav calendar-description"`
-	CalendarColor       string `xml:"http://apple.com/ns/ical/ calendar-color"`
+type MonthViewConfig struct {
+	Heatmap *bool `json:"heatmap,omitempty"` // Shade day numbers by booked hours against working hours, default true
 }
 
-type multistatus struct {
-	XMLName  xml.Name   `xml:"DAV: multistatus"`
-	Response []response `xml:"DAV: response"`
+// PublishConfig sets up the static site written with --publish
+type PublishConfig struct {
+	Title     string   `json:"title,omitempty"`     // Page heading, default "Calendar"
+	Calendars []string `json:"calendars,omitempty"` // Calendars to publish, default all
+	Busy      []string `json:"busy,omitempty"`      // Calendars published as "Busy", without titles or locations
+	Months    int      `json:"months,omitempty"`    // Months from the current one, default 3
 }
 
-type response struct {
-	Href     string     `xml:"DAV: href"`
-	Propstat []propstat `xml:"DAV: propstat"`
+// LocalStoreConfig is a local calendar that new events are saved to when
+// the calendar picked for them can't be written, e.g. a feed or a server
+// calendar without a URL. It is created on the first write.
+type LocalStoreConfig struct {
+	Name string `json:"name,omitempty"` // Calendar name, default "Local"
+	Path string `json:"path"`           // A vdir directory, or a single .ics file if it ends in .ics
 }
 
-type propstat struct {
-	Status string `xml:"DAV: status"`
-	Prop   prop   `xml:"DAV: prop"`
+type Config struct {
+	Radicale       *RadicaleConfig     `json:"radicale,omitempty"`
+	Calendars      []CalendarConfig    `json:"calendars"`
+	LocalCalendars []string            `json:"local_calendars,omitempty"`
+	Reminders      *ReminderConfig     `json:"reminders,omitempty"`
+	RefreshMinutes int                 `json:"refresh_minutes,omitempty"` // Background refresh interval (default 15)
+	Locale         string              `json:"locale,omitempty"`          // e.g. "de_CH", defaults to English
+	DayView        *DayViewConfig      `json:"day_view,omitempty"`
+	WeekView       *WeekViewConfig     `json:"week_view,omitempty"`
+	MonthView      *MonthViewConfig    `json:"month_view,omitempty"`
+	Density        string              `json:"density,omitempty"` // "compact", "normal" (default) or "spacious"
+	HTTP           *HTTPConfig         `json:"http,omitempty"`
+	NotesCalendar  string              `json:"notes_calendar,omitempty"` // Radicale calendar for daily notes, defaults to the first one
+	WorkingHours   *WorkingHoursConfig `json:"working_hours,omitempty"`
+	Focus          *FocusConfig        `json:"focus,omitempty"`
+	Publish        *PublishConfig      `json:"publish,omitempty"`
+	LocalStore     *LocalStoreConfig   `json:"local_store,omitempty"`
+
+	FloatingTimezone string `json:"floating_timezone,omitempty"` // IANA zone for event times without one, defaults to the system's
+	TerminalTitle    *bool  `json:"terminal_title,omitempty"`    // Show the next event in the terminal (and tmux pane) title, default true
+	Palette          string `json:"palette,omitempty"`           // Calendar colors: "default", "colorblind" or "basic", chosen from the terminal if empty
+	RollingWeeks     int    `json:"rolling_weeks,omitempty"`     // Weeks in the rolling view, default 2
+	CalendarOrder    string `json:"calendar_order,omitempty"`    // "config" (default) for the order of calendars, or "alphabetical"
+	TimeStep         int    `json:"time_step,omitempty"`         // Minutes up/down move the form's times, 5, 15 (default) or 30
+
+	// Natural language phrases for a part of the day, like "all afternoon":
+	// "13:00-17:00" or "all day": "all day". They add to and override the
+	// built-in ones; empty hours remove one. A single word like "lunch"
+	// stays in the summary.
+	DayParts map[string]string `json:"day_parts,omitempty"`
+
+	Currency string `json:"currency,omitempty"` // Shown with meeting costs, e.g. "CHF" (see hourly_rate)
+
+	Emails []string `json:"emails,omitempty"` // My addresses, to find my events on shared calendars; the first organizes new events with attendees
+
+	Contacts *ContactsConfig `json:"contacts,omitempty"`
+
+	// Show an event found in several calendars (e.g. a forwarded invite)
+	// once, listing all its calendars
+	MergeDuplicates bool `json:"merge_duplicates,omitempty"`
+
+	// Calendars whose busy times are queried (free-busy-query) so free slot
+	// searches only offer times that suit everyone
+	Colleagues []ColleagueConfig `json:"colleagues,omitempty"`
+
+	Timer *TimerConfig `json:"timer,omitempty"`
+
+	LowMemory *LowMemoryConfig `json:"low_memory,omitempty"`
+
+	Descriptions *DescriptionConfig `json:"descriptions,omitempty"`
+
+	Google  *GoogleConfig  `json:"google,omitempty"`
+	Outlook *OutlookConfig `json:"outlook,omitempty"`
+	EWS     *EWSConfig     `json:"ews,omitempty"`
 }
 
 type UIFormState struct {
@@ -109,6 +400,7 @@ type model struct {
 	calendars        map[string]lipgloss.Color
 	calendarURLs     map[string]string // Map calendar name to Radicale URL
 	currentDate      time.Time
+	clock            Clock // What time it is now
 	viewMode         ViewMode
 	dayInput         string
 	width            int
@@ -131,11 +423,69 @@ type model struct {
 	// Form data (pointers for huh form)
 	formSummary       *string
 	formDescription   *string
+	formAttendees     *string // Comma-separated "Name <address>" entries
+	formURL           *string
 	formDate          *string
 	formStartTime     *string
 	formEndTime       *string
+	formEndDate       *string // Empty for events ending on their start date
 	formCalendar      *string
 	formRepeatOptions *string // Single select for repeat option
 	formRepeatEndDate *string
-	formScrollOffset  int // For scrolling when content is too tall
+	formRepeatEvery   *string    // Interval, "2" for every other day/week/...
+	formRepeatDays    *[]string  // BYDAY codes of weekly repeats, "MO", "WE", ...
+	formRepeatMonthly *string    // Monthly repeats on the date's "day", "nth" weekday or "last" weekday
+	formRepeatCount   *string    // End after this many occurrences instead of a date
+	formScrollOffset  int        // For scrolling when content is too tall
+	formError         string     // Why the last save failed, shown below the form
+	draft             *formDraft // Input of a form left without saving
+	draftPrompt       bool       // Asking whether to restore draft into the opened form
+	contacts          []contact  // Completions for the form's attendees
+
+	// Selection and bulk operations (daily view)
+	cursor      int             // Index of the focused event in the day's list
+	selected    map[string]bool // Selected events by eventKey
+	pendingBulk *bulkAction     // Bulk action awaiting confirmation
+	confirmQuit int             // Unsynced changes the quit prompt warns about, 0 when not asking
+
+	// Sync state
+	config    *Config
+	dirty     map[string]eventVersion // Unsynced local edits by seriesID, with the version they were based on
+	conflicts []eventConflict         // Conflicts awaiting resolution
+
+	pendingCount int          // Writes queued in the offline journal
+	creating     *createBatch // Event writes in progress
+
+	redact   bool   // Hide titles and descriptions for screen sharing
+	mineOnly bool   // Hide others' events on shared calendars
+	openUID  string // Event to focus once calendars are loaded (--open-uid)
+
+	// Daily notes (VJOURNALs) by day, see noteKey
+	notes    map[string]ical.Journal
+	noteForm *huh.Form // Note editor, nil when closed
+	noteText *string
+
+	focusForm  *huh.Form // Focus range prompt, nil when closed
+	focusRange *string
+
+	colleagueBusy   map[string][]ical.Period // Colleagues' busy times by name
+	habits          *habitLog                // Days habits were done, nil if unavailable
+	timer           *countdown               // Running timer (p), nil if none
+	detail          *eventDetail             // Detail panel of an event (enter), nil when closed
+	planner         *taskPlanner             // Open tasks to plan into the day (T), nil when closed
+	zen             bool                     // Full-screen current event and countdown (f)
+	invite          *invitePreview           // Events read with --stdin, nil once accepted or dismissed
+	hiddenGroups    map[string]bool          // Calendar groups hidden with G
+	hiddenCalendars map[string]bool          // Calendars hidden with 1-9, by name
+	groupPrompt     bool                     // Asking which calendar group to show or hide (G)
+	expandedDay     time.Time                // Day of the weekly view showing descriptions (enter), zero if none
+	alerted         map[string]bool          // Occurrences whose start was announced, by eventKey
+	alert           *startAlert              // Events flashing before their start, nil if none
+
+	// Jump history for ctrl+o / ctrl+i, most recent last
+	jumpBack    []jumpPosition
+	jumpForward []jumpPosition
+
+	loadCenter time.Time // Date the events were loaded around, in low memory mode
+	debug      bool      // Show memory use in the status line (--debug)
 }
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/ical"
+)
+
+// vdirScheme marks calendar URLs that are vdir directories, so writes to
+// them go to files instead of the server
+const vdirScheme = "file://"
+
+// unsafeFileChars are replaced in UIDs used as file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._@-]`)
+
+// vdirProvider reads and writes a vdir: a directory with one .ics file per
+// event, as kept in sync by vdirsyncer and used by khal
+type vdirProvider struct {
+	singleCalendar
+	config *Config
+}
+
+func newVdirProvider(config *Config, entry CalendarConfig) CalendarProvider {
+	entry.URL = vdirScheme + expandHome(entry.Path)
+	return &vdirProvider{singleCalendar: singleCalendar{entry}, config: config}
+}
+
+// vdirPath returns the directory of a vdir calendar
+func vdirPath(cal CalendarConfig) string {
+	return strings.TrimPrefix(cal.URL, vdirScheme)
+}
+
+func (p *vdirProvider) FetchEvents(cal CalendarConfig, from, to, now time.Time, onRetry caldav.RetryFunc, warn func(message string)) ([]Event, error) {
+	files, err := filepath.Glob(filepath.Join(vdirPath(cal), "*.ics"))
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		if _, err := os.Stat(vdirPath(cal)); err != nil {
+			return nil, err
+		}
+	}
+
+	var events []Event
+	opts := parseOptions(p.config, cal, from, to, now, true)
+	for _, file := range files {
+		fileEvents, err := loadICSFromFile(file, cal.Name, opts)
+		if err != nil {
+			// One broken item shouldn't hide the rest of the calendar
+			warn(fmt.Sprintf("Skipped unreadable %s of %s: %v", filepath.Base(file), cal.Name, err))
+			continue
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, nil
+}
+
+// CreateEvent writes the event to a new file named after its UID
+func (p *vdirProvider) CreateEvent(cal CalendarConfig, event *Event) error {
+	if event.UID == "" {
+		event.UID = ical.NewUID()
+	}
+	return p.UpdateEvent(cal, event)
+}
+
+// UpdateEvent rewrites the file holding the event's UID, or creates one
+func (p *vdirProvider) UpdateEvent(cal CalendarConfig, event *Event) error {
+	path, err := vdirFile(vdirPath(cal), event.UID)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		path = filepath.Join(vdirPath(cal), unsafeFileChars.ReplaceAllString(event.UID, "_")+".ics")
+	}
+
+	// Write to a temporary file first so vdirsyncer never sees half a file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(ical.Build([]Event{*event})), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (p *vdirProvider) DeleteEvent(cal CalendarConfig, event Event) error {
+	path, err := vdirFile(vdirPath(cal), event.UID)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("no file for event %s in %s", event.UID, vdirPath(cal))
+	}
+	return os.Remove(path)
+}
+
+// vdirFile finds the file of the event with the given UID, empty if there
+// is none. Files are usually named after the UID, but vdirsyncer keeps the
+// server's names.
+func vdirFile(dir, uid string) (string, error) {
+	named := filepath.Join(dir, unsafeFileChars.ReplaceAllString(uid, "_")+".ics")
+	if fileUID(named) == uid {
+		return named, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.ics"))
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if fileUID(file) == uid {
+			return file, nil
+		}
+	}
+	return "", nil
+}
+
+// fileUID returns the UID of the first VEVENT in an .ics file
+func fileUID(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	// Long UIDs are folded onto continuation lines starting with a space
+	scanner := bufio.NewScanner(file)
+	uid, inUID := "", false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if inUID {
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				uid += line[1:]
+				continue
+			}
+			return uid
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.SplitN(name, ";", 2)[0], "UID") {
+			uid, inUID = value, true
+		}
+	}
+	return uid
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"mytuiapp/internal/caldav"
+	"mytuiapp/internal/ical"
+)
+
+const syncUsage = "Usage: zebracal sync [verify]"
+
+// collectionReport is what sync verify found for one calendar: the events
+// of the cached download that differ from the server's, by UID
+type collectionReport struct {
+	events          int  // On the server
+	stale           bool // The ctag changed since the download, so differences are expected
+	missingLocally  []string
+	missingRemotely []string
+	drifted         []string
+	queued          []string // Writes of the offline queue still to reach the server
+}
+
+func (r collectionReport) discrepancies() int {
+	return len(r.missingLocally) + len(r.missingRemotely) + len(r.drifted)
+}
+
+// runSyncCommand loads all calendars like refresh-cache, or runs "sync
+// verify", which compares the cached download of each CalDAV calendar with
+// the server and exits with 1 on discrepancies
+func runSyncCommand(args []string) {
+	if len(args) == 0 {
+		runRefreshCacheCommand()
+		return
+	}
+	if len(args) != 1 || args[0] != "verify" {
+		fmt.Fprintln(os.Stderr, syncUsage)
+		os.Exit(2)
+	}
+	config, _ := loadConfig()
+	if config == nil || config.Radicale == nil || config.Radicale.ServerURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: no Radicale server configured, only its calendars are cached")
+		os.Exit(1)
+	}
+	if err := setupHTTPClient(config.HTTP); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	cals, err := newRadicaleProvider(config, CalendarConfig{}).Discover(time.Now(), nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	ops, err := loadPendingOps()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to read the offline queue: %v\n", err)
+	}
+
+	client := newCalDAVClient(config.Radicale)
+	failed := false
+	for _, cal := range cals {
+		report, err := verifyCollection(client, cal.URL, ops)
+		if err != nil {
+			fmt.Printf("%s: %v\n", cal.Name, err)
+			failed = true
+			continue
+		}
+		fmt.Print(report.render(cal.Name))
+		if !report.stale && report.discrepancies() > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// verifyCollection downloads a calendar and compares it with its cached
+// download
+func verifyCollection(client *caldav.Client, calendarURL string, ops []pendingOp) (*collectionReport, error) {
+	cache, err := loadCollection(calendarURL)
+	if err != nil {
+		return nil, fmt.Errorf("unreadable cache: %v", err)
+	}
+	if cache == nil {
+		return nil, fmt.Errorf("not cached yet, nothing to verify")
+	}
+	local, err := fingerprintDocs(cache.Docs)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt cache: %v", err)
+	}
+
+	ctag, _ := client.CTag(calendarURL, nil)
+	docs, err := client.FetchCalendar(calendarURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := fingerprintDocs(docs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar data on the server: %v", err)
+	}
+
+	report := &collectionReport{events: len(remote), stale: ctag == "" || ctag != cache.CTag}
+	for uid, event := range remote {
+		cached, ok := local[uid]
+		switch {
+		case !ok:
+			report.missingLocally = append(report.missingLocally, describeUID(event.Summary, uid))
+		case cached.Hash != event.Hash:
+			report.drifted = append(report.drifted, describeUID(event.Summary, uid))
+		}
+	}
+	for uid, event := range local {
+		if _, ok := remote[uid]; !ok {
+			report.missingRemotely = append(report.missingRemotely, describeUID(event.Summary, uid))
+		}
+	}
+	for _, op := range ops {
+		if op.CalendarURL == calendarURL {
+			report.queued = append(report.queued, op.Kind+" "+describeUID(op.Event.Summary, op.Event.UID))
+		}
+	}
+	for _, list := range [][]string{report.missingLocally, report.missingRemotely, report.drifted} {
+		sort.Strings(list)
+	}
+	return report, nil
+}
+
+// fingerprintDocs fingerprints the events of a calendar's documents
+func fingerprintDocs(docs []string) (map[string]ical.Fingerprint, error) {
+	fingerprints := make(map[string]ical.Fingerprint)
+	for _, doc := range docs {
+		found, err := ical.Fingerprints(strings.NewReader(doc))
+		if err != nil {
+			return nil, err
+		}
+		for uid, fingerprint := range found {
+			fingerprints[uid] = fingerprint
+		}
+	}
+	return fingerprints, nil
+}
+
+func describeUID(summary, uid string) string {
+	return fmt.Sprintf("%s (%s)", summary, uid)
+}
+
+// render writes the report of a calendar: a line if it is in sync, the
+// differing events otherwise
+func (r collectionReport) render(name string) string {
+	var b strings.Builder
+	switch {
+	case r.discrepancies() == 0:
+		fmt.Fprintf(&b, "%s: %d events in sync\n", name, r.events)
+	case r.stale:
+		fmt.Fprintf(&b, "%s: cache is behind the server (ctag changed), zebracal refresh-cache updates it\n", name)
+	default:
+		fmt.Fprintf(&b, "%s: %d discrepancies with the server\n", name, r.discrepancies())
+	}
+	sections := []struct {
+		label  string
+		events []string
+	}{
+		{"missing locally: ", r.missingLocally},
+		{"missing remotely:", r.missingRemotely},
+		{"content drift:   ", r.drifted},
+		{"queued write:    ", r.queued},
+	}
+	for _, section := range sections {
+		for _, event := range section.events {
+			fmt.Fprintf(&b, "  %s %s\n", section.label, event)
+		}
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -18,11 +19,12 @@ func (m model) viewNaturalLanguage() string {
 	if m.naturalLangInput != "" {
 		event, err := parseNaturalLanguage(m.naturalLangInput, m.currentDate)
 		if err == nil {
+			start, end := formatDate(event.Start, "Mon Jan 2, 2006 15:04"), event.End.Format("15:04")
+			if isAllDay(*event) {
+				start, end = formatDate(event.Start, "Mon Jan 2, 2006"), tr("all day")
+			}
 			preview := fmt.Sprintf("Summary: %s\nStart: %s\nEnd: %s\nCalendar: %s",
-				event.Summary,
-				event.Start.Format("Mon Jan 2, 2006 15:04"),
-				event.End.Format("15:04"),
-				m.selectedCalendar)
+				event.Summary, start, end, m.selectedCalendar)
 			b.WriteString(eventBoxStyle.Width(60).Render(preview) + "\n")
 		} else {
 			b.WriteString(helpStyle.Render(fmt.Sprintf("Parse error: %v", err)) + "\n")
@@ -41,7 +43,7 @@ func (m model) viewLoading() string {
 	progressView := m.loadingProgress.View()
 
 	var b strings.Builder
-	b.WriteString(titleStyle.Render("Loading Calendars...") + "\n\n")
+	b.WriteString(titleStyle.Render(tr("Loading Calendars...")) + "\n\n")
 	if m.loadingMessage != "" {
 		b.WriteString(helpStyle.Render(m.loadingMessage) + "\n")
 	}
@@ -79,11 +81,17 @@ func (m model) viewEventForm() string {
 	content := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, "  ", rightColumn)
 
 	// Add help bar at the bottom
-	helpText := "Enter: confirm & next | Shift+Tab: previous | Esc: cancel"
+	helpText := "Enter: confirm & next | Shift+Tab: previous | ↑/↓: change time | Ctrl+E: description in $EDITOR | Esc: cancel"
 	helpBar := helpStyle.Render(helpText)
+	if m.formError != "" {
+		helpBar = formErrorStyle.Render(m.formError) + "\n" + helpBar
+	}
+	if m.draftPrompt {
+		helpBar = promptStyle.Render(fmt.Sprintf("Restore the unsaved draft %q? (y/n)", truncate(m.draft.Summary, 40))) + "\n" + helpBar
+	}
 
 	// Calculate available height for content (leave room for help bar)
-	availableHeight := m.height - 1
+	availableHeight := m.height - lipgloss.Height(helpBar)
 	if availableHeight < 1 {
 		availableHeight = 1
 	}
@@ -122,27 +130,38 @@ func (m model) viewEventForm() string {
 func (m model) viewDaily() string {
 	var b strings.Builder
 
-	title := titleStyle.Render("📅 Daily View")
-	b.WriteString(title + "\n")
-
 	_, week := m.currentDate.ISOWeek()
-	dateHeader := dateHeaderStyle.Render(fmt.Sprintf(
-		"%s, %s (Week %d)",
-		m.currentDate.Format("Monday"),
-		m.currentDate.Format("January 2, 2006"),
+	date := fmt.Sprintf(
+		tr("%s, %s (Week %d)"),
+		formatDate(m.currentDate, "Monday"),
+		formatDate(m.currentDate, "January 2, 2006"),
 		week,
-	))
-	b.WriteString(dateHeader + "\n")
+	)
+	if m.narrow() {
+		date = formatDate(m.currentDate, "Mon Jan 2, 2006")
+	}
+	b.WriteString(m.renderTitle(tr("📅 Daily View"), date))
+	b.WriteString(m.renderNote())
+	if busy := m.renderColleagueBusy(m.currentDate); busy != "" {
+		b.WriteString(noEventsStyle.Render(m.fitLine(busy, 2)) + "\n")
+	}
 
-	dayEvents := m.getEventsForDay(m.currentDate)
-	currentTime := time.Now()
+	dayEvents := m.dailyEvents()
+	currentTime := m.clock.Now()
+	groupByCalendar := m.config != nil && m.config.DayView != nil && m.config.DayView.GroupByCalendar
+	density := m.density()
 
+	// The events, beside the timeline in the split layout
+	var list strings.Builder
 	if len(dayEvents) == 0 {
-		b.WriteString(noEventsStyle.Render("No events scheduled for this day") + "\n")
+		list.WriteString(noEventsStyle.Render(tr("No events scheduled for this day")) + "\n")
 	} else {
 		boxWidth := 60
 		if m.width > 0 {
 			boxWidth = m.width - 10
+			if m.split() {
+				boxWidth -= timelineWidth + 2
+			}
 			if boxWidth > 80 {
 				boxWidth = 80
 			}
@@ -151,16 +170,42 @@ func (m model) viewDaily() string {
 			}
 		}
 
-		for _, event := range dayEvents {
+		first, last := m.dailyPage(len(dayEvents))
+		if first > 0 {
+			list.WriteString(noEventsStyle.Render(fmt.Sprintf(tr("↑ %d more"), first)) + "\n")
+		}
+
+		for i, event := range dayEvents {
+			if i < first || i >= last {
+				continue
+			}
+			if groupByCalendar && (i == first || dayEvents[i-1].CalendarName != event.CalendarName) {
+				groupHeader := lipgloss.NewStyle().
+					Bold(true).
+					Foreground(eventColor(event)).
+					Padding(0, 1)
+				list.WriteString(groupHeader.Render(event.CalendarName) + "\n")
+			}
+
 			isNow := m.currentDate.Format("2006-01-02") == currentTime.Format("2006-01-02") &&
 				currentTime.After(event.Start) && currentTime.Before(event.End)
 
+			marker := eventMarker(event)
+			if m.selected[eventKey(event)] {
+				marker = "✓ "
+			}
+			if !m.oneShot && i == m.cursor {
+				marker = "▸ " + marker
+			}
+
+			if density == "compact" {
+				list.WriteString(m.renderCompactEvent(event, marker, isNow) + "\n")
+				continue
+			}
+
 			var boxContent strings.Builder
 
-			timeStr := fmt.Sprintf("%s - %s",
-				event.Start.Format("15:04"),
-				event.End.Format("15:04"),
-			)
+			timeStr := timeRange(event)
 			duration := event.End.Sub(event.Start)
 			durationStr := ""
 			if duration >= time.Hour {
@@ -169,29 +214,45 @@ func (m model) viewDaily() string {
 				durationStr = fmt.Sprintf(" (%dm)", int(duration.Minutes()))
 			}
 
+			dayView := m.dayViewConfig()
+			if dayView.showCalendarName() || len(event.AlsoIn) > 0 {
+				durationStr += " · " + calendarLabel(event)
+			}
+			if cost := m.eventCost(event); cost != "" {
+				durationStr += " · " + cost
+			}
 			timeLineStyle := timeStyle.Foreground(lipgloss.Color("241"))
 			boxContent.WriteString(timeLineStyle.Render(timeStr+durationStr) + "\n")
 
 			titleStyle := lipgloss.NewStyle().
-				Foreground(event.CalendarColor).
+				Foreground(eventColor(event)).
 				Bold(true)
-			boxContent.WriteString(titleStyle.Render("● " + event.Summary))
+			if m.flashing(event) {
+				titleStyle = titleStyle.Reverse(true)
+			}
+			boxContent.WriteString(titleStyle.Render(truncate(marker+m.eventTitle(event), boxWidth-4)))
+
+			if habit := m.renderHabit(event); habit != "" {
+				boxContent.WriteString("\n" + habit)
+			}
 
-			if event.Description != "" && strings.TrimSpace(event.Description) != "" {
+			if location := m.eventLocation(event); dayView.showLocation() && location != "" {
+				locationStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+				boxContent.WriteString("\n" + locationStyle.Render(truncate("📍 "+location, boxWidth-4)))
+			}
+
+			if description := m.eventDescription(event); dayView.showDescription() && strings.TrimSpace(description) != "" {
 				descStyle := lipgloss.NewStyle().
 					Foreground(lipgloss.Color("245")).
 					Italic(true).
 					Width(boxWidth - 4)
 
-				desc := strings.TrimSpace(event.Description)
-				if len(desc) > 150 {
-					desc = desc[:150] + "..."
-				}
-				boxContent.WriteString("\n" + descStyle.Render(desc))
+				desc := wrapText(strings.TrimSpace(description), boxWidth-4, maxDescriptionLines)
+				boxContent.WriteString("\n" + descStyle.Render(strings.Join(desc, "\n")))
 			}
 
 			boxStyle := eventBoxStyle.
-				BorderForeground(event.CalendarColor).
+				BorderForeground(eventColor(event)).
 				Width(boxWidth)
 
 			if isNow {
@@ -199,132 +260,438 @@ func (m model) viewDaily() string {
 					BorderForeground(lipgloss.Color("205")).
 					BorderStyle(lipgloss.ThickBorder())
 			}
+			if m.selected[eventKey(event)] {
+				boxStyle = boxStyle.BorderStyle(lipgloss.DoubleBorder())
+			}
+			if m.flashing(event) {
+				boxStyle = boxStyle.BorderForeground(lipgloss.Color("229"))
+			}
+			if density == "spacious" {
+				boxStyle = boxStyle.Padding(1, 2).MarginBottom(1)
+			}
 
-			b.WriteString(boxStyle.Render(boxContent.String()) + "\n")
+			list.WriteString(boxStyle.Render(boxContent.String()) + "\n")
 		}
+
+		if last < len(dayEvents) {
+			list.WriteString(noEventsStyle.Render(fmt.Sprintf(tr("↓ %d more"), len(dayEvents)-last)) + "\n")
+		}
+	}
+
+	if m.split() && len(dayEvents) > 0 {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, m.renderTimeline(dayEvents), "  ", strings.TrimSuffix(list.String(), "\n")) + "\n")
+	} else {
+		b.WriteString(list.String())
 	}
 
 	if !m.oneShot {
-		b.WriteString(m.renderCalendarLegend())
-		b.WriteString("\n" + helpStyle.Render("d: daily  w: weekly  m: monthly  |  ← →: navigate  t: today  |  n: new event  |  q: quit"))
+		b.WriteString(m.renderFooter(
+			[]string{"d: daily", "w: weekly", "m: monthly", "g: rolling"},
+			[]string{"← →: navigate", "t: today", "^o/^i: jump back/forward", "r: refresh", "Z: redact", "u: mine", "1-9: calendars", "G: groups"},
+			[]string{"j/k: move", "enter: details", "space/V: select", "D/C/</>/E: bulk", "x: done", "*: pin"},
+			[]string{"n: new event", "o: new after focused", "b/B: focus", "T: plan tasks", "F: free time", "J: note", "p: timer", "f: zen"},
+			[]string{"q: quit"},
+		))
 
 		if m.err != nil {
-			b.WriteString("\n" + helpStyle.Render("Note: Using sample data (no calendars found)"))
+			b.WriteString("\n" + helpStyle.Render(tr("Note: Using sample data (no calendars found)")))
 		}
 	}
 
 	return b.String()
 }
 
+// dailyPage returns the range of the day's events that fits the terminal,
+// scrolled to keep the cursor visible. One-shot output shows all events.
+func (m model) dailyPage(count int) (first, last int) {
+	if m.oneShot || m.height == 0 {
+		return 0, count
+	}
+
+	linesPerEvent := 4 // Border, time and title
+	switch m.density() {
+	case "compact":
+		linesPerEvent = 1
+	case "spacious":
+		linesPerEvent = 7
+	}
+	// Leave room for the headers, legend, status and help
+	overhead := 16
+	if m.short() {
+		overhead = 6
+	}
+	size := max(1, (m.height-overhead)/linesPerEvent)
+	if count <= size {
+		return 0, count
+	}
+
+	first = max(0, m.cursor-size/2)
+	last = min(count, first+size)
+	first = max(0, last-size)
+	return first, last
+}
+
+// density returns the configured event density: "compact", "normal" or "spacious"
+func (m model) density() string {
+	if m.short() {
+		return "compact"
+	}
+	if m.config != nil && m.config.Density != "" {
+		return m.config.Density
+	}
+	return "normal"
+}
+
+// renderCompactEvent renders an event as a single line (time + title, no box)
+func (m model) renderCompactEvent(event Event, marker string, isNow bool) string {
+	timeStr := " " + timeRange(event) + " "
+	lineTimeStyle := timeStyle
+	if isNow {
+		lineTimeStyle = lineTimeStyle.Foreground(lipgloss.Color("205"))
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(eventColor(event))
+	if m.selected[eventKey(event)] {
+		titleStyle = titleStyle.Bold(true)
+	}
+	if m.flashing(event) {
+		titleStyle = titleStyle.Reverse(true)
+	}
+	dayView := m.dayViewConfig()
+	details := m.eventDetails(event, dayView.showLocation(), dayView.showCalendarName())
+	title := m.fitLine(marker+m.eventTitle(event)+details, lipgloss.Width(timeStr))
+	return lineTimeStyle.Render(timeStr) + titleStyle.Render(title)
+}
+
 func (m model) viewWeekly() string {
 	var b strings.Builder
 
-	title := titleStyle.Render("📅 Weekly View")
-	b.WriteString(title + "\n")
-
 	weekStart := m.getWeekStart(m.currentDate)
 	_, week := weekStart.ISOWeek()
 
-	dateHeader := dateHeaderStyle.Render(fmt.Sprintf(
-		"Week %d - %s to %s",
+	b.WriteString(m.renderTitle(tr("📅 Weekly View"), fmt.Sprintf(
+		tr("Week %d - %s to %s"),
 		week,
-		weekStart.Format("Jan 2"),
-		weekStart.AddDate(0, 0, 6).Format("Jan 2, 2006"),
-	))
-	b.WriteString(dateHeader + "\n")
+		formatDate(weekStart, "Jan 2"),
+		formatDate(weekStart.AddDate(0, 0, 6), "Jan 2, 2006"),
+	)))
+	if !m.short() {
+		b.WriteString(m.renderWeekLoad(weekStart) + "\n")
+	}
 
-	for i := 0; i < 7; i++ {
+	// All-day and multi-day events get a band of their own, except in the
+	// single column of short terminals
+	var days [7][]Event
+	busyDays := 0
+	for i := range days {
+		days[i] = m.getEventsForDay(weekStart.AddDate(0, 0, i))
+		if !m.short() {
+			days[i] = withoutSpanning(days[i])
+		}
+		if len(days[i]) > 0 {
+			busyDays++
+		}
+	}
+	colWidth := m.weekBandColumnWidth()
+	if band := m.renderBand(weekStart, 7, colWidth); band != "" && !m.short() {
+		headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Width(colWidth)
+		var header strings.Builder
+		for _, day := range weekdayHeaders() {
+			header.WriteString(headerStyle.Render(" " + day))
+		}
+		b.WriteString("\n" + header.String() + "\n" + band + "\n")
+	}
+
+	weekView := m.weekViewConfig()
+	headerFormat, perDay := "Monday, Jan 2", maxWeekEventsPerDay
+	// Without a line for an event of each busy day, days get a line each
+	oneLine := m.short() && m.height-3 < 7+busyDays
+	if m.short() {
+		// Share the lines left below the day headers, date and help out
+		// among the busy days, keeping one for "… more"
+		headerFormat, perDay = "Mon Jan 2", max(1, (m.height-3-7)/max(1, busyDays)-1)
+	}
+	for i, dayEvents := range days {
 		day := weekStart.AddDate(0, 0, i)
-		dayEvents := m.getEventsForDay(day)
+		// The focused day is the current date, moved with h/l
+		focused := !m.oneShot && sameDay(day, m.currentDate)
+		expanded := !m.expandedDay.IsZero() && sameDay(day, m.expandedDay)
+		if oneLine && !expanded {
+			line := m.renderDayLine(day, dayEvents)
+			if focused {
+				line = "▸" + strings.TrimPrefix(line, " ")
+			}
+			b.WriteString(line + "\n")
+			continue
+		}
 
-		dayHeader := lipgloss.NewStyle().
+		headerStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("117")).
-			Render(day.Format("Monday, Jan 2"))
+			Foreground(lipgloss.Color("117"))
+		header := formatDate(day, headerFormat)
+		if focused {
+			headerStyle = headerStyle.Underline(true)
+			header = "▸ " + header
+		}
+		dayHeader := headerStyle.Render(header)
 
-		b.WriteString("\n" + dayHeader + "\n")
+		if m.short() {
+			b.WriteString(dayHeader)
+			if len(dayEvents) == 0 {
+				b.WriteString(noEventsStyle.Render(" –"))
+			}
+			b.WriteString("\n")
+		} else {
+			b.WriteString("\n" + dayHeader + "\n")
+		}
 
 		if len(dayEvents) == 0 {
-			b.WriteString(noEventsStyle.Render("  No events") + "\n")
+			if !m.short() {
+				b.WriteString(noEventsStyle.Render(tr("  No events")) + "\n")
+			}
 		} else {
-			for _, event := range dayEvents {
-				timeStr := fmt.Sprintf("  %s - %s",
-					event.Start.Format("15:04"),
-					event.End.Format("15:04"),
-				)
+			for i, event := range dayEvents {
+				if i == perDay && len(dayEvents) > perDay+1 && !expanded {
+					b.WriteString(noEventsStyle.Render(fmt.Sprintf(tr("  … %d more"), len(dayEvents)-i)) + "\n")
+					break
+				}
+				timeStr := "  " + event.Start.Format("15:04")
+				if isAllDay(event) {
+					timeStr = "  " + tr("all day")
+				} else if weekView.showEndTime() {
+					timeStr += " - " + event.End.Format("15:04")
+				}
 				b.WriteString(timeStyle.Render(timeStr))
 
 				eventStyle := lipgloss.NewStyle().
-					Foreground(event.CalendarColor).
+					Foreground(eventColor(event)).
 					MarginLeft(2)
+				if m.flashing(event) {
+					eventStyle = eventStyle.Reverse(true)
+				}
 
-				b.WriteString(eventStyle.Render(fmt.Sprintf("● %s", event.Summary)))
+				details := m.eventDetails(event, weekView.showLocation(), weekView.showCalendarName())
+				title := m.fitLine(eventMarker(event)+m.eventTitle(event)+details, lipgloss.Width(timeStr)+2)
+				b.WriteString(eventStyle.Render(title))
 				b.WriteString("\n")
+				if expanded {
+					// Under the title, past the time, margin and bullet
+					b.WriteString(m.renderEventExpansion(event, lipgloss.Width(timeStr)+4, weekView.showLocation()))
+				}
 			}
 		}
 	}
 
 	if !m.oneShot {
-		b.WriteString(m.renderCalendarLegend())
-		b.WriteString("\n" + helpStyle.Render("d: daily  w: weekly  m: monthly  |  ← →: navigate  t: today  |  n: new event  |  q: quit"))
+		b.WriteString(m.renderFooter(
+			[]string{"d: daily", "w: weekly", "m: monthly", "g: rolling"},
+			[]string{"← →: navigate", "h/l: focus day", "t: today", "^o/^i: jump back/forward"},
+			[]string{"enter: expand/open day", "n/o: new event", "1-9: calendars"},
+			[]string{"q: quit"},
+		))
 	}
 
 	return b.String()
 }
 
-func (m model) viewMonthly() string {
+// renderEventExpansion renders the location and description lines shown
+// below an event of the expanded day in the weekly view
+func (m model) renderEventExpansion(event Event, indent int, locationShown bool) string {
+	width := 60
+	if m.width > 0 {
+		width = max(20, m.width-indent-2)
+	}
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).MarginLeft(indent)
+
 	var b strings.Builder
+	if location := m.eventLocation(event); location != "" && !locationShown {
+		b.WriteString(detailStyle.Render(truncate("📍 "+location, width)) + "\n")
+	}
+	if description := strings.TrimSpace(m.eventDescription(event)); description != "" {
+		// Blank lines between paragraphs would break up the list of the day
+		var lines []string
+		for _, line := range wrapText(description, width, maxDescriptionLines) {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		b.WriteString(detailStyle.Italic(true).Render(strings.Join(lines, "\n")) + "\n")
+	}
+	return b.String()
+}
 
-	title := titleStyle.Render("📅 Monthly View")
-	b.WriteString(title + "\n")
+// maxWeekEventsPerDay keeps busy days from pushing the rest of the week
+// off screen
+const maxWeekEventsPerDay = 8
 
-	dateHeader := dateHeaderStyle.Render(m.currentDate.Format("January 2006"))
-	b.WriteString(dateHeader + "\n")
+// sparkBlocks are the sparkline levels from lightest to heaviest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
 
-	weekdays := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
-	var headerRow strings.Builder
-	for _, day := range weekdays {
-		headerRow.WriteString(weekdayHeaderStyle.Render(day))
+// renderWeekLoad renders a sparkline of booked hours per day of the week
+func (m model) renderWeekLoad(weekStart time.Time) string {
+	var hours [7]float64
+	maxHours := 0.0
+	for i := 0; i < 7; i++ {
+		day := weekStart.AddDate(0, 0, i)
+		for _, event := range m.getEventsForDay(day) {
+			if !transparent(event) {
+				hours[i] += dayDuration(event, day).Hours()
+			}
+		}
+		if hours[i] > maxHours {
+			maxHours = hours[i]
+		}
 	}
-	b.WriteString(headerRow.String() + "\n")
 
-	firstDay := time.Date(m.currentDate.Year(), m.currentDate.Month(), 1, 0, 0, 0, 0, time.Local)
-	lastDay := time.Date(m.currentDate.Year(), m.currentDate.Month()+1, 0, 0, 0, 0, 0, time.Local)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	headers := weekdayHeaders()
+	var labels, bars, totals strings.Builder
+	for i, h := range hours {
+		bar := " "
+		if h > 0 {
+			level := int(h / maxHours * float64(len(sparkBlocks)-1))
+			bar = string(sparkBlocks[level])
+		}
+		barStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("117"))
+		if h >= 8 {
+			barStyle = barStyle.Foreground(lipgloss.Color("205"))
+		}
+		bars.WriteString(barStyle.Render(fmt.Sprintf("%-4s", strings.Repeat(bar, 2))))
+		labels.WriteString(fmt.Sprintf("%-4s", headers[i]))
+		totals.WriteString(fmt.Sprintf("%-4s", fmt.Sprintf("%.0fh", h)))
+	}
 
-	startWeekday := int(firstDay.Weekday())
-	if startWeekday == 0 {
-		startWeekday = 7
+	load := " " + dimStyle.Render(labels.String()) + "\n " + bars.String() + "\n " + dimStyle.Render(totals.String())
+	if cost := m.weekCost(weekStart); cost > 0 {
+		load += "\n " + dimStyle.Render(fmt.Sprintf(tr("Meetings this week: %s"), m.config.formatCost(cost)))
 	}
-	startWeekday--
+	return load
+}
+
+func (m model) viewMonthly() string {
+	var b strings.Builder
 
-	day := 1
-	today := time.Now()
+	b.WriteString(m.renderTitle(tr("📅 Monthly View"), formatDate(m.currentDate, "January 2006")))
 
-	for week := 0; week < 6; week++ {
-		var row []string
-		for weekday := 0; weekday < 7; weekday++ {
-			if (week == 0 && weekday < startWeekday) || day > lastDay.Day() {
-				row = append(row, cellStyle.Render(""))
-			} else {
-				cellDate := time.Date(m.currentDate.Year(), m.currentDate.Month(), day, 0, 0, 0, 0, time.Local)
-				cell := m.renderMonthCell(cellDate, today)
-				row = append(row, cell)
-				day++
-			}
+	firstDay := time.Date(m.currentDate.Year(), m.currentDate.Month(), 1, 0, 0, 0, 0, time.Local)
+	if weeks := monthWeeks(firstDay); m.smallGrid(len(weeks)) {
+		b.WriteString(m.renderSmallGrid(weeks))
+	} else {
+		var headerRow strings.Builder
+		for _, day := range weekdayHeaders() {
+			headerRow.WriteString(weekdayHeaderStyle.Render(day))
 		}
-		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...) + "\n")
+		b.WriteString(headerRow.String() + "\n")
+
+		lastDay := time.Date(m.currentDate.Year(), m.currentDate.Month()+1, 0, 0, 0, 0, 0, time.Local)
 
-		if day > lastDay.Day() {
-			break
+		startWeekday := int(firstDay.Weekday())
+		if startWeekday == 0 {
+			startWeekday = 7
+		}
+		startWeekday--
+
+		day := 1
+		today := m.clock.Now()
+
+		for week := 0; week < 6; week++ {
+			var row []string
+			for weekday := 0; weekday < 7; weekday++ {
+				if (week == 0 && weekday < startWeekday) || day > lastDay.Day() {
+					row = append(row, cellStyle.Render(""))
+				} else {
+					cellDate := time.Date(m.currentDate.Year(), m.currentDate.Month(), day, 0, 0, 0, 0, time.Local)
+					cell := m.renderMonthCell(cellDate, today)
+					row = append(row, cell)
+					day++
+				}
+			}
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...) + "\n")
+
+			if day > lastDay.Day() {
+				break
+			}
 		}
 	}
 
 	if !m.oneShot {
-		b.WriteString(m.renderCalendarLegend())
 		if m.dayInput != "" {
-			b.WriteString("\n" + helpStyle.Render(fmt.Sprintf("Jump to day: %s (press Enter)", m.dayInput)))
+			b.WriteString(helpStyle.Render(fmt.Sprintf(tr("Jump to day: %s (press Enter)"), strings.TrimPrefix(m.dayInput, ":"))) + "\n")
+		}
+		b.WriteString(m.renderFooter(
+			[]string{"d: daily", "w: weekly", "m: monthly", "g: rolling"},
+			[]string{"← →: navigate", "t: today", "^o/^i: jump back/forward"},
+			[]string{":day + Enter: jump", "1-9: calendars"},
+			[]string{"n/o: new event"},
+			[]string{"q: quit"},
+		))
+	}
+
+	return b.String()
+}
+
+// defaultRollingWeeks is the length of the rolling view without config
+const defaultRollingWeeks = 2
+
+// rollingWeeks returns the number of weeks shown in the rolling view
+func (m model) rollingWeeks() int {
+	if m.config != nil && m.config.RollingWeeks > 0 {
+		return m.config.RollingWeeks
+	}
+	return defaultRollingWeeks
+}
+
+// viewRolling shows a grid of weeks starting with the current one. Unlike
+// the monthly view it doesn't stop at the end of the month.
+func (m model) viewRolling() string {
+	var b strings.Builder
+
+	weekStart := m.getWeekStart(m.currentDate)
+	weeks := m.rollingWeeks()
+	weekEnd := weekStart.AddDate(0, 0, 7*weeks-1)
+	b.WriteString(m.renderTitle(tr("📅 Rolling View"), fmt.Sprintf(
+		tr("%s to %s"),
+		formatDate(weekStart, "Jan 2"),
+		formatDate(weekEnd, "Jan 2, 2006"),
+	)))
+
+	if m.smallGrid(weeks) {
+		// All-day events are counted in the days instead of a band
+		var days [][]time.Time
+		for week := 0; week < weeks; week++ {
+			days = append(days, make([]time.Time, 7))
+			for weekday := range days[week] {
+				days[week][weekday] = weekStart.AddDate(0, 0, 7*week+weekday)
+			}
+		}
+		b.WriteString(m.renderSmallGrid(days))
+	} else {
+		var headerRow strings.Builder
+		for _, day := range weekdayHeaders() {
+			headerRow.WriteString(weekdayHeaderStyle.Render(day))
 		}
-		b.WriteString("\n" + helpStyle.Render("d: daily  w: weekly  m: monthly  |  ← →: navigate  t: today  |  0-9 + Enter: jump  |  n: new event  |  q: quit"))
+		b.WriteString(headerRow.String() + "\n")
+
+		today := m.clock.Now()
+		for week := 0; week < weeks; week++ {
+			if band := m.renderBand(weekStart.AddDate(0, 0, 7*week), 7, bandColumnWidth); band != "" {
+				b.WriteString(band + "\n")
+			}
+			var row []string
+			for weekday := 0; weekday < 7; weekday++ {
+				row = append(row, m.renderMonthCell(weekStart.AddDate(0, 0, 7*week+weekday), today))
+			}
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...) + "\n")
+		}
+	}
+
+	if !m.oneShot {
+		b.WriteString(m.renderFooter(
+			[]string{"d: daily", "w: weekly", "m: monthly", "g: rolling"},
+			[]string{"← →: navigate", "h/l: focus day", "t: today", "^o/^i: jump back/forward"},
+			[]string{"enter: expand/open day", "n/o: new event", "1-9: calendars"},
+			[]string{"q: quit"},
+		))
 	}
 
 	return b.String()
@@ -338,26 +705,44 @@ func (m model) renderMonthCell(date time.Time, today time.Time) string {
 	if isToday {
 		dayStyle = dayStyle.Foreground(lipgloss.Color("205"))
 	}
-	content.WriteString(dayStyle.Render(fmt.Sprintf("%2d", date.Day())) + "\n")
+	dayLabel := fmt.Sprintf("%2d", date.Day())
+	if m.viewMode == RollingView && date.Day() == 1 {
+		// Make the month boundary stand out
+		dayLabel = formatDate(date, "Jan 2")
+	}
+	dayEvents := m.getEventsForDay(date)
+	if color, ok := m.heatColor(dayEvents, date); ok {
+		dayStyle = dayStyle.Background(color)
+		if !isToday {
+			dayStyle = dayStyle.Foreground(lipgloss.Color("255"))
+		}
+	}
+	content.WriteString(dayStyle.Render(dayLabel) + "\n")
 
 	durationPerCalendar := make(map[string]time.Duration)
 	hasEventsPerCalendar := make(map[string]bool)
-	dayEvents := m.getEventsForDay(date)
+	if m.viewMode == RollingView {
+		// Shown in the band above the week instead
+		dayEvents = withoutSpanning(dayEvents)
+	}
 
 	for _, event := range dayEvents {
-		duration := event.End.Sub(event.Start)
+		if transparent(event) {
+			// Free time doesn't fill the bars
+			continue
+		}
+		duration := dayDuration(event, date)
 		durationPerCalendar[event.CalendarName] += duration
 		hasEventsPerCalendar[event.CalendarName] = true
 	}
 
 	if len(hasEventsPerCalendar) > 0 {
 		var calNames []string
-		for name := range m.calendars {
+		for _, name := range m.sortedCalendarNames() {
 			if hasEventsPerCalendar[name] {
 				calNames = append(calNames, name)
 			}
 		}
-		sort.Strings(calNames)
 
 		maxHeight := 2
 		barHeights := make([]int, len(calNames))
@@ -399,24 +784,110 @@ func (m model) renderMonthCell(date time.Time, today time.Time) string {
 	return style.Render(content.String())
 }
 
-func (m model) renderCalendarLegend() string {
-	var b strings.Builder
-	b.WriteString(calendarLabelStyle.Render("Calendars:") + "\n")
-	for name, color := range m.calendars {
-		legendStyle := lipgloss.NewStyle().
-			Foreground(color).
-			Padding(0, 1)
-		b.WriteString(legendStyle.Render(fmt.Sprintf("● %s", name)))
+// heatColor picks the day number's background from the time booked that
+// day relative to the working day, or false for a day without busy time
+func (m model) heatColor(events []Event, date time.Time) (lipgloss.Color, bool) {
+	if !m.monthViewConfig().showHeatmap() {
+		return "", false
 	}
-	return b.String()
+	booked := bookedTime(events, date)
+	if booked <= 0 {
+		return "", false
+	}
+	var workingHours *WorkingHoursConfig
+	if m.config != nil {
+		workingHours = m.config.WorkingHours
+	}
+	load := float64(booked) / float64(workingHours.dayLength())
+	if load > 1 {
+		return heatColors[len(heatColors)-1], true
+	}
+	// A step per quarter of the working day
+	return heatColors[int(math.Ceil(load*4))-1], true
+}
+
+// eventTitle returns the summary to display, masked in redact mode
+func (m model) eventTitle(event Event) string {
+	if m.redact {
+		return tr(redactedTitle)
+	}
+	if label := anniversaryLabel(event); label != "" {
+		return event.Summary + " (" + label + ")"
+	}
+	return event.Summary
+}
+
+// fitLine truncates s to the rest of a terminal line after used cells. The
+// width is unknown for one-shot output, which is left as is.
+func (m model) fitLine(s string, used int) string {
+	if m.width <= 0 {
+		return s
+	}
+	return truncate(s, m.width-used)
+}
+
+// eventDescription returns the description to display as plain text, masked
+// in redact mode
+func (m model) eventDescription(event Event) string {
+	if m.redact && event.Description != "" {
+		return tr(redactedDescription)
+	}
+	return plainDescription(event.Description)
+}
+
+// renderStatusLine shows a pending confirmation prompt, the selection size and
+// the last status message
+func (m model) renderStatusLine() string {
+	if m.pendingBulk != nil {
+		return "\n" + promptStyle.Render(m.pendingBulk.prompt())
+	}
+	if len(m.conflicts) > 0 {
+		return "\n" + promptStyle.Render(m.conflicts[0].prompt())
+	}
+	if m.confirmQuit > 0 {
+		return "\n" + promptStyle.Render(quitPrompt(m.confirmQuit))
+	}
+	if m.groupPrompt {
+		return "\n" + promptStyle.Render(m.groupTogglePrompt())
+	}
+
+	if m.creating != nil {
+		bar := m.loadingProgress
+		bar.Width = 30
+		fraction := float64(m.creating.done) / float64(max(1, m.creating.total))
+		return "\n" + helpStyle.Render(fmt.Sprintf(tr("Creating events %d/%d"), m.creating.done, m.creating.total)+"  "+
+			bar.ViewAs(fraction)+"  "+tr("esc: cancel"))
+	}
+
+	var parts []string
+	if m.redact {
+		parts = append(parts, tr("REDACTED"))
+	}
+	if m.pendingCount > 0 {
+		parts = append(parts, pendingBadge(m.pendingCount))
+	}
+	if len(m.selected) > 0 {
+		parts = append(parts, fmt.Sprintf(tr("%d selected"), len(m.selected)))
+	}
+	if m.debug {
+		parts = append(parts, memoryStats(len(m.events)))
+	}
+	if m.message != "" {
+		parts = append(parts, m.message)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n" + helpStyle.Render(strings.Join(parts, "  |  "))
 }
 
 func (m model) getEventsForDay(date time.Time) []Event {
 	var dayEvents []Event
 	for _, event := range m.events {
-		if event.Start.Year() == date.Year() &&
-			event.Start.Month() == date.Month() &&
-			event.Start.Day() == date.Day() {
+		if !m.visible(event) {
+			continue
+		}
+		if onDay(event, date) {
 			dayEvents = append(dayEvents, event)
 		}
 	}
@@ -428,6 +899,39 @@ func (m model) getEventsForDay(date time.Time) []Event {
 	return dayEvents
 }
 
+// dailyEvents returns the current day's events in the configured daily view
+// order, pinned ones first. When grouping by calendar, events of one
+// calendar are contiguous.
+func (m model) dailyEvents() []Event {
+	dayEvents := m.getEventsForDay(m.currentDate)
+
+	var sortBy string
+	var group bool
+	if m.config != nil && m.config.DayView != nil {
+		sortBy = m.config.DayView.Sort
+		group = m.config.DayView.GroupByCalendar
+	}
+
+	sort.SliceStable(dayEvents, func(i, j int) bool {
+		a, b := dayEvents[i], dayEvents[j]
+		if (group || sortBy == "calendar") && a.CalendarName != b.CalendarName {
+			return a.CalendarName < b.CalendarName
+		}
+		if isPinned(a) != isPinned(b) {
+			return isPinned(a)
+		}
+		if sortBy == "duration" {
+			da, db := a.End.Sub(a.Start), b.End.Sub(b.Start)
+			if da != db {
+				return da > db
+			}
+		}
+		return a.Start.Before(b.Start)
+	})
+
+	return dayEvents
+}
+
 func (m model) getWeekStart(date time.Time) time.Time {
 	weekday := int(date.Weekday())
 	if weekday == 0 {
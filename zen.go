@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type zenTickMsg struct{}
+
+func scheduleZenTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return zenTickMsg{}
+	})
+}
+
+// bigDigits draw the countdown of the zen screen, five rows per glyph
+var bigDigits = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" █ ", "██ ", " █ ", " █ ", "███"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// renderBigText renders digits and colons in bigDigits, each cell two
+// columns wide so the glyphs aren't squeezed
+func renderBigText(text string) string {
+	var rows [5]strings.Builder
+	for i, r := range text {
+		glyph, ok := bigDigits[r]
+		if !ok {
+			continue
+		}
+		for row := range rows {
+			if i > 0 {
+				rows[row].WriteString("  ")
+			}
+			for _, cell := range glyph[row] {
+				rows[row].WriteString(strings.Repeat(string(cell), 2))
+			}
+		}
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = rows[i].String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startZen opens the zen screen (f): the current event, a large countdown
+// to its end and the next event, for a secondary monitor
+func (m model) startZen() (model, tea.Cmd) {
+	m.zen = true
+	return m, scheduleZenTick()
+}
+
+// handleZenKey lets esc, f or q close the zen screen
+func (m model) handleZenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc", "f", "q":
+		m.zen = false
+	}
+	return m, nil
+}
+
+// zenEvents returns the busy event running now, ending first, and the next
+// one to start. Either is nil if there is none.
+func (m model) zenEvents(now time.Time) (current, next *Event) {
+	var events []Event
+	for _, event := range m.events {
+		if busy(event) && m.visible(event) {
+			events = append(events, event)
+		}
+	}
+	for _, event := range events {
+		if event.Start.After(now) || !event.End.After(now) {
+			continue
+		}
+		if current == nil || event.End.Before(current.End) {
+			current = &event
+		}
+	}
+	return current, getNextEvent(events, now)
+}
+
+// viewZen renders the zen screen centered in the terminal
+func (m model) viewZen() string {
+	now := m.clock.Now()
+	current, next := m.zenEvents(now)
+	width := 60
+	if m.width > 0 {
+		width = m.width
+	}
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	countdownStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229"))
+
+	var lines []string
+	switch {
+	case current != nil:
+		title := truncate(m.eventTitle(*current), width-2)
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(eventColor(*current)).Bold(true).Render(title), "",
+			countdownStyle.Render(renderBigText(formatRemaining(current.End.Sub(now)))), "",
+			dimStyle.Render(fmt.Sprintf(tr("until %s"), current.End.Format("15:04"))))
+	case next != nil && sameDay(next.Start, now):
+		// Free until the next event
+		lines = append(lines,
+			dimStyle.Render(tr("Free")), "",
+			countdownStyle.Render(renderBigText(formatRemaining(next.Start.Sub(now)))), "",
+			dimStyle.Render(fmt.Sprintf(tr("until %s"), next.Start.Format("15:04"))))
+	default:
+		lines = append(lines, dimStyle.Render(tr("Nothing scheduled for the rest of the day")))
+	}
+
+	if next != nil {
+		when := next.Start.Format("15:04")
+		if !sameDay(next.Start, now) {
+			when = formatDate(next.Start, "Mon Jan 2") + " " + when
+		}
+		lines = append(lines, "", dimStyle.Render(truncate(fmt.Sprintf(tr("Next: %s at %s"), m.eventTitle(*next), when), width-2)))
+	}
+
+	screen := lipgloss.JoinVertical(lipgloss.Center, lines...)
+	if m.width == 0 || m.height == 0 {
+		return screen
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, screen)
+}